@@ -49,4 +49,5 @@ func init() {
 	Cmd.AddCommand(KvmCmd)
 	Cmd.AddCommand(UpdateCmd)
 	Cmd.AddCommand(CloneCmd)
+	Cmd.AddCommand(SpecCmd)
 }
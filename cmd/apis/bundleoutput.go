@@ -0,0 +1,93 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"internal/clilog"
+
+	proxybundle "internal/bundlegen/proxybundle"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputDir   string
+	mergeBundle bool
+	templateDir string
+)
+
+// addOutputFlags adds the flags to write the generated API proxy as a folder
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "",
+		"", "Write the generated apiproxy folder to this folder instead of a zip file")
+	cmd.Flags().BoolVarP(&mergeBundle, "merge", "",
+		false, "Merge with the apiproxy folder in output-dir, preserving hand added policies, resources and endpoint edits")
+}
+
+// addTemplateFlag adds the flag to customize the generated API proxy with templates
+func addTemplateFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&templateDir, "template-dir", "",
+		"", "Folder with policy templates (<Kind>.xml or <Policy name>.xml), policies/, resources/ and "+
+			proxybundle.StepsFileName+" to customize the generated API proxy")
+}
+
+func validateOutputFlags() error {
+	if mergeBundle && outputDir == "" {
+		return fmt.Errorf("merge requires output-dir")
+	}
+	if templateDir != "" {
+		if info, err := os.Stat(templateDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("template-dir %s is not a folder", templateDir)
+		}
+	}
+	return nil
+}
+
+// generateDir returns the folder the API proxy is generated to. With merge,
+// the API proxy is generated to a temporary folder and merged by finishBundle
+func generateDir(proxyOutputDir string) (string, error) {
+	if proxyOutputDir == "" || !mergeBundle {
+		return proxyOutputDir, nil
+	}
+	return os.MkdirTemp("", "generated")
+}
+
+// finishBundle merges the generated API proxy into proxyOutputDir and archives
+// the folder when the API proxy is imported. Merge conflicts are returned.
+func finishBundle(name string, generatedDir string, proxyOutputDir string) (conflicts []string, err error) {
+	if proxyOutputDir == "" {
+		return nil, nil
+	}
+	if generatedDir != proxyOutputDir {
+		defer os.RemoveAll(generatedDir)
+		if conflicts, err = proxybundle.MergeAPIProxy(generatedDir, proxyOutputDir); err != nil {
+			return conflicts, err
+		}
+	}
+	if importProxy {
+		err = proxybundle.GenerateArchiveBundle(filepath.Join(proxyOutputDir, "apiproxy"), name+".zip")
+	}
+	return conflicts, err
+}
+
+func printConflicts(conflicts []string) {
+	for _, conflict := range conflicts {
+		clilog.Warning.Println(conflict)
+	}
+}
@@ -59,7 +59,7 @@ var CloneCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		if proxyZip != "" {
 			// extract the zip to a tmp folder and assign to proxyFolder
-			if proxyFolder, err = unzipBundle(); err != nil {
+			if proxyFolder, err = unzipBundle(proxyZip); err != nil {
 				return err
 			}
 		}
@@ -205,13 +205,13 @@ func setParam(filePath string, paramType string) (err error) {
 	return nil
 }
 
-func unzipBundle() (tmpDir string, err error) {
+func unzipBundle(zipPath string) (tmpDir string, err error) {
 	tmpDir, err = os.MkdirTemp("", "proxy")
 	if err != nil {
 		return tmpDir, err
 	}
 
-	bundle, err := zip.OpenReader(proxyZip)
+	bundle, err := zip.OpenReader(zipPath)
 	if err != nil {
 		return tmpDir, err
 	}
@@ -37,4 +37,5 @@ func init() {
 	CreateCmd.AddCommand(GqlCreateCmd)
 	CreateCmd.AddCommand(IntegrationCmd)
 	CreateCmd.AddCommand(SwaggerCreateCmd)
+	CreateCmd.AddCommand(GrpcCreateCmd)
 }
@@ -0,0 +1,123 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"internal/apiclient"
+
+	genapi "internal/bundlegen"
+
+	"internal/client/apis"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+// GenSpecCmd to generate an OpenAPI spec from an API proxy
+var GenSpecCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate an OpenAPI spec from an API proxy",
+	Long: "Generate a skeleton OpenAPI 3 spec from the flows, security policies and targets " +
+		"of an API proxy revision, a proxy bundle (zip) or a proxy folder",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		sources := 0
+		for _, source := range []string{name, proxyZip, proxyFolder} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("one of name, proxy bundle (zip) or folder must be specified")
+		}
+		if specFormat != "yaml" && specFormat != "json" {
+			return fmt.Errorf("invalid format %s, must be one of yaml, json", specFormat)
+		}
+		if name != "" {
+			return apiclient.SetApigeeOrg(org)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		bundleDir := proxyFolder
+
+		if name != "" {
+			var tmpDir string
+			if tmpDir, err = os.MkdirTemp("", "proxy"); err != nil {
+				return err
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if revision == -1 {
+				if revision, err = apis.GetHighestProxyRevision(name); err != nil {
+					return err
+				}
+			}
+			proxyZip = filepath.Join(tmpDir, name+".zip")
+			if err = apis.FetchProxyToFile(name, revision, proxyZip); err != nil {
+				return err
+			}
+		}
+
+		if proxyZip != "" {
+			if bundleDir, err = unzipBundle(proxyZip); err != nil {
+				return err
+			}
+			defer os.RemoveAll(bundleDir)
+		}
+
+		doc, err := genapi.GenerateSpecFromBundle(bundleDir)
+		if err != nil {
+			return err
+		}
+
+		content, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return err
+		}
+		if specFormat == "yaml" {
+			if content, err = yaml.JSONToYAML(content); err != nil {
+				return err
+			}
+		}
+
+		if specOutputFile == "" {
+			fmt.Println(string(content))
+			return nil
+		}
+		return os.WriteFile(specOutputFile, content, 0o644)
+	},
+}
+
+var specFormat, specOutputFile string
+
+func init() {
+	GenSpecCmd.Flags().StringVarP(&name, "name", "n",
+		"", "API Proxy name")
+	GenSpecCmd.Flags().IntVarP(&revision, "rev", "v",
+		-1, "API Proxy revision. If not set, the highest revision is used")
+	GenSpecCmd.Flags().StringVarP(&proxyZip, "proxy-zip", "p",
+		"", "Path to the Proxy bundle/zip file")
+	GenSpecCmd.Flags().StringVarP(&proxyFolder, "proxy-folder", "f",
+		"", "Path to the Proxy Bundle; ex: ./test/apiproxy")
+	GenSpecCmd.Flags().StringVarP(&specFormat, "format", "",
+		"yaml", "Format of the spec; yaml or json")
+	GenSpecCmd.Flags().StringVarP(&specOutputFile, "output", "",
+		"", "Write the spec to a file instead of stdout")
+}
@@ -31,6 +31,7 @@ import (
 	proxybundle "internal/bundlegen/proxybundle"
 
 	"internal/client/apis"
+	"internal/client/products"
 
 	"github.com/spf13/cobra"
 )
@@ -47,6 +48,18 @@ var GqlCreateCmd = &cobra.Command{
 		if targetURL != "" && targetURLRef != "" {
 			return fmt.Errorf("either target-url or target-url-ref must be passed, not both")
 		}
+		if len(gqlQuotas) > 0 && skipPolicy {
+			return fmt.Errorf("quotas require the GraphQL policy, skip-policy cannot be set")
+		}
+		if len(gqlQuotas) > 0 && apiKeyLocation != "" && gqlProduct == "" {
+			return fmt.Errorf("with apikey-location the quotas are read from the API product, product must be set")
+		}
+		if gqlProduct != "" && !importProxy {
+			return fmt.Errorf("the API product can only be created when the API proxy is imported")
+		}
+		if err = validateOutputFlags(); err != nil {
+			return err
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
@@ -66,22 +79,50 @@ var GqlCreateCmd = &cobra.Command{
 			return err
 		}
 
+		quotas, err := getGraphQLQuotas()
+		if err != nil {
+			return err
+		}
+
+		var operations bundle.GraphQLOperations
+		if !skipPolicy || gqlProduct != "" {
+			if operations, err = bundle.ParseGraphQLSchema(string(content)); err != nil {
+				return err
+			}
+		}
+		for operationType := range quotas {
+			if _, ok := operations[operationType]; !ok {
+				return fmt.Errorf("the schema has no %s operations for the quota", operationType)
+			}
+		}
+
+		g := bundle.NewGenerator()
+		g.SetTemplateDir(templateDir)
+
 		// Generate the apiproxy struct
-		err = bundle.GenerateAPIProxyDefFromGQL(name,
+		err = g.GenerateAPIProxyDefFromGQL(name,
 			gqlDocName,
 			basePath,
 			apiKeyLocation,
 			skipPolicy,
 			addCORS,
 			targetURLRef,
-			targetURL)
+			targetURL,
+			operations,
+			quotas)
+
+		if err != nil {
+			return err
+		}
 
+		genDir, err := generateDir(outputDir)
 		if err != nil {
 			return err
 		}
 
 		// Create the API proxy bundle
-		err = proxybundle.GenerateAPIProxyBundleFromGQL(name,
+		err = proxybundle.GenerateAPIProxyBundleFromGQL(g,
+			name,
 			string(content),
 			gqlDocName,
 			action,
@@ -90,21 +131,59 @@ var GqlCreateCmd = &cobra.Command{
 			skipPolicy,
 			addCORS,
 			targetURLRef,
-			targetURL)
+			targetURL,
+			operations.PolicyOperationType(),
+			maxDepth,
+			maxCount,
+			quotas,
+			quotaInterval,
+			quotaTimeUnit,
+			genDir)
 
 		if err != nil {
 			return err
 		}
 
-		if importProxy {
-			_, err = apis.CreateProxy(name, name+".zip")
+		conflicts, err := finishBundle(name, genDir, outputDir)
+		printConflicts(conflicts)
+		if err != nil {
+			return err
+		}
+
+		if !importProxy {
+			return nil
+		}
+
+		if _, err = apis.CreateProxy(name, name+".zip"); err != nil {
+			return err
+		}
+
+		if gqlProduct == "" {
+			return nil
 		}
 
+		p := products.APIProduct{
+			Name:                  gqlProduct,
+			DisplayName:           gqlProduct,
+			ApprovalType:          "auto",
+			GraphQLOperationGroup: &products.GraphqlOperationGroup{},
+		}
+		for _, operationType := range bundle.GraphQLOperationTypes {
+			if fields, ok := operations[operationType]; ok {
+				p.GraphQLOperationGroup.AddOperations(name, strings.ToUpper(operationType), fields,
+					quotas[operationType], quotaInterval, quotaTimeUnit)
+			}
+		}
+		_, err = products.Create(p)
 		return err
 	},
 }
 
-var gqlFile, gqlURI, basePath, action, apiKeyLocation string
+var (
+	gqlFile, gqlURI, basePath, action, apiKeyLocation, gqlProduct string
+	gqlQuotas                                                     []string
+	maxDepth, maxCount                                            int
+)
 
 func init() {
 	GqlCreateCmd.Flags().StringVarP(&name, "name", "n",
@@ -123,12 +202,28 @@ func init() {
 		"", "Set a target URL for the target endpoint")
 	GqlCreateCmd.Flags().StringVarP(&apiKeyLocation, "apikey-location", "",
 		"", "Set the location of the API key, ex: request.header.x-api-key")
+	GqlCreateCmd.Flags().IntVarP(&maxDepth, "max-depth", "",
+		4, "Maximum depth of queries allowed by the GraphQL policy")
+	GqlCreateCmd.Flags().IntVarP(&maxCount, "max-count", "",
+		4, "Maximum number of fragments allowed by the GraphQL policy")
+	GqlCreateCmd.Flags().StringArrayVarP(&gqlQuotas, "quota", "",
+		nil, "Add a quota to the flow of an operation type, ex: query=100. Can be repeated. "+
+			"With apikey-location, the quotas are set in the API product and product is required")
+	GqlCreateCmd.Flags().StringVarP(&quotaInterval, "quota-interval", "",
+		"1", "Interval of the quotas")
+	GqlCreateCmd.Flags().StringVarP(&quotaTimeUnit, "quota-unit", "",
+		"minute", "Time unit of the quota intervals; minute, hour, day or month")
+	GqlCreateCmd.Flags().StringVarP(&gqlProduct, "product", "",
+		"", "Create an API product with this name and the GraphQL operations of the schema")
 	GqlCreateCmd.Flags().BoolVarP(&importProxy, "import", "",
 		true, "Import API Proxy after generation from spec")
 	GqlCreateCmd.Flags().BoolVarP(&skipPolicy, "skip-policy", "",
-		false, "Skip adding the GraphQL Validate policy")
+		false, "Skip adding the GraphQL Validate policy. Without it the graphql.operation.type "+
+			"variable is not set and no operation type flows are generated")
 	GqlCreateCmd.Flags().BoolVarP(&addCORS, "add-cors", "",
 		false, "Add a CORS policy")
+	addOutputFlags(GqlCreateCmd)
+	addTemplateFlag(GqlCreateCmd)
 
 	_ = GqlCreateCmd.MarkFlagRequired("name")
 	_ = GqlCreateCmd.MarkFlagRequired("basepath")
@@ -166,6 +261,22 @@ func readSchemaURL() (string, []byte, error) {
 	return path.Base(u.Path), respBody, err
 }
 
+// getGraphQLQuotas returns the quota limits by operation type
+func getGraphQLQuotas() (quotas map[string]string, err error) {
+	quotas = map[string]string{}
+	for _, q := range gqlQuotas {
+		operationType, limit, found := strings.Cut(q, "=")
+		if !found || limit == "" {
+			return nil, fmt.Errorf("quota must be of the form <operation type>=<limit>, ex: query=100")
+		}
+		if operationType != "query" && operationType != "mutation" {
+			return nil, fmt.Errorf("invalid operation type %s in quota, must be query or mutation", operationType)
+		}
+		quotas[operationType] = limit
+	}
+	return quotas, nil
+}
+
 func getKeyNameAndLocation() (location string, key string, err error) {
 	if apiKeyLocation == "" {
 		return "", "", nil
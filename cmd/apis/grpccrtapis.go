@@ -0,0 +1,168 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"internal/apiclient"
+
+	bundle "internal/bundlegen"
+	proxybundle "internal/bundlegen/proxybundle"
+
+	"internal/client/apis"
+	"internal/client/products"
+
+	"github.com/spf13/cobra"
+)
+
+var GrpcCreateCmd = &cobra.Command{
+	Use:   "grpc",
+	Short: "Creates an API proxy from a gRPC proto file",
+	Long:  "Creates an API proxy from a gRPC proto file, with a flow per method and a GRPC target server",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if grpcProduct != "" && !importProxy {
+			return fmt.Errorf("the API product can only be created when the API proxy is imported")
+		}
+		if quotaLimit != "" && apiKeyLocation != "" && grpcProduct == "" {
+			return fmt.Errorf("with apikey-location the quota is read from the API product, product must be set")
+		}
+		if err = validateOutputFlags(); err != nil {
+			return err
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		content, err := os.ReadFile(protoFile)
+		if err != nil {
+			return err
+		}
+
+		services, err := bundle.ParseProto(string(content))
+		if err != nil {
+			return err
+		}
+
+		location, keyName, err := getKeyNameAndLocation()
+		if err != nil {
+			return err
+		}
+
+		g := bundle.NewGenerator()
+		g.SetTemplateDir(templateDir)
+
+		// Generate the apiproxy struct
+		err = g.GenerateAPIProxyDefFromGRPC(name,
+			filepath.Base(protoFile),
+			services,
+			grpcBasePath,
+			grpcTargetServer,
+			apiKeyLocation,
+			quotaLimit,
+			addCORS)
+		if err != nil {
+			return err
+		}
+
+		genDir, err := generateDir(outputDir)
+		if err != nil {
+			return err
+		}
+
+		// Create the API proxy bundle
+		err = proxybundle.GenerateAPIProxyBundleFromGRPC(g,
+			name,
+			location,
+			keyName,
+			quotaLimit,
+			quotaInterval,
+			quotaTimeUnit,
+			addCORS,
+			genDir)
+		if err != nil {
+			return err
+		}
+
+		conflicts, err := finishBundle(name, genDir, outputDir)
+		printConflicts(conflicts)
+		if err != nil {
+			return err
+		}
+
+		if !importProxy {
+			return nil
+		}
+
+		if _, err = apis.CreateProxy(name, name+".zip"); err != nil {
+			return err
+		}
+
+		if grpcProduct == "" {
+			return nil
+		}
+
+		p := products.APIProduct{
+			Name:               grpcProduct,
+			DisplayName:        grpcProduct,
+			ApprovalType:       "auto",
+			GrpcOperationGroup: &products.GrpcOperationGroup{},
+		}
+		for _, service := range services {
+			p.GrpcOperationGroup.AddService(name, service.Name, service.Methods,
+				quotaLimit, quotaInterval, quotaTimeUnit)
+		}
+		_, err = products.Create(p)
+		return err
+	},
+}
+
+var (
+	protoFile, grpcBasePath, grpcTargetServer, grpcProduct string
+	quotaLimit, quotaInterval, quotaTimeUnit               string
+)
+
+func init() {
+	GrpcCreateCmd.Flags().StringVarP(&name, "name", "n",
+		"", "API Proxy name")
+	GrpcCreateCmd.Flags().StringVarP(&protoFile, "proto", "f",
+		"", "gRPC proto file")
+	GrpcCreateCmd.Flags().StringVarP(&grpcBasePath, "basepath", "p",
+		"/", "Base Path of the API Proxy, must be a prefix of /package.Service")
+	GrpcCreateCmd.Flags().StringVarP(&grpcTargetServer, "target-server", "",
+		"", "Name of the GRPC target server of the target endpoint")
+	GrpcCreateCmd.Flags().StringVarP(&apiKeyLocation, "apikey-location", "",
+		"", "Set the location of the API key, ex: request.header.x-api-key")
+	GrpcCreateCmd.Flags().StringVarP(&quotaLimit, "quota-limit", "",
+		"", "Add a quota policy allowing this number of requests per interval. "+
+			"With apikey-location, the quota is set in the API product and product is required")
+	GrpcCreateCmd.Flags().StringVarP(&quotaInterval, "quota-interval", "",
+		"1", "Interval of the quota")
+	GrpcCreateCmd.Flags().StringVarP(&quotaTimeUnit, "quota-unit", "",
+		"minute", "Time unit of the quota interval; minute, hour, day or month")
+	GrpcCreateCmd.Flags().StringVarP(&grpcProduct, "product", "",
+		"", "Create an API product with this name and a gRPC operation per service")
+	GrpcCreateCmd.Flags().BoolVarP(&importProxy, "import", "",
+		true, "Import API Proxy after generation from the proto file")
+	GrpcCreateCmd.Flags().BoolVarP(&addCORS, "add-cors", "",
+		false, "Add a CORS policy")
+	addOutputFlags(GrpcCreateCmd)
+	addTemplateFlag(GrpcCreateCmd)
+
+	_ = GrpcCreateCmd.MarkFlagRequired("name")
+	_ = GrpcCreateCmd.MarkFlagRequired("proto")
+	_ = GrpcCreateCmd.MarkFlagRequired("target-server")
+}
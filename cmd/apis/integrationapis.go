@@ -30,6 +30,9 @@ var IntegrationCmd = &cobra.Command{
 	Short: "Creates an API proxy template for Application Integration",
 	Long:  "Creates an API proxy template for Application Integration with an API Trigger",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if err = validateOutputFlags(); err != nil {
+			return err
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
@@ -40,10 +43,22 @@ var IntegrationCmd = &cobra.Command{
 
 		defer os.RemoveAll(tmpDir)
 
-		if err = bundlegen.GenerateIntegrationAPIProxy(name, integration, apitrigger); err != nil {
+		g := bundlegen.NewGenerator()
+		g.SetTemplateDir(templateDir)
+
+		if err = g.GenerateIntegrationAPIProxy(name, integration, apitrigger); err != nil {
 			return err
 		}
-		if err = proxybundle.GenerateIntegrationAPIProxyBundle(name, integration, apitrigger, true); err != nil {
+		genDir, err := generateDir(outputDir)
+		if err != nil {
+			return err
+		}
+		if err = proxybundle.GenerateIntegrationAPIProxyBundle(g, name, integration, apitrigger, true, genDir); err != nil {
+			return err
+		}
+		conflicts, err := finishBundle(name, genDir, outputDir)
+		printConflicts(conflicts)
+		if err != nil {
 			return err
 		}
 		/*if _, err = apis.CreateProxy(name, tmpDir); err != nil {
@@ -62,6 +77,8 @@ func init() {
 		"", "Integration name")
 	IntegrationCmd.Flags().StringVarP(&apitrigger, "trigger", "",
 		"", "API Trigger name; don't include 'api_trigger/'")
+	addOutputFlags(IntegrationCmd)
+	addTemplateFlag(IntegrationCmd)
 
 	_ = IntegrationCmd.MarkFlagRequired("name")
 	_ = IntegrationCmd.MarkFlagRequired("integration")
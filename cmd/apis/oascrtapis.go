@@ -24,6 +24,7 @@ import (
 
 	"internal/client/apis"
 
+	"github.com/apigee/apigeecli/cmd/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -33,29 +34,67 @@ var OasCreateCmd = &cobra.Command{
 	Short:   "Creates an API proxy from an OpenAPI Specification",
 	Long:    "Creates an API proxy from an OpenAPI Specification",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
-		if oasFile == "" && oasURI == "" {
-			return fmt.Errorf("either oasfile or oasuri must be passed")
+		if oasFile == "" && oasURI == "" && specDir == "" {
+			return fmt.Errorf("either oasfile, oasuri or spec-dir must be passed")
+		}
+		if specDir != "" {
+			if oasFile != "" || oasURI != "" {
+				return fmt.Errorf("spec-dir cannot be combined with oasfile or oasuri")
+			}
+			if name != "" {
+				return fmt.Errorf("name cannot be set with spec-dir, proxy names are derived from the spec file names")
+			}
+			if createTargetServers {
+				return fmt.Errorf("create-target-servers cannot be combined with spec-dir")
+			}
+			if err = utils.ValidateReportFormat(format); err != nil {
+				return err
+			}
+		} else if name == "" {
+			return fmt.Errorf("name must be passed")
+		}
+		if err = validateOutputFlags(); err != nil {
+			return err
 		}
 		if targetURL != "" && targetURLRef != "" {
 			return fmt.Errorf("either target-url or target-url-ref must be passed, not both")
 		}
+		if (useTargetServers || createTargetServers) && (targetURL != "" || targetURLRef != "") {
+			return fmt.Errorf("target-servers cannot be combined with target-url or target-url-ref")
+		}
+		if len(targetServerEnvs) > 0 && !createTargetServers {
+			return fmt.Errorf("env requires create-target-servers")
+		}
+		if mock && (targetURL != "" || targetURLRef != "" || useTargetServers || createTargetServers ||
+			oasGoogleAcessTokenScopeLiteral != "" || oasGoogleIDTokenAudLiteral != "" || oasGoogleIDTokenAudRef != "") {
+			return fmt.Errorf("mock cannot be combined with target or google token flags, a mock API proxy has no target")
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		var content []byte
 		var oasDocName string
 
+		if specDir != "" {
+			return generateFromSpecDir()
+		}
+
+		g := bundle.NewGenerator()
+		g.SetTemplateDir(templateDir)
+		g.UseTargetServers(useTargetServers || createTargetServers)
+		g.SetMock(mock)
+
 		if oasFile != "" {
-			oasDocName, content, err = bundle.LoadDocumentFromFile(oasFile, validateSpec, formatValidation)
+			oasDocName, content, err = g.LoadDocumentFromFile(oasFile, validateSpec, formatValidation)
 		} else {
-			oasDocName, content, err = bundle.LoadDocumentFromURI(oasURI, validateSpec, formatValidation)
+			oasDocName, content, err = g.LoadDocumentFromURI(oasURI, validateSpec, formatValidation)
 		}
 		if err != nil {
 			return err
 		}
 
 		// Generate the apiproxy struct
-		err = bundle.GenerateAPIProxyDefFromOAS(name,
+		err = g.GenerateAPIProxyDefFromOAS(name,
 			oasDocName,
 			skipPolicy,
 			addCORS,
@@ -69,8 +108,14 @@ var OasCreateCmd = &cobra.Command{
 			return err
 		}
 
+		genDir, err := generateDir(outputDir)
+		if err != nil {
+			return err
+		}
+
 		// Create the API proxy bundle
-		err = proxybundle.GenerateAPIProxyBundleFromOAS(name,
+		err = proxybundle.GenerateAPIProxyBundleFromOAS(g,
+			name,
 			string(content),
 			oasDocName,
 			skipPolicy,
@@ -79,12 +124,25 @@ var OasCreateCmd = &cobra.Command{
 			oasGoogleIDTokenAudLiteral,
 			oasGoogleIDTokenAudRef,
 			targetURLRef,
-			targetURL)
+			targetURL,
+			genDir)
+
+		if err != nil {
+			return err
+		}
 
+		conflicts, err := finishBundle(name, genDir, outputDir)
+		printConflicts(conflicts)
 		if err != nil {
 			return err
 		}
 
+		if createTargetServers {
+			if err = createOASTargetServers(g, name); err != nil {
+				return err
+			}
+		}
+
 		if importProxy {
 			_, err = apis.CreateProxy(name, name+".zip")
 		}
@@ -96,7 +154,8 @@ var OasCreateCmd = &cobra.Command{
 var (
 	oasFile, oasURI, targetURL                                                          string
 	oasGoogleAcessTokenScopeLiteral, oasGoogleIDTokenAudLiteral, oasGoogleIDTokenAudRef string
-	validateSpec, formatValidation                                                      bool
+	validateSpec, formatValidation, mock                                                bool
+	specDir, format, outputFile                                                         string
 )
 
 func init() {
@@ -126,6 +185,23 @@ func init() {
 		false, "Add a CORS policy")
 	OasCreateCmd.Flags().BoolVarP(&formatValidation, "formatValidation", "",
 		true, "disables validation of schema type formats")
-
-	_ = OasCreateCmd.MarkFlagRequired("name")
+	OasCreateCmd.Flags().StringVarP(&specDir, "spec-dir", "",
+		"", "Folder of Open API 3.0 Specification files, generates an API proxy per file")
+	OasCreateCmd.Flags().IntVarP(&conn, "conn", "c",
+		4, "Number of specs processed in parallel with spec-dir")
+	OasCreateCmd.Flags().StringVarP(&format, "format", "",
+		"table", "Format of the spec-dir report; table, csv or json")
+	OasCreateCmd.Flags().StringVarP(&outputFile, "output", "",
+		"", "Write the spec-dir report to a file instead of stdout")
+	OasCreateCmd.Flags().BoolVarP(&useTargetServers, "target-servers", "",
+		false, "Load balance over target servers derived from the OAS servers instead of using the first server url")
+	OasCreateCmd.Flags().BoolVarP(&createTargetServers, "create-target-servers", "",
+		false, "Create or update the target servers of the OAS servers in the environment "+
+			"set with x-apigee-environment; implies target-servers")
+	OasCreateCmd.Flags().StringArrayVarP(&targetServerEnvs, "env", "e",
+		[]string{}, "Environments to create the target servers of OAS servers without x-apigee-environment in")
+	OasCreateCmd.Flags().BoolVarP(&mock, "mock", "",
+		false, "Generate an API proxy without a target that responds with the examples of the spec")
+	addOutputFlags(OasCreateCmd)
+	addTemplateFlag(OasCreateCmd)
 }
@@ -0,0 +1,202 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apis
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	bundle "internal/bundlegen"
+	proxybundle "internal/bundlegen/proxybundle"
+
+	"internal/client/apis"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+)
+
+type specResult struct {
+	Spec      string   `json:"spec"`
+	Proxy     string   `json:"proxy"`
+	Status    string   `json:"status"`
+	Error     string   `json:"error,omitempty"`
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// generateFromSpecDir generates, and optionally imports, an API proxy for every
+// OpenAPI spec in specDir using conn parallel workers
+func generateFromSpecDir() (err error) {
+	var specs []specResult
+
+	seen := map[string]string{}
+	err = filepath.WalkDir(specDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(filePath)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		spec := specResult{Spec: filePath, Proxy: proxyNameFromSpec(filePath)}
+		if other, ok := seen[spec.Proxy]; ok {
+			spec.Status = "failed"
+			spec.Error = "proxy name conflicts with " + other
+		} else {
+			seen[spec.Proxy] = filePath
+		}
+		specs = append(specs, spec)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	clilog.Info.Printf("Found %d specs in %s\n", len(specs), specDir)
+
+	// don't print to sysout
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	jobChan := make(chan *specResult)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < conn; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for spec := range jobChan {
+				generateFromSpec(spec)
+			}
+		}()
+	}
+
+	for index := range specs {
+		if specs[index].Status == "" {
+			jobChan <- &specs[index]
+		}
+	}
+	close(jobChan)
+	wg.Wait()
+
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Spec < specs[j].Spec
+	})
+
+	header := []string{"SPEC", "PROXY", "STATUS", "ERROR", "CONFLICTS"}
+	rows := [][]string{}
+	failed := []string{}
+	for _, spec := range specs {
+		rows = append(rows, []string{spec.Spec, spec.Proxy, spec.Status, spec.Error, strings.Join(spec.Conflicts, "; ")})
+		if spec.Status == "failed" {
+			failed = append(failed, spec.Spec)
+		}
+	}
+	if err = utils.WriteReport(format, outputFile, header, rows, specs); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to generate %d of %d specs: %s", len(failed), len(specs), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func generateFromSpec(spec *specResult) {
+	g := bundle.NewGenerator()
+	g.SetTemplateDir(templateDir)
+	g.UseTargetServers(useTargetServers)
+	g.SetMock(mock)
+
+	fail := func(err error) {
+		clilog.Warning.Printf("Failed to generate %s from %s: %v\n", spec.Proxy, spec.Spec, err)
+		spec.Status = "failed"
+		spec.Error = err.Error()
+	}
+
+	oasDocName, content, err := g.LoadDocumentFromFile(spec.Spec, validateSpec, formatValidation)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	if err = g.GenerateAPIProxyDefFromOAS(spec.Proxy,
+		oasDocName,
+		skipPolicy,
+		addCORS,
+		oasGoogleAcessTokenScopeLiteral,
+		oasGoogleIDTokenAudLiteral,
+		oasGoogleIDTokenAudRef,
+		targetURLRef,
+		targetURL); err != nil {
+		fail(err)
+		return
+	}
+
+	proxyOutputDir := ""
+	if outputDir != "" {
+		proxyOutputDir = filepath.Join(outputDir, spec.Proxy)
+	}
+	genDir, err := generateDir(proxyOutputDir)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	if err = proxybundle.GenerateAPIProxyBundleFromOAS(g,
+		spec.Proxy,
+		string(content),
+		oasDocName,
+		skipPolicy,
+		addCORS,
+		oasGoogleAcessTokenScopeLiteral,
+		oasGoogleIDTokenAudLiteral,
+		oasGoogleIDTokenAudRef,
+		targetURLRef,
+		targetURL,
+		genDir); err != nil {
+		fail(err)
+		return
+	}
+	if spec.Conflicts, err = finishBundle(spec.Proxy, genDir, proxyOutputDir); err != nil {
+		fail(err)
+		return
+	}
+	spec.Status = "generated"
+
+	if importProxy {
+		if _, err = apis.CreateProxy(spec.Proxy, spec.Proxy+".zip"); err != nil {
+			fail(err)
+			return
+		}
+		spec.Status = "imported"
+	}
+}
+
+// proxyNameFromSpec derives the API proxy name from the spec file name
+func proxyNameFromSpec(filePath string) string {
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	return regexp.MustCompile(`[^a-zA-Z0-9_-]+`).ReplaceAllString(name, "-")
+}
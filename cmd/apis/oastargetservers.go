@@ -0,0 +1,72 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apis
+
+import (
+	"errors"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	bundle "internal/bundlegen"
+
+	"internal/client/targetservers"
+)
+
+var (
+	useTargetServers, createTargetServers bool
+	targetServerEnvs                      []string
+)
+
+// createOASTargetServers creates or updates the target servers of the OAS
+// servers in their environments. Servers without x-apigee-environment are
+// created in every environment of targetServerEnvs
+func createOASTargetServers(g *bundle.Generator, name string) (err error) {
+	servers, err := g.GetTargetServers(name)
+	if err != nil {
+		return err
+	}
+
+	defer apiclient.SetApigeeEnv(apiclient.GetApigeeEnv())
+
+	for _, server := range servers {
+		envs := targetServerEnvs
+		if server.Env != "" {
+			envs = []string{server.Env}
+		} else if len(envs) == 0 {
+			clilog.Warning.Printf("No environment for target server %s of %s, skipping\n", server.Name, server.URL)
+			continue
+		}
+		for _, env := range envs {
+			apiclient.SetApigeeEnv(env)
+
+			// only the host, port and TLS flag come from the spec, keystores,
+			// truststores and mTLS set on an existing target server are kept
+			_, err = targetservers.UpdateHost(server.Name, server.Host, server.Port, server.TLS)
+			if errors.Is(err, apiclient.ErrNotFound) {
+				clilog.Info.Printf("Creating target server %s in environment %s\n", server.Name, env)
+				_, err = targetservers.Create(server.Name, "Generated from "+server.URL, server.Host, server.Port,
+					true, false, "", "", "", "", server.TLS, false, false)
+			} else if err == nil {
+				clilog.Info.Printf("Updated target server %s in environment %s\n", server.Name, env)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
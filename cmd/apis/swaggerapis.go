@@ -35,26 +35,32 @@ var SwaggerCreateCmd = &cobra.Command{
 		if swaggerFile == "" && swaggerURI == "" {
 			return fmt.Errorf("either swaggerfile or swaggeruri must be passed")
 		}
+		if err = validateOutputFlags(); err != nil {
+			return err
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		// var content []byte
 		var oasDocName string
 
+		g := bundle.NewGenerator()
+		g.SetTemplateDir(templateDir)
+
 		if swaggerURI != "" {
-			if oasDocName, _, err = bundle.LoadSwaggerFromUri(swaggerURI); err != nil {
+			if oasDocName, _, err = g.LoadSwaggerFromUri(swaggerURI); err != nil {
 				return err
 			}
 		}
 
 		if swaggerFile != "" {
-			if oasDocName, _, err = bundle.LoadSwaggerFromFile(swaggerFile); err != nil {
+			if oasDocName, _, err = g.LoadSwaggerFromFile(swaggerFile); err != nil {
 				return err
 			}
 		}
 
 		// Generate the apiproxy struct
-		name, err = bundle.GenerateAPIProxyFromSwagger(name,
+		name, err = g.GenerateAPIProxyFromSwagger(name,
 			oasDocName,
 			basePath,
 			addCORS)
@@ -63,10 +69,27 @@ var SwaggerCreateCmd = &cobra.Command{
 			return err
 		}
 
+		genDir, err := generateDir(outputDir)
+		if err != nil {
+			return err
+		}
+
 		// Create the API proxy bundle
-		err = proxybundle.GenerateAPIProxyBundleFromSwagger(name,
+		err = proxybundle.GenerateAPIProxyBundleFromSwagger(g,
+			name,
 			skipPolicy,
-			addCORS)
+			addCORS,
+			genDir)
+
+		if err != nil {
+			return err
+		}
+
+		conflicts, err := finishBundle(name, genDir, outputDir)
+		printConflicts(conflicts)
+		if err != nil {
+			return err
+		}
 
 		if importProxy {
 			_, err = apis.CreateProxy(name, name+".zip")
@@ -89,4 +112,6 @@ func init() {
 		true, "Import API Proxy after generation from spec")
 	SwaggerCreateCmd.Flags().BoolVarP(&addCORS, "add-cors", "",
 		false, "Add a CORS policy")
+	addOutputFlags(SwaggerCreateCmd)
+	addTemplateFlag(SwaggerCreateCmd)
 }
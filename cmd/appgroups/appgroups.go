@@ -0,0 +1,51 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd to manage app groups
+var Cmd = &cobra.Command{
+	Use:     "appgroups",
+	Aliases: []string{"ag"},
+	Short:   "Manage Apigee App Groups",
+	Long:    "Manage Apigee App Groups and the apps they own",
+}
+
+var (
+	org, name                          string
+	displayName, channelURI, channelID string
+	attrs                              map[string]string
+	action                             string
+	conn                               int
+)
+
+func init() {
+	Cmd.PersistentFlags().StringVarP(&org, "org", "o",
+		"", "Apigee organization name")
+
+	Cmd.AddCommand(CreateCmd)
+	Cmd.AddCommand(GetCmd)
+	Cmd.AddCommand(ListCmd)
+	Cmd.AddCommand(UpdateCmd)
+	Cmd.AddCommand(DelCmd)
+	Cmd.AddCommand(ManageCmd)
+	Cmd.AddCommand(ExpCmd)
+	Cmd.AddCommand(ImpCmd)
+	Cmd.AddCommand(AttributesCmd)
+	Cmd.AddCommand(AppsCmd)
+}
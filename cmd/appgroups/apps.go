@@ -0,0 +1,46 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// AppsCmd to manage the apps of an app group
+var AppsCmd = &cobra.Command{
+	Use:   "apps",
+	Short: "Manage App Group Apps",
+	Long:  "Manage the apps owned by an App Group",
+}
+
+var (
+	appGroup, appName, expires, callback string
+	apiProducts, scopes                  []string
+)
+
+func init() {
+	AppsCmd.PersistentFlags().StringVarP(&appGroup, "appgroup", "g",
+		"", "Name of the app group")
+
+	_ = AppsCmd.MarkPersistentFlagRequired("appgroup")
+
+	AppsCmd.AddCommand(CreateAppCmd)
+	AppsCmd.AddCommand(GetAppCmd)
+	AppsCmd.AddCommand(ListAppsCmd)
+	AppsCmd.AddCommand(UpdateAppCmd)
+	AppsCmd.AddCommand(DelAppCmd)
+	AppsCmd.AddCommand(ManageAppCmd)
+	AppsCmd.AddCommand(KeysCmd)
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// AttributesCmd to manage app group attributes
+var AttributesCmd = &cobra.Command{
+	Use:     "attributes",
+	Aliases: []string{"attrs"},
+	Short:   "Manage App Group attributes",
+	Long:    "Manage App Group attributes",
+}
+
+var attrName, attrValue string
+
+func init() {
+	AttributesCmd.PersistentFlags().StringVarP(&name, "name", "n",
+		"", "Name of the app group")
+
+	_ = AttributesCmd.MarkPersistentFlagRequired("name")
+
+	AttributesCmd.AddCommand(ListAttrCmd)
+	AttributesCmd.AddCommand(SetAttrCmd)
+	AttributesCmd.AddCommand(DelAttrCmd)
+}
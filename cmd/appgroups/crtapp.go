@@ -0,0 +1,54 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"internal/apiclient"
+
+	"internal/client/appgroups"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateAppCmd to create an app group app
+var CreateAppCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an App Group App",
+	Long:  "Create an App Group App",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = appgroups.CreateApp(appGroup, appName, expires, callback, apiProducts, scopes, attrs)
+		return
+	},
+}
+
+func init() {
+	CreateAppCmd.Flags().StringVarP(&appName, "name", "n",
+		"", "Name of the app")
+	CreateAppCmd.Flags().StringVarP(&expires, "expires", "x",
+		"", "A setting, in milliseconds, for the lifetime of the consumer key")
+	CreateAppCmd.Flags().StringVarP(&callback, "callback", "c",
+		"", "The callbackUrl is used by OAuth")
+	CreateAppCmd.Flags().StringArrayVarP(&apiProducts, "prods", "p",
+		[]string{}, "A list of api products")
+	CreateAppCmd.Flags().StringArrayVarP(&scopes, "scopes", "s",
+		[]string{}, "OAuth scopes")
+	CreateAppCmd.Flags().StringToStringVar(&attrs, "attrs",
+		nil, "Custom attributes")
+
+	_ = CreateAppCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"internal/apiclient"
+
+	"internal/client/appgroups"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateKeyCmd to create an app group app key
+var CreateKeyCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an App Group App key",
+	Long:  "Import a consumer key and secret into an App Group App and add api products to it",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = appgroups.CreateKey(appGroup, appName, key, secret, apiProducts, scopes, expiresIn)
+		return
+	},
+}
+
+func init() {
+	CreateKeyCmd.Flags().StringVarP(&key, "key", "k",
+		"", "App consumer key")
+	CreateKeyCmd.Flags().StringVarP(&secret, "secret", "r",
+		"", "App consumer secret")
+	CreateKeyCmd.Flags().StringArrayVarP(&apiProducts, "prods", "p",
+		[]string{}, "A list of api products")
+	CreateKeyCmd.Flags().StringArrayVarP(&scopes, "scopes", "s",
+		[]string{}, "OAuth scopes")
+	CreateKeyCmd.Flags().StringVarP(&expiresIn, "expires", "x",
+		"", "Lifetime of the consumer key in seconds")
+
+	_ = CreateKeyCmd.MarkFlagRequired("key")
+	_ = CreateKeyCmd.MarkFlagRequired("secret")
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"internal/apiclient"
+
+	"internal/client/appgroups"
+
+	"github.com/spf13/cobra"
+)
+
+// ExpCmd to export app groups
+var ExpCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export App Groups and their apps to a file",
+	Long:  "Export App Groups, their apps and credentials to a file",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		const exportFileName = "appgroups.json"
+
+		respBody, err := appgroups.Export(conn)
+		if err != nil {
+			return err
+		}
+
+		return apiclient.WriteByteArrayToFile(exportFileName, false, respBody)
+	},
+}
+
+func init() {
+	ExpCmd.Flags().IntVarP(&conn, "conn", "c",
+		4, "Number of connections")
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// KeysCmd to manage app group app keys
+var KeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage App Group App keys",
+	Long:  "Manage App Group App keys",
+}
+
+var key, secret, expiresIn, apiProduct string
+
+func init() {
+	KeysCmd.PersistentFlags().StringVarP(&appName, "name", "n",
+		"", "Name of the app")
+
+	_ = KeysCmd.MarkPersistentFlagRequired("name")
+
+	KeysCmd.AddCommand(CreateKeyCmd)
+	KeysCmd.AddCommand(GetKeyCmd)
+	KeysCmd.AddCommand(DelKeyCmd)
+	KeysCmd.AddCommand(ManageKeyCmd)
+	KeysCmd.AddCommand(AddProductsCmd)
+	KeysCmd.AddCommand(RemoveProductCmd)
+}
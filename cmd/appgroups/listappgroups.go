@@ -0,0 +1,63 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"fmt"
+
+	"internal/apiclient"
+
+	"internal/client/appgroups"
+
+	"github.com/spf13/cobra"
+)
+
+// ListCmd to list app groups
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Returns a list of App Groups",
+	Long:  "Returns a list of App Groups in the organization",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if all && (pageSize != -1 || pageToken != "") {
+			return fmt.Errorf("all cannot be combined with page-size or page-token")
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if all {
+			_, err = appgroups.ListAll(filter)
+			return
+		}
+		_, err = appgroups.List(pageSize, pageToken, filter)
+		return
+	},
+}
+
+var (
+	pageSize          int
+	pageToken, filter string
+	all               bool
+)
+
+func init() {
+	ListCmd.Flags().IntVarP(&pageSize, "page-size", "",
+		-1, "Number of app groups; limit is 1000")
+	ListCmd.Flags().StringVarP(&pageToken, "page-token", "",
+		"", "Token returned by a previous list call")
+	ListCmd.Flags().StringVarP(&filter, "filter", "",
+		"", "Filter the app groups, ex: channelId=abc")
+	ListCmd.Flags().BoolVarP(&all, "all", "",
+		false, "Fetch every page of app groups")
+}
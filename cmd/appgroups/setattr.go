@@ -0,0 +1,47 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"internal/apiclient"
+
+	"internal/client/appgroups"
+
+	"github.com/spf13/cobra"
+)
+
+// SetAttrCmd to add or change an app group attribute
+var SetAttrCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Adds or changes an App Group attribute",
+	Long:  "Adds or changes an App Group attribute",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = appgroups.SetAttribute(name, attrName, attrValue)
+		return
+	},
+}
+
+func init() {
+	SetAttrCmd.Flags().StringVarP(&attrName, "attr", "a",
+		"", "Name of the attribute")
+	SetAttrCmd.Flags().StringVarP(&attrValue, "value", "v",
+		"", "Value of the attribute")
+
+	_ = SetAttrCmd.MarkFlagRequired("attr")
+	_ = SetAttrCmd.MarkFlagRequired("value")
+}
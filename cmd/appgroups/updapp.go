@@ -0,0 +1,50 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"internal/apiclient"
+
+	"internal/client/appgroups"
+
+	"github.com/spf13/cobra"
+)
+
+// UpdateAppCmd to update an app group app
+var UpdateAppCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update an App Group App",
+	Long:  "Update the callback, scopes or attributes of an App Group App",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = appgroups.UpdateApp(appGroup, appName, callback, scopes, attrs)
+		return
+	},
+}
+
+func init() {
+	UpdateAppCmd.Flags().StringVarP(&appName, "name", "n",
+		"", "Name of the app")
+	UpdateAppCmd.Flags().StringVarP(&callback, "callback", "c",
+		"", "The callbackUrl is used by OAuth")
+	UpdateAppCmd.Flags().StringArrayVarP(&scopes, "scopes", "s",
+		[]string{}, "OAuth scopes")
+	UpdateAppCmd.Flags().StringToStringVar(&attrs, "attrs",
+		nil, "Custom attributes to add or change")
+
+	_ = UpdateAppCmd.MarkFlagRequired("name")
+}
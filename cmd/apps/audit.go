@@ -0,0 +1,124 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"internal/client/apps"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// AuditCmd to audit developers, apps and products
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit developers, apps and api products",
+	Long: "Report api products referenced by keys that no longer exist, expired, expiring and revoked keys, " +
+		"keys that never expire, inactive developers, developers and apps without credentials and " +
+		"api products that are not bundled into any app. With cleanup, orphaned products are removed from " +
+		"the keys, expired and revoked keys and apps without credentials are deleted",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if err = utils.ValidateReportFormat(format); err != nil {
+			return err
+		}
+		if expiringDays, err = utils.ParseDays(expiringWithin); err != nil {
+			return err
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		findings, err := apps.Audit(time.Duration(expiringDays) * 24 * time.Hour)
+		if err != nil {
+			return err
+		}
+
+		if err = writeAuditReport(findings); err != nil {
+			return err
+		}
+
+		if !cleanup {
+			return nil
+		}
+
+		cleanable := 0
+		for _, f := range findings {
+			if f.Cleanable() {
+				cleanable++
+			}
+		}
+		if cleanable == 0 {
+			clilog.Info.Println("Nothing to clean up")
+			return nil
+		}
+		if !assumeYes && !confirm(fmt.Sprintf("Clean up %d findings in %s?", cleanable, apiclient.GetApigeeOrg())) {
+			clilog.Info.Println("Clean up cancelled")
+			return nil
+		}
+		return apps.Cleanup(findings)
+	},
+}
+
+var (
+	expiringWithin     string
+	expiringDays       int
+	cleanup, assumeYes bool
+)
+
+func init() {
+	AuditCmd.Flags().StringVarP(&expiringWithin, "expiring-within", "",
+		"30d", "Report keys that expire within a duration, ex: 30d or 72h")
+	AuditCmd.Flags().StringVarP(&format, "format", "f",
+		"table", "Report format; table, csv or json")
+	AuditCmd.Flags().StringVarP(&outputFile, "output", "",
+		"", "Write the report to a file instead of stdout")
+	AuditCmd.Flags().BoolVarP(&cleanup, "cleanup", "",
+		false, "Remove orphaned products and delete expired keys, revoked keys and apps without credentials")
+	AuditCmd.Flags().BoolVarP(&assumeYes, "yes", "y",
+		false, "Clean up without asking for confirmation")
+}
+
+func writeAuditReport(findings []apps.AuditFinding) error {
+	header := []string{"check", "developer", "app", "key", "api product", "detail"}
+	rows := make([][]string, 0, len(findings))
+	for _, f := range findings {
+		rows = append(rows, []string{f.Check, f.Developer, f.App, f.Key, f.APIProduct, f.Detail})
+	}
+	if findings == nil {
+		findings = []apps.AuditFinding{}
+	}
+	return utils.WriteReport(format, outputFile, header, rows, findings)
+}
+
+// confirm asks a yes or no question on the terminal. The question is written to
+// stderr so that it is not mixed with a report written to stdout
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -15,6 +15,8 @@
 package apps
 
 import (
+	"fmt"
+
 	"internal/apiclient"
 
 	"internal/client/apps"
@@ -28,9 +30,16 @@ var ListCmd = &cobra.Command{
 	Short: "Returns a list of Developer Applications",
 	Long:  "Returns a list of app IDs within an organization based on app status",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if all && count != -1 {
+			return fmt.Errorf("all and count cannot be used together")
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if all {
+			_, err = apps.ListAll(includeCred, expand)
+			return
+		}
 		_, err = apps.List(includeCred, expand, count)
 		return
 	},
@@ -40,6 +49,7 @@ var (
 	expand      = false
 	includeCred = false
 	count       int
+	all         bool
 )
 
 func init() {
@@ -49,4 +59,6 @@ func init() {
 		false, "Expand Details")
 	ListCmd.Flags().BoolVarP(&includeCred, "inclCred", "i",
 		false, "Include Credentials")
+	ListCmd.Flags().BoolVarP(&all, "all", "",
+		false, "Fetch every page of apps")
 }
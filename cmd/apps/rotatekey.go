@@ -0,0 +1,153 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"internal/client/apps"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// RotateKeyCmd to rotate the keys of developer apps
+var RotateKeyCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate the keys of developer apps",
+	Long: "Create a new key, with the same api products and scopes, for every approved key " +
+		"of the apps selected by developer, product, attribute or name pattern. The old keys are kept; " +
+		"write the report with --format json --output <file>, then run the command again with " +
+		"--revoke <file> after the grace period to revoke them",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if revokeReport != "" {
+			if dryRun {
+				return fmt.Errorf("dry-run cannot be combined with revoke")
+			}
+			if gracePeriod <= 0 {
+				return fmt.Errorf("grace-period must be set to revoke the old keys")
+			}
+		} else if rotateDeveloper == "" && rotateProduct == "" && len(attrs) == 0 && namePattern == "" && !rotateAll {
+			return fmt.Errorf("select the apps with dev, prod, attrs or name, or set all to rotate every key")
+		}
+		if err = utils.ValidateReportFormat(format); err != nil {
+			return err
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if revokeReport != "" {
+			return revokeOldKeys()
+		}
+
+		filter := apps.RotateFilter{
+			DeveloperEmail: rotateDeveloper,
+			APIProduct:     rotateProduct,
+			Attributes:     attrs,
+			NamePattern:    namePattern,
+		}
+
+		rotations, rotateErr := apps.RotateKeys(filter, dryRun)
+
+		// the report maps the old keys to the new ones, it is read back to revoke the old keys
+		if err = writeRotationReport(rotations); err != nil {
+			return err
+		}
+
+		if !dryRun && outputFile != "" && format == "json" {
+			clilog.Info.Printf("After the grace period, revoke the old keys with: "+
+				"apigeecli apps keys rotate -o %s --revoke %s --grace-period <duration>\n", org, outputFile)
+		}
+		return rotateErr
+	},
+}
+
+// revokeOldKeys revokes the old keys listed in a JSON rotation report and
+// writes the report with the status of the old keys
+func revokeOldKeys() (err error) {
+	var content []byte
+	var rotations []apps.KeyRotation
+
+	if content, err = os.ReadFile(revokeReport); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(content, &rotations); err != nil {
+		return fmt.Errorf("revoke must be a rotation report written with --format json: %v", err)
+	}
+
+	revokeErr := apps.RevokeKeys(rotations, gracePeriod)
+	if err = writeRotationReport(rotations); err != nil {
+		return err
+	}
+	return revokeErr
+}
+
+var (
+	rotateDeveloper, rotateProduct, namePattern string
+	revokeReport, format, outputFile            string
+	dryRun, rotateAll                           bool
+	gracePeriod                                 time.Duration
+)
+
+func init() {
+	// the parent flags select a single app, the apps are selected by filters instead
+	RotateKeyCmd.Flags().StringVarP(&rotateDeveloper, "dev", "d",
+		"", "Rotate the keys of the apps owned by this developer email")
+	RotateKeyCmd.Flags().StringVarP(&namePattern, "name", "n",
+		"", "Rotate the keys of the apps matching this name or pattern, ex: mobile-*")
+	RotateKeyCmd.Flags().StringVarP(&rotateProduct, "prod", "p",
+		"", "Rotate the keys associated with this api product")
+	RotateKeyCmd.Flags().StringToStringVar(&attrs, "attrs",
+		nil, "Rotate the keys of the apps with these custom attributes")
+	RotateKeyCmd.Flags().BoolVarP(&rotateAll, "all", "",
+		false, "Rotate every approved key in the org when no other filter is set")
+	RotateKeyCmd.Flags().StringVarP(&revokeReport, "revoke", "",
+		"", "JSON report of a previous rotation; revoke its old keys instead of rotating")
+	RotateKeyCmd.Flags().DurationVarP(&gracePeriod, "grace-period", "",
+		0, "Time the old keys remain valid after the rotation, ex: 24h. Required to revoke")
+	RotateKeyCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "List the keys that would be rotated without making changes")
+	RotateKeyCmd.Flags().StringVarP(&format, "format", "f",
+		"table", "Report format; table, csv or json")
+	RotateKeyCmd.Flags().StringVarP(&outputFile, "output", "",
+		"", "Write the report to a file instead of stdout")
+}
+
+func writeRotationReport(rotations []apps.KeyRotation) error {
+	header := []string{"app", "developer", "old key", "new key", "api products", "old key status", "error"}
+	rows := make([][]string, 0, len(rotations))
+	for _, r := range rotations {
+		newKey := r.NewKey
+		if dryRun {
+			newKey = "(dry-run)"
+		}
+		rows = append(rows, []string{
+			r.App, r.DeveloperEmail, r.OldKey, newKey,
+			strings.Join(r.APIProducts, ","), r.OldKeyStatus, r.Error,
+		})
+	}
+	if rotations == nil {
+		rotations = []apps.KeyRotation{}
+	}
+	return utils.WriteReport(format, outputFile, header, rows, rotations)
+}
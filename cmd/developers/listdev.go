@@ -15,6 +15,8 @@
 package developers
 
 import (
+	"fmt"
+
 	"internal/apiclient"
 
 	"internal/client/developers"
@@ -27,10 +29,17 @@ var ListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Returns a list of App Developers",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if all && (count != -1 || ids != "") {
+			return fmt.Errorf("all cannot be combined with count or ids")
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	Long: "Lists all developers in an organization by email address",
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if all {
+			_, err = developers.ListAll(expand)
+			return
+		}
 		_, err = developers.List(count, expand, ids)
 		return
 	},
@@ -39,6 +48,7 @@ var ListCmd = &cobra.Command{
 var (
 	count int
 	ids   string
+	all   bool
 )
 
 func init() {
@@ -50,4 +60,7 @@ func init() {
 
 	ListCmd.Flags().StringVarP(&ids, "ids", "i",
 		"", "List of IDs to include, separated by commas")
+
+	ListCmd.Flags().BoolVarP(&all, "all", "",
+		false, "Fetch every page of developers")
 }
@@ -0,0 +1,49 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// AnalyticsCmd to manage asynchronous analytics queries
+var AnalyticsCmd = &cobra.Command{
+	Use:   "analytics",
+	Short: "Manage asynchronous analytics queries for the environment",
+	Long:  "Manage asynchronous analytics queries for the environment",
+}
+
+// AnalyticsQueryCmd to manage asynchronous analytics queries
+var AnalyticsQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Create asynchronous analytics queries and download the results",
+	Long:  "Create asynchronous analytics queries and download the results",
+}
+
+var queryID string
+
+func init() {
+	AnalyticsCmd.PersistentFlags().StringVarP(&environment, "env", "e",
+		"", "Apigee environment name")
+
+	_ = AnalyticsCmd.MarkPersistentFlagRequired("env")
+
+	AnalyticsQueryCmd.AddCommand(CreateQueryCmd)
+	AnalyticsQueryCmd.AddCommand(GetQueryCmd)
+	AnalyticsQueryCmd.AddCommand(ListQueriesCmd)
+	AnalyticsQueryCmd.AddCommand(GetQueryResultCmd)
+
+	AnalyticsCmd.AddCommand(AnalyticsQueryCmd)
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"internal/apiclient"
+	"internal/clilog"
+
+	environments "internal/client/env"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// CreateQueryCmd to submit an asynchronous analytics query
+var CreateQueryCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Submit an asynchronous analytics query",
+	Long: "Submit an asynchronous analytics query from a json or yaml definition. With --wait the query " +
+		"status is polled and the result is downloaded when it completes",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if !utils.FileExists(queryFile) {
+			return fmt.Errorf("query file %s was not found", queryFile)
+		}
+		if wait {
+			if err = validateResultFormat(resultFormat); err != nil {
+				return err
+			}
+		}
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		respBody, err := environments.CreateQuery(queryFile)
+		if err != nil || !wait {
+			return err
+		}
+
+		query := environments.Query{}
+		if err = json.Unmarshal(respBody, &query); err != nil {
+			return err
+		}
+		id := query.QueryID()
+
+		clilog.Info.Printf("Query id is %s\n", id)
+		clilog.Info.Printf("Checking query status in %d seconds\n", interval)
+
+		apiclient.DisableCmdPrintHttpResponse()
+
+		stop := apiclient.Every(interval*time.Second, func(time.Time) bool {
+			var respQueryBody []byte
+			if respQueryBody, err = environments.GetQuery(id); err != nil {
+				return false
+			}
+			if err = json.Unmarshal(respQueryBody, &query); err != nil {
+				return false
+			}
+
+			switch query.State {
+			case "enqueued", "running":
+				clilog.Info.Printf("Query status is: %s. Waiting %d seconds.\n", query.State, interval)
+				return true
+			case "completed":
+				clilog.Info.Printf("Query completed with %s rows\n", query.ResultRows)
+			case "failed":
+				err = fmt.Errorf("query %s failed: %s", id, query.Error)
+			default:
+				err = fmt.Errorf("unknown query state %s", query.State)
+			}
+			return false
+		})
+
+		<-stop
+
+		if err != nil {
+			return err
+		}
+		return writeQueryResult(id)
+	},
+}
+
+var queryFile string
+
+func init() {
+	CreateQueryCmd.Flags().StringVarP(&queryFile, "file", "f",
+		"", "Path to the query definition in json or yaml")
+	CreateQueryCmd.Flags().BoolVarP(&wait, "wait", "w",
+		false, "Wait for the query to complete and download the result")
+	CreateQueryCmd.Flags().StringVarP(&resultFormat, "format", "",
+		"csv", "Format of the downloaded result; csv or ndjson")
+	CreateQueryCmd.Flags().StringVarP(&resultFile, "output", "",
+		"", "Write the result to a file instead of stdout")
+
+	_ = CreateQueryCmd.MarkFlagRequired("file")
+}
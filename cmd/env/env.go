@@ -43,4 +43,6 @@ func init() {
 	Cmd.AddCommand(DeployCmd)
 	Cmd.AddCommand(TraceConfigCmd)
 	Cmd.AddCommand(ArchiveCmd)
+	Cmd.AddCommand(StatsCmd)
+	Cmd.AddCommand(AnalyticsCmd)
 }
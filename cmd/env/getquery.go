@@ -0,0 +1,45 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"internal/apiclient"
+
+	environments "internal/client/env"
+
+	"github.com/spf13/cobra"
+)
+
+// GetQueryCmd to get the status of an asynchronous analytics query
+var GetQueryCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get the status of an asynchronous analytics query",
+	Long:  "Get the status of an asynchronous analytics query",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = environments.GetQuery(queryID)
+		return
+	},
+}
+
+func init() {
+	GetQueryCmd.Flags().StringVarP(&queryID, "id", "i",
+		"", "Query id")
+
+	_ = GetQueryCmd.MarkFlagRequired("id")
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"fmt"
+	"os"
+
+	"internal/apiclient"
+	"internal/clilog"
+
+	environments "internal/client/env"
+
+	"github.com/spf13/cobra"
+)
+
+// GetQueryResultCmd to download the result of an asynchronous analytics query
+var GetQueryResultCmd = &cobra.Command{
+	Use:   "result",
+	Short: "Download the result of an asynchronous analytics query",
+	Long:  "Download and decompress the result of a completed query and convert it to csv or ndjson",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if err = validateResultFormat(resultFormat); err != nil {
+			return err
+		}
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		return writeQueryResult(queryID)
+	},
+}
+
+var resultFormat, resultFile string
+
+func init() {
+	GetQueryResultCmd.Flags().StringVarP(&queryID, "id", "i",
+		"", "Query id")
+	GetQueryResultCmd.Flags().StringVarP(&resultFormat, "format", "f",
+		"csv", "Format of the result; csv or ndjson")
+	GetQueryResultCmd.Flags().StringVarP(&resultFile, "output", "",
+		"", "Write the result to a file instead of stdout")
+
+	_ = GetQueryResultCmd.MarkFlagRequired("id")
+}
+
+func validateResultFormat(format string) error {
+	if format != "csv" && format != "ndjson" {
+		return fmt.Errorf("format must be csv or ndjson")
+	}
+	return nil
+}
+
+func writeQueryResult(id string) (err error) {
+	result, err := environments.GetQueryResult(id, resultFormat)
+	if err != nil {
+		return err
+	}
+	if resultFile == "" {
+		_, err = os.Stdout.Write(result)
+		return err
+	}
+	if err = os.WriteFile(resultFile, result, 0o644); err != nil {
+		return err
+	}
+	clilog.Info.Printf("Query result written to %s\n", resultFile)
+	return nil
+}
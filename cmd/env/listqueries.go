@@ -0,0 +1,51 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"internal/apiclient"
+
+	environments "internal/client/env"
+
+	"github.com/spf13/cobra"
+)
+
+// ListQueriesCmd to list asynchronous analytics queries
+var ListQueriesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List asynchronous analytics queries in the environment",
+	Long:  "List asynchronous analytics queries in the environment",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = environments.ListQueries(submittedBy, queryStatus, queryFrom, queryTo)
+		return
+	},
+}
+
+var submittedBy, queryStatus, queryFrom, queryTo string
+
+func init() {
+	ListQueriesCmd.Flags().StringVarP(&submittedBy, "submitted-by", "",
+		"", "Email of the user who submitted the queries")
+	ListQueriesCmd.Flags().StringVarP(&queryStatus, "status", "",
+		"", "Filter by status; enqueued, running, completed or failed")
+	ListQueriesCmd.Flags().StringVarP(&queryFrom, "from", "",
+		"", "Filter queries submitted after this time, ex: 2023-01-01T00:00:00Z")
+	ListQueriesCmd.Flags().StringVarP(&queryTo, "to", "",
+		"", "Filter queries submitted before this time, ex: 2023-01-31T00:00:00Z")
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"fmt"
+
+	"internal/apiclient"
+
+	environments "internal/client/env"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// QueryStatsCmd to run an analytics stats query
+var QueryStatsCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Run an analytics stats query",
+	Long: "Run an analytics stats query with any dimensions and metrics and print the results " +
+		"as a table, csv or a json time series",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if err = utils.ValidateReportFormat(statsFormat); err != nil {
+			return err
+		}
+		if sortOrder != "" && sortOrder != "ASC" && sortOrder != "DESC" {
+			return fmt.Errorf("sort must be ASC or DESC")
+		}
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var respBody []byte
+		var series []environments.StatsSeries
+
+		apiclient.ClientPrintHttpResponse.Set(false)
+		defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+		query := environments.StatsQuery{
+			Dimensions:  dimensions,
+			Metrics:     metrics,
+			Filter:      filter,
+			TimeRange:   timeRange,
+			TimeUnit:    timeUnit,
+			Sort:        sortOrder,
+			SortBy:      sortBy,
+			Limit:       limit,
+			TsAscending: tsAscending,
+		}
+
+		if respBody, err = environments.QueryStats(query); err != nil {
+			return err
+		}
+		if series, err = environments.ParseStats(respBody, dimensions); err != nil {
+			return err
+		}
+
+		header, rows := environments.StatsTable(series, dimensions, metrics)
+		return utils.WriteReport(statsFormat, statsOutputFile, header, rows, series)
+	},
+}
+
+var (
+	dimensions, metrics                            []string
+	filter, timeRange, timeUnit, sortOrder, sortBy string
+	statsFormat, statsOutputFile                   string
+	limit                                          int
+	tsAscending                                    bool
+)
+
+func init() {
+	QueryStatsCmd.Flags().StringArrayVarP(&dimensions, "dimensions", "",
+		[]string{}, "Dimension to group by, ex: apiproxy, developer_app. Can be repeated")
+	QueryStatsCmd.Flags().StringArrayVarP(&metrics, "metrics", "",
+		[]string{}, "Metric to select, ex: sum(message_count) or percentile(total_response_time,95). Can be repeated")
+	QueryStatsCmd.Flags().StringVarP(&filter, "filter", "",
+		"", "Filter expression, ex: (response_status_code ge 500)")
+	QueryStatsCmd.Flags().StringVarP(&timeRange, "time-range", "",
+		"", "Time range in the format MM/DD/YYYY HH:MM~MM/DD/YYYY HH:MM")
+	QueryStatsCmd.Flags().StringVarP(&timeUnit, "time-unit", "",
+		"", "Time unit to group by; second, minute, hour, day, week or month")
+	QueryStatsCmd.Flags().StringVarP(&sortOrder, "sort", "",
+		"", "Sort order; ASC or DESC")
+	QueryStatsCmd.Flags().StringVarP(&sortBy, "sortby", "",
+		"", "Metric to sort by, ex: sum(message_count)")
+	QueryStatsCmd.Flags().IntVarP(&limit, "limit", "",
+		0, "Maximum number of results")
+	QueryStatsCmd.Flags().BoolVarP(&tsAscending, "tsAscending", "",
+		false, "Return the time series in ascending order")
+	QueryStatsCmd.Flags().StringVarP(&statsFormat, "format", "f",
+		"table", "Output format; table, csv or json")
+	QueryStatsCmd.Flags().StringVarP(&statsOutputFile, "output", "",
+		"", "Write the results to a file instead of stdout")
+
+	_ = QueryStatsCmd.MarkFlagRequired("metrics")
+	_ = QueryStatsCmd.MarkFlagRequired("time-range")
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iam
+
+import (
+	"internal/apiclient"
+
+	iamaudit "internal/client/iam"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// AuditCmd to report members holding Apigee roles
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report members holding Apigee roles in the project and environments",
+	Long: "Report a matrix of members, roles and scopes from the project IAM policy and the IAM policy " +
+		"of every environment. Over-privileged principals are flagged",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if err = utils.ValidateReportFormat(format); err != nil {
+			return err
+		}
+		apiclient.SetProjectID(projectID)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var entries []iamaudit.AuditEntry
+
+		if entries, err = iamaudit.Audit(allRoles); err != nil {
+			return err
+		}
+
+		if flaggedOnly {
+			flagged := []iamaudit.AuditEntry{}
+			for _, entry := range entries {
+				if entry.Finding != "" {
+					flagged = append(flagged, entry)
+				}
+			}
+			entries = flagged
+		}
+
+		rows := [][]string{}
+		for _, entry := range entries {
+			rows = append(rows, []string{entry.Member, entry.Role, entry.Scope, entry.Finding})
+		}
+
+		return utils.WriteReport(format, outputFile,
+			[]string{"member", "role", "scope", "finding"}, rows, entries)
+	},
+}
+
+var (
+	org, format, outputFile string
+	allRoles, flaggedOnly   bool
+)
+
+func init() {
+	AuditCmd.Flags().StringVarP(&projectID, "prj", "p",
+		"", "GCP Project ID")
+	AuditCmd.Flags().StringVarP(&org, "org", "o",
+		"", "Apigee organization name")
+	AuditCmd.Flags().StringVarP(&format, "format", "f",
+		"table", "Output format, must be one of table, csv or json")
+	AuditCmd.Flags().StringVarP(&outputFile, "output", "",
+		"", "Write the report to a file instead of stdout")
+	AuditCmd.Flags().BoolVarP(&allRoles, "all-roles", "",
+		false, "Include project roles not related to Apigee")
+	AuditCmd.Flags().BoolVarP(&flaggedOnly, "flagged", "",
+		false, "Only report over-privileged principals")
+
+	_ = AuditCmd.MarkFlagRequired("prj")
+}
@@ -35,6 +35,14 @@ var CallCmd = &cobra.Command{
 		if !ValidateRoleType(roleType) {
 			return fmt.Errorf("the role type %s is not a valid type. Please use one of %s", roleType, roles)
 		}
+		if skipKey && keyFile != "" {
+			return fmt.Errorf("key-file cannot be used with skip-key")
+		}
+		// hybrid names the service accounts after the org, env and a hash, they
+		// are listed with kubectl get sa -n <namespace>
+		if wid && len(kubernetesServiceAccounts) == 0 {
+			return fmt.Errorf("ksa must be set with wid, list the service accounts with kubectl get sa -n %s", namespace)
+		}
 		apiclient.SetProjectID(projectID)
 		return nil
 	},
@@ -42,10 +50,22 @@ var CallCmd = &cobra.Command{
 		if generateName {
 			name = GenerateName("apigee-" + roleType + "-")
 		}
-		return apiclient.CreateIAMServiceAccount(name, roleType)
+		if err = apiclient.CreateIAMServiceAccount(name, roleType, !skipKey, keyFile); err != nil {
+			return err
+		}
+		if wid {
+			return apiclient.AddWidMembers(projectID, namespace, kubernetesServiceAccounts, name)
+		}
+		return nil
 	},
 }
 
+var (
+	keyFile                   string
+	skipKey, wid              bool
+	kubernetesServiceAccounts []string
+)
+
 func init() {
 	CallCmd.Flags().StringVarP(&projectID, "prj", "p",
 		"", "GCP Project ID")
@@ -55,6 +75,16 @@ func init() {
 		false, "Generate account name")
 	CallCmd.Flags().StringVarP(&roleType, "role", "r",
 		"", "IAM Role Type, must be one of: "+strings.Join(roles, ", "))
+	CallCmd.Flags().BoolVarP(&skipKey, "skip-key", "",
+		false, "Do not create a key for the service account")
+	CallCmd.Flags().StringVarP(&keyFile, "key-file", "",
+		"", "Path to write the service account key, default is <project>-<name>.json")
+	CallCmd.Flags().BoolVarP(&wid, "wid", "",
+		false, "Grant Workload Identity user role to the Apigee runtime Kubernetes service accounts")
+	CallCmd.Flags().StringVarP(&namespace, "namespace", "",
+		"apigee", "Apigee runtime namespace, used with wid")
+	CallCmd.Flags().StringArrayVarP(&kubernetesServiceAccounts, "ksa", "k",
+		[]string{}, "Kubernetes Service Account Name, can be repeated. Required with wid")
 
 	_ = CallCmd.MarkFlagRequired("prj")
 	_ = CallCmd.MarkFlagRequired("role")
@@ -38,4 +38,5 @@ var roles = []string{
 
 func init() {
 	Cmd.AddCommand(CallCmd)
+	Cmd.AddCommand(AuditCmd)
 }
@@ -31,7 +31,9 @@ var CreateCmd = &cobra.Command{
 	Short: "Create a Key Alias from PEM, PKCS12 or generate self signed cert",
 	Long:  "Create a Key Alias from PEM, PKCS12 or generate self signed cert",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
-		apiclient.SetApigeeEnv(env)
+		if err = setEnv(); err != nil {
+			return err
+		}
 		if format == "pfx" && password == "" {
 			return fmt.Errorf("password must be set for pfx files")
 		}
@@ -59,6 +61,10 @@ var CreateCmd = &cobra.Command{
 				ignoreNewLine,
 				selfFile)
 		case "pem":
+			// catch malformed or mismatched PEMs before uploading
+			if err = keyaliases.ValidateKeyCert(certFile, keyFile, ""); err != nil {
+				return err
+			}
 			_, err = keyaliases.CreateOrUpdateKeyCert(keystoreName,
 				name,
 				false,
@@ -28,7 +28,9 @@ var CsrCmd = &cobra.Command{
 	Short: "Generates a csr for the private key in an alias",
 	Long:  "Generates a PKCS #10 Certificate Signing Request for the private key in an alias",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
-		apiclient.SetApigeeEnv(env)
+		if err = setEnv(); err != nil {
+			return err
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
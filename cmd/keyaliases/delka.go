@@ -28,7 +28,9 @@ var DelCmd = &cobra.Command{
 	Short: "Delete a Key Alias",
 	Long:  "Delete a Key Alias",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
-		apiclient.SetApigeeEnv(env)
+		if err = setEnv(); err != nil {
+			return err
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
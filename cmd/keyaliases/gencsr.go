@@ -0,0 +1,92 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyaliases
+
+import (
+	"crypto/x509/pkix"
+
+	"internal/client/keyaliases"
+
+	"github.com/spf13/cobra"
+)
+
+// GenCsrCmd to generate a key pair and csr locally
+var GenCsrCmd = &cobra.Command{
+	Use:   "gencsr",
+	Short: "Generates a private key and csr locally",
+	Long: "Generates a private key and a PKCS #10 Certificate Signing Request locally. " +
+		"After the CSR is signed by a CA, upload the certificate and key with create --format pem",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		subject := pkix.Name{CommonName: commonName}
+		if country != "" {
+			subject.Country = []string{country}
+		}
+		if state != "" {
+			subject.Province = []string{state}
+		}
+		if locality != "" {
+			subject.Locality = []string{locality}
+		}
+		if organization != "" {
+			subject.Organization = []string{organization}
+		}
+		if orgUnit != "" {
+			subject.OrganizationalUnit = []string{orgUnit}
+		}
+		return keyaliases.GenerateKeyAndCSR(keyType, keySize, subject,
+			dnsNames, ipAddresses, emailAddresses, keyOutFile, csrOutFile, force)
+	},
+}
+
+var (
+	keyType, commonName, country, state, locality string
+	organization, orgUnit, keyOutFile, csrOutFile string
+	keySize                                       int
+	force                                         bool
+	dnsNames, ipAddresses, emailAddresses         []string
+)
+
+func init() {
+	GenCsrCmd.Flags().StringVarP(&keyType, "key-type", "",
+		"rsa", "Type of the private key; rsa or ecdsa")
+	GenCsrCmd.Flags().IntVarP(&keySize, "key-size", "",
+		2048, "Size of the private key; 2048, 3072 or 4096 for rsa, 256, 384 or 521 for ecdsa")
+	GenCsrCmd.Flags().StringVarP(&commonName, "cn", "",
+		"", "Subject common name")
+	GenCsrCmd.Flags().StringVarP(&country, "country", "",
+		"", "Subject country code")
+	GenCsrCmd.Flags().StringVarP(&state, "state", "",
+		"", "Subject state")
+	GenCsrCmd.Flags().StringVarP(&locality, "locality", "",
+		"", "Subject locality")
+	GenCsrCmd.Flags().StringVarP(&organization, "org-name", "",
+		"", "Subject organization")
+	GenCsrCmd.Flags().StringVarP(&orgUnit, "org-unit", "",
+		"", "Subject organizational unit")
+	GenCsrCmd.Flags().StringArrayVarP(&dnsNames, "dns", "",
+		[]string{}, "Subject alternative DNS name, can be repeated")
+	GenCsrCmd.Flags().StringArrayVarP(&ipAddresses, "ip", "",
+		[]string{}, "Subject alternative IP address, can be repeated")
+	GenCsrCmd.Flags().StringArrayVarP(&emailAddresses, "email", "",
+		[]string{}, "Subject alternative email address, can be repeated")
+	GenCsrCmd.Flags().StringVarP(&keyOutFile, "key-out", "",
+		"private.key", "Path to write the private key in PEM format")
+	GenCsrCmd.Flags().StringVarP(&csrOutFile, "csr-out", "",
+		"request.csr", "Path to write the CSR in PEM format")
+	GenCsrCmd.Flags().BoolVarP(&force, "force", "",
+		false, "Overwrite the private key and CSR files if they exist")
+
+	_ = GenCsrCmd.MarkFlagRequired("cn")
+}
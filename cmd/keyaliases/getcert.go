@@ -28,7 +28,9 @@ var GetctCmd = &cobra.Command{
 	Short: "Get a Key alias certificate",
 	Long:  "Get a Key alias certificate",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
-		apiclient.SetApigeeEnv(env)
+		if err = setEnv(); err != nil {
+			return err
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
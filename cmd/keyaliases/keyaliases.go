@@ -15,6 +15,10 @@
 package keyaliases
 
 import (
+	"fmt"
+
+	"internal/apiclient"
+
 	"github.com/spf13/cobra"
 )
 
@@ -34,12 +38,22 @@ func init() {
 	Cmd.PersistentFlags().StringVarP(&env, "env", "e",
 		"", "Apigee environment name")
 
-	_ = Cmd.MarkPersistentFlagRequired("env")
-
 	Cmd.AddCommand(ListCmd)
 	Cmd.AddCommand(CsrCmd)
+	Cmd.AddCommand(GenCsrCmd)
 	Cmd.AddCommand(GetctCmd)
 	Cmd.AddCommand(GetCmd)
 	Cmd.AddCommand(DelCmd)
 	Cmd.AddCommand(CreateCmd)
+	Cmd.AddCommand(RotateCmd)
+}
+
+// setEnv sets the environment of the commands calling Apigee. The env flag is
+// not required by the parent since gencsr runs locally
+func setEnv() error {
+	if env == "" {
+		return fmt.Errorf(`required flag(s) "env" not set`)
+	}
+	apiclient.SetApigeeEnv(env)
+	return nil
 }
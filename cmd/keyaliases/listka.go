@@ -28,7 +28,9 @@ var ListCmd = &cobra.Command{
 	Short: "List Key Aliases",
 	Long:  "List Key Alises",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
-		apiclient.SetApigeeEnv(env)
+		if err = setEnv(); err != nil {
+			return err
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
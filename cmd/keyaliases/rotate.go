@@ -0,0 +1,179 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyaliases
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"internal/client/keyaliases"
+	"internal/client/keystores"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// RotateCmd to rotate the certificate in a key alias
+var RotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate the certificate and key in a Key Alias",
+	Long: "Upload new key material to a versioned keystore and alias, switch the references and target " +
+		"servers using the old keystore and alias to it. All changes are rolled back if a step fails. " +
+		"The rotation is recorded in a file; run the command again with --cleanup after the grace period " +
+		"to delete the old alias",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if err = setEnv(); err != nil {
+			return err
+		}
+		if recordFile == "" {
+			recordFile = "rotation-" + keystoreName + "-" + aliasName + ".json"
+		}
+		if cleanup {
+			return apiclient.SetApigeeOrg(org)
+		}
+		switch format {
+		case "pem":
+			if certFile == "" || !utils.FileExists(certFile) {
+				return fmt.Errorf("certFile was not found")
+			}
+			if keyFile != "" && !utils.FileExists(keyFile) {
+				return fmt.Errorf("keyFile was not found")
+			}
+		case "pkcs12":
+			if pfxFile == "" || !utils.FileExists(pfxFile) {
+				return fmt.Errorf("pfxFile was not found")
+			}
+			if password == "" {
+				return fmt.Errorf("password must be set for pfx files")
+			}
+		default:
+			return fmt.Errorf("format must be pem or pkcs12")
+		}
+		timestamp := time.Now().UTC().Format("20060102150405")
+		if newKeystoreName == "" {
+			newKeystoreName = keystoreName + "-" + timestamp
+		}
+		if newAliasName == "" {
+			newAliasName = aliasName + "-" + timestamp
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var record keystores.RotationRecord
+
+		if cleanup {
+			return cleanupRotation()
+		}
+
+		// validate the material locally before making any changes
+		if format == "pem" {
+			err = keyaliases.ValidateKeyCert(certFile, keyFile, hostname)
+		} else {
+			err = keyaliases.ValidatePfx(pfxFile, password, hostname)
+		}
+		if err != nil {
+			return err
+		}
+
+		if record, err = keystores.Rotate(keystoreName, aliasName, newKeystoreName, newAliasName,
+			format, certFile, keyFile, pfxFile, password, ignoreExpiry, ignoreNewLine); err != nil {
+			return err
+		}
+
+		clilog.Info.Printf("Rotated %s/%s to %s/%s\n", keystoreName, aliasName, newKeystoreName, newAliasName)
+		for _, s := range record.Switched {
+			clilog.Info.Printf("\tswitched %s\n", s)
+		}
+
+		content, err := json.MarshalIndent(record, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(recordFile, content, 0o644); err != nil {
+			return fmt.Errorf("the rotation completed but its record could not be written: %v", err)
+		}
+
+		clilog.Info.Printf("After the grace period, delete the old alias with: "+
+			"apigeecli keyaliases rotate -o %s -e %s -k %s -s %s --cleanup --record %s\n",
+			org, env, keystoreName, aliasName, recordFile)
+		return nil
+	},
+}
+
+// cleanupRotation deletes the old alias of the rotation in the record file
+func cleanupRotation() (err error) {
+	var content []byte
+	var record keystores.RotationRecord
+
+	if content, err = os.ReadFile(recordFile); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(content, &record); err != nil {
+		return fmt.Errorf("invalid rotation record %s: %v", recordFile, err)
+	}
+	if record.Keystore != keystoreName || record.Alias != aliasName {
+		return fmt.Errorf("the rotation record %s is for %s/%s", recordFile, record.Keystore, record.Alias)
+	}
+	return keystores.CleanupRotation(record, gracePeriod)
+}
+
+var (
+	newKeystoreName, newAliasName, hostname string
+	recordFile                              string
+	cleanup                                 bool
+	gracePeriod                             time.Duration
+)
+
+func init() {
+	RotateCmd.Flags().StringVarP(&keystoreName, "key", "k",
+		"", "Name of the key store in use")
+	RotateCmd.Flags().StringVarP(&aliasName, "alias", "s",
+		"", "Name of the key alias in use")
+	RotateCmd.Flags().StringVarP(&newKeystoreName, "new-key", "",
+		"", "Name of the key store for the new material, default is <key>-<timestamp>")
+	RotateCmd.Flags().StringVarP(&newAliasName, "new-alias", "",
+		"", "Name of the key alias for the new material, default is <alias>-<timestamp>")
+	RotateCmd.Flags().StringVarP(&format, "format", "f",
+		"", "Format of the certificate; pem or pkcs12 (file extn is .pfx)")
+	RotateCmd.Flags().StringVarP(&password, "password", "p",
+		"", "PKCS12 password")
+	RotateCmd.Flags().StringVarP(&hostname, "hostname", "",
+		"", "Validate the certificate is valid for this hostname")
+	RotateCmd.Flags().BoolVarP(&ignoreExpiry, "exp", "x",
+		false, "Ignore expiry validation")
+	RotateCmd.Flags().BoolVarP(&ignoreNewLine, "nl", "w",
+		false, "Ignore new line in cert chain")
+	RotateCmd.Flags().StringVarP(&certFile, "certFilePath", "",
+		"", "Path to the X509 certificate in PEM format")
+	RotateCmd.Flags().StringVarP(&keyFile, "keyFilePath", "",
+		"", "Path to the X509 key in PEM format")
+	RotateCmd.Flags().StringVarP(&pfxFile, "pfxFilePath", "",
+		"", "Path to the PFX file")
+	RotateCmd.Flags().BoolVarP(&cleanup, "cleanup", "",
+		false, "Delete the old key alias, and key store when empty, of a completed rotation")
+	RotateCmd.Flags().StringVarP(&recordFile, "record", "",
+		"", "File recording the rotation, default is rotation-<key>-<alias>.json")
+	RotateCmd.Flags().DurationVarP(&gracePeriod, "grace-period", "",
+		24*time.Hour, "Time the old key alias is kept after the rotation before cleanup is allowed")
+
+	_ = RotateCmd.MarkFlagRequired("alias")
+	_ = RotateCmd.MarkFlagRequired("key")
+}
@@ -31,7 +31,9 @@ var UpdateCmd = &cobra.Command{
 	Short: "Create a Key Alias from PEM or PKCS12 file",
 	Long:  "Create a Key Alias from PEM or PKCS12 file",
 	Args: func(cmd *cobra.Command, args []string) (err error) {
-		apiclient.SetApigeeEnv(env)
+		if err = setEnv(); err != nil {
+			return err
+		}
 		if format == "pfx" && password == "" {
 			return fmt.Errorf("password must be set for pfx files")
 		}
@@ -59,6 +61,10 @@ var UpdateCmd = &cobra.Command{
 				ignoreNewLine,
 				selfFile)
 		case "pem":
+			// catch malformed or mismatched PEMs before uploading
+			if err = keyaliases.ValidateKeyCert(certFile, keyFile, ""); err != nil {
+				return err
+			}
 			_, err = keyaliases.CreateOrUpdateKeyCert(keystoreName,
 				name,
 				true,
@@ -41,4 +41,5 @@ func init() {
 	Cmd.AddCommand(DelCmd)
 	Cmd.AddCommand(CreateCmd)
 	Cmd.AddCommand(ImpCmd)
+	Cmd.AddCommand(ScanCmd)
 }
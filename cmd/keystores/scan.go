@@ -0,0 +1,118 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keystores
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/client/keystores"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// ScanCmd to report certificate expiry
+var ScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Report certificate expiry across keystores and aliases",
+	Long: "Report the subject, SANs, issuer and expiry of every certificate in every keystore and alias, " +
+		"along with the target servers and references that use the keystore",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if err = utils.ValidateReportFormat(format); err != nil {
+			return err
+		}
+		if expiringWithin != "" {
+			if expiringDays, err = utils.ParseDays(expiringWithin); err != nil {
+				return err
+			}
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var environments []string
+
+		if scanEnv != "" {
+			environments = append(environments, scanEnv)
+		}
+
+		// aliases that could not be checked are in the report, the error is returned after writing it
+		reports, scanErr := keystores.Scan(environments)
+		if scanErr != nil && reports == nil {
+			return scanErr
+		}
+
+		if expiringWithin != "" {
+			expiring := []keystores.CertificateReport{}
+			for _, report := range reports {
+				if report.Error != "" || report.DaysRemaining <= expiringDays {
+					expiring = append(expiring, report)
+				}
+			}
+			reports = expiring
+		}
+
+		rows := [][]string{}
+		for _, report := range reports {
+			notAfter, daysRemaining := "", ""
+			if report.Error == "" {
+				notAfter, daysRemaining = report.NotAfter.Format(time.RFC3339), strconv.Itoa(report.DaysRemaining)
+			}
+			rows = append(rows, []string{
+				report.Environment, report.Keystore, report.Alias, report.Subject,
+				strings.Join(report.SANs, " "), report.Issuer, notAfter,
+				daysRemaining, strings.Join(report.UsedBy, " "), report.Error,
+			})
+		}
+
+		if err = utils.WriteReport(format, outputFile,
+			[]string{
+				"environment", "keystore", "alias", "subject", "sans",
+				"issuer", "not after", "days remaining", "used by", "error",
+			}, rows, reports); err != nil {
+			return err
+		}
+
+		if scanErr != nil {
+			return scanErr
+		}
+		if expiringWithin != "" && len(reports) > 0 {
+			return fmt.Errorf("%d certificate(s) expire within %s", len(reports), expiringWithin)
+		}
+		return nil
+	},
+}
+
+var (
+	scanEnv, format, outputFile, expiringWithin string
+	expiringDays                                int
+)
+
+func init() {
+	// scan walks all the environments unless one is set
+	ScanCmd.Flags().StringVarP(&scanEnv, "env", "e",
+		"", "Apigee environment name, scans all environments when not set")
+	ScanCmd.Flags().StringVarP(&expiringWithin, "expiring-within", "",
+		"", "Only report certificates expiring within a duration (ex: 30d or 72h) and "+
+			"exit with an error if any are found")
+	ScanCmd.Flags().StringVarP(&format, "format", "f",
+		"table", "Output format, must be one of table, csv or json")
+	ScanCmd.Flags().StringVarP(&outputFile, "output", "",
+		"", "Write the report to a file instead of stdout")
+}
@@ -36,9 +36,16 @@ var ListEntryCmd = &cobra.Command{
 		if env != "" && proxyName != "" {
 			return fmt.Errorf("proxy and env flags cannot be used together")
 		}
+		if all && (pageToken != "" || pageSize != -1) {
+			return fmt.Errorf("all cannot be combined with page-token or page-size")
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if all {
+			_, err = kvm.ListAllEntries(proxyName, mapName)
+			return
+		}
 		_, err = kvm.ListEntries(proxyName, mapName, pageSize, pageToken)
 		return
 	},
@@ -47,6 +54,7 @@ var ListEntryCmd = &cobra.Command{
 var (
 	pageToken string
 	pageSize  int
+	all       bool
 )
 
 func init() {
@@ -60,6 +68,8 @@ func init() {
 		"", "next_page_token from the prior response to be used to fetch the next dataset")
 	ListEntryCmd.Flags().IntVarP(&pageSize, "page-size", "",
 		-1, "Number of items to return on the list")
+	ListEntryCmd.Flags().BoolVarP(&all, "all", "",
+		false, "Fetch every page of entries")
 
 	_ = ListEntryCmd.MarkFlagRequired("map")
 }
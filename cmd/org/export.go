@@ -27,6 +27,7 @@ import (
 	"internal/clilog"
 
 	"internal/client/apis"
+	"internal/client/appgroups"
 	"internal/client/apps"
 	"internal/client/datacollectors"
 	"internal/client/developers"
@@ -37,6 +38,7 @@ import (
 	"internal/client/orgs"
 	"internal/client/products"
 	"internal/client/references"
+	"internal/client/reports"
 	"internal/client/sharedflows"
 	"internal/client/sync"
 	"internal/client/targetservers"
@@ -126,6 +128,16 @@ var ExportCmd = &cobra.Command{
 			return err
 		}
 
+		clilog.Info.Println("Exporting App Groups...")
+		if respBody, err = appgroups.Export(conn); proceedOnError(err) != nil {
+			return err
+		}
+		if err = apiclient.WriteByteArrayToFile(
+			appGroupsFileName,
+			false, respBody); proceedOnError(err) != nil {
+			return err
+		}
+
 		clilog.Info.Println("Exporting Environment Group Configuration...")
 		if respBody, err = envgroups.List(); proceedOnError(err) != nil {
 			return err
@@ -146,6 +158,16 @@ var ExportCmd = &cobra.Command{
 			return err
 		}
 
+		clilog.Info.Println("Exporting Custom Reports...")
+		if respBody, err = reports.Export(); proceedOnError(err) != nil {
+			return err
+		}
+		if err = apiclient.WriteByteArrayToFile(
+			reportsFileName,
+			false, respBody); proceedOnError(err) != nil {
+			return err
+		}
+
 		if runtimeType == "HYBRID" {
 			clilog.Info.Println("Exporting Sync Authorization Identities...")
 			if respBody, err = sync.Get(); err != nil {
@@ -351,6 +373,12 @@ func cleanExportData() (err error) {
 			return err
 		}
 	}
+	if err = os.Remove(path.Join(folder, appGroupsFileName)); err != nil {
+		pathErr, _ := err.(*os.PathError)
+		if pathErr.Err != syscall.ENOENT {
+			return err
+		}
+	}
 	if err = os.Remove(path.Join(folder, "*"+targetServerFileName)); err != nil {
 		pathErr, _ := err.(*os.PathError)
 		if pathErr.Err != syscall.ENOENT {
@@ -369,6 +397,12 @@ func cleanExportData() (err error) {
 			return err
 		}
 	}
+	if err = os.Remove(path.Join(folder, reportsFileName)); err != nil {
+		pathErr, _ := err.(*os.PathError)
+		if pathErr.Err != syscall.ENOENT {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 	"internal/clilog"
 
 	"internal/client/apis"
+	"internal/client/appgroups"
 	"internal/client/apps"
 	"internal/client/datacollectors"
 	"internal/client/developers"
@@ -35,6 +36,7 @@ import (
 	"internal/client/kvm"
 	"internal/client/products"
 	"internal/client/references"
+	"internal/client/reports"
 	"internal/client/sharedflows"
 	"internal/client/targetservers"
 
@@ -111,6 +113,13 @@ var ImportCmd = &cobra.Command{
 			}
 		}
 
+		if utils.FileExists(path.Join(folder, appGroupsFileName)) {
+			clilog.Info.Println("Importing App Groups...")
+			if err = appgroups.Import(conn, path.Join(folder, appGroupsFileName)); err != nil {
+				return err
+			}
+		}
+
 		if utils.FileExists(path.Join(folder, envGroupsFileName)) {
 			clilog.Info.Println("Importing Environment Group Configuration...")
 			if err = envgroups.Import(path.Join(folder, envGroupsFileName)); err != nil {
@@ -125,6 +134,13 @@ var ImportCmd = &cobra.Command{
 			}
 		}
 
+		if utils.FileExists(path.Join(folder, reportsFileName)) {
+			clilog.Info.Println("Importing Custom Reports...")
+			if err = reports.Import(path.Join(folder, reportsFileName)); err != nil {
+				return err
+			}
+		}
+
 		var envRespBody []byte
 		if envRespBody, err = env.List(); err != nil {
 			return err
@@ -29,4 +29,5 @@ var ReportCmd = &cobra.Command{
 func init() {
 	ReportCmd.AddCommand(MonthlyCmd)
 	ReportCmd.AddCommand(YearlyCmd)
+	ReportCmd.AddCommand(UsageCmd)
 }
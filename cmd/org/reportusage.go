@@ -0,0 +1,141 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package org
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"internal/apiclient"
+	"internal/clilog"
+
+	"internal/client/env"
+	"internal/client/orgs"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// UsageCmd to get usage by proxy, developer app and product
+var UsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Report API calls by environment, proxy, developer app and product",
+	Long: "Report API calls by environment, proxy, developer app and product for a month or " +
+		"a custom range, optionally compared with the previous period",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if err = utils.ValidateReportFormat(usageFormat); err != nil {
+			return err
+		}
+		if (month != -1 || year != -1) && (startDate != "" || endDate != "") {
+			return fmt.Errorf("month and year cannot be used with start and end")
+		}
+		if (month == -1) != (year == -1) {
+			return fmt.Errorf("both month and year must be set")
+		}
+		if (startDate == "") != (endDate == "") {
+			return fmt.Errorf("both start and end must be set")
+		}
+		if month == -1 && startDate == "" {
+			return fmt.Errorf("either month and year or start and end must be set")
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var start, end time.Time
+		var rows []env.UsageRow
+
+		if month != -1 {
+			if start, err = time.Parse("1/2006", fmt.Sprintf("%d/%d", month, year)); err != nil {
+				return err
+			}
+			end = start.AddDate(0, 1, 0).Add(-time.Minute)
+		} else {
+			if start, err = time.Parse("2006-01-02", startDate); err != nil {
+				return err
+			}
+			if end, err = time.Parse("2006-01-02", endDate); err != nil {
+				return err
+			}
+			// the end date is inclusive
+			end = end.AddDate(0, 0, 1).Add(-time.Minute)
+		}
+
+		clilog.Warning.Println("This API is rate limited to 1 API Call per second")
+
+		// the report of the environments that could be read is written before
+		// returning the error of the others
+		rows, usageErr := orgs.UsageReport(start, end, usageEnvs, compare, conn)
+		if usageErr != nil && len(rows) == 0 {
+			return usageErr
+		}
+
+		header := []string{"environment", "proxy", "developer app", "api product", "calls"}
+		if compare {
+			header = append(header, "previous calls", "delta", "delta %")
+		}
+
+		table := [][]string{}
+		for _, r := range rows {
+			row := []string{r.Environment, r.Proxy, r.DeveloperApp, r.APIProduct, strconv.FormatInt(r.Calls, 10)}
+			if compare {
+				row = append(row, strconv.FormatInt(*r.PreviousCalls, 10), strconv.FormatInt(*r.Delta, 10),
+					deltaPercent(*r.Delta, *r.PreviousCalls))
+			}
+			table = append(table, row)
+		}
+
+		if err = utils.WriteReport(usageFormat, usageOutputFile, header, table, rows); err != nil {
+			return err
+		}
+		return usageErr
+	},
+}
+
+var (
+	startDate, endDate, usageFormat, usageOutputFile string
+	usageEnvs                                        []string
+	compare                                          bool
+)
+
+func init() {
+	UsageCmd.Flags().IntVarP(&month, "month", "m",
+		-1, "Month")
+	UsageCmd.Flags().IntVarP(&year, "year", "y",
+		-1, "Year")
+	UsageCmd.Flags().StringVarP(&startDate, "start", "",
+		"", "Start date of a custom range, ex: 2023-01-01")
+	UsageCmd.Flags().StringVarP(&endDate, "end", "",
+		"", "End date of a custom range, inclusive, ex: 2023-01-15")
+	UsageCmd.Flags().StringArrayVarP(&usageEnvs, "env", "e",
+		[]string{}, "Environment to include, can be repeated. Default is all environments")
+	UsageCmd.Flags().BoolVarP(&compare, "compare", "",
+		false, "Compare with the previous month or the previous range of the same length")
+	UsageCmd.Flags().StringVarP(&usageFormat, "format", "f",
+		"table", "Output format; table, csv or json")
+	UsageCmd.Flags().StringVarP(&usageOutputFile, "output", "",
+		"", "Write the report to a file instead of stdout")
+	UsageCmd.Flags().IntVarP(&conn, "conn", "c",
+		4, "Number of connections")
+	UsageCmd.Flags().StringVarP(&org, "org", "o",
+		"", "Apigee organization name")
+}
+
+func deltaPercent(delta int64, previous int64) string {
+	if previous == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(float64(delta)*100/float64(previous), 'f', 1, 64)
+}
@@ -27,6 +27,8 @@ const (
 	debugmaskFileName    = "_debugmask.json"
 	tracecfgFileName     = "_tracecfg.json"
 	referencesFileName   = "references.json"
+	reportsFileName      = "reports.json"
+	appGroupsFileName    = "appgroups.json"
 
 	proxiesFolderName     = "proxies"
 	sharedFlowsFolderName = "sharedflows"
@@ -0,0 +1,42 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// APIDocsCmd to manage the catalog items of a portal
+var APIDocsCmd = &cobra.Command{
+	Use:     "apidocs",
+	Aliases: []string{"catalog"},
+	Short:   "Manage the API catalog of a portal",
+	Long:    "Manage the API catalog items of an integrated developer portal",
+}
+
+var (
+	id, title, description, apiProduct, imageURL string
+	published, anonAllowed, requireCallbackURL   bool
+	categories                                   []string
+)
+
+func init() {
+	APIDocsCmd.AddCommand(CreateAPIDocCmd)
+	APIDocsCmd.AddCommand(GetAPIDocCmd)
+	APIDocsCmd.AddCommand(ListAPIDocsCmd)
+	APIDocsCmd.AddCommand(UpdateAPIDocCmd)
+	APIDocsCmd.AddCommand(DelAPIDocCmd)
+	APIDocsCmd.AddCommand(PublishCmd)
+}
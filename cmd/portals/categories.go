@@ -0,0 +1,36 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// CategoriesCmd to manage the catalog categories of a portal
+var CategoriesCmd = &cobra.Command{
+	Use:   "categories",
+	Short: "Manage the API categories of a portal",
+	Long:  "Manage the categories used to group catalog items in an integrated developer portal",
+}
+
+var categoryName string
+
+func init() {
+	CategoriesCmd.AddCommand(CreateCategoryCmd)
+	CategoriesCmd.AddCommand(GetCategoryCmd)
+	CategoriesCmd.AddCommand(ListCategoriesCmd)
+	CategoriesCmd.AddCommand(UpdateCategoryCmd)
+	CategoriesCmd.AddCommand(DelCategoryCmd)
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"internal/apiclient"
+
+	"internal/client/portals"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateAPIDocCmd to create a catalog item
+var CreateAPIDocCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a catalog item",
+	Long:  "Create a catalog item for an API Product in the portal",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		apiDoc := portals.APIDoc{
+			Title:              title,
+			Description:        description,
+			APIProductName:     apiProduct,
+			Published:          published,
+			AnonAllowed:        anonAllowed,
+			RequireCallbackURL: requireCallbackURL,
+			ImageURL:           imageURL,
+		}
+		if apiDoc.CategoryIDs, err = portals.ResolveCategories(siteID, categories, false); err != nil {
+			return err
+		}
+		_, err = portals.CreateAPIDoc(siteID, apiDoc)
+		return
+	},
+}
+
+func init() {
+	CreateAPIDocCmd.Flags().StringVarP(&title, "title", "t",
+		"", "Title of the catalog item")
+	CreateAPIDocCmd.Flags().StringVarP(&description, "description", "d",
+		"", "Description of the catalog item")
+	CreateAPIDocCmd.Flags().StringVarP(&apiProduct, "product", "p",
+		"", "Name of the API Product")
+	CreateAPIDocCmd.Flags().StringVarP(&imageURL, "image-url", "",
+		"", "URL of the image shown in the catalog")
+	CreateAPIDocCmd.Flags().StringArrayVarP(&categories, "category", "c",
+		[]string{}, "Name or id of a category, can be repeated")
+	CreateAPIDocCmd.Flags().BoolVarP(&published, "published", "",
+		false, "Publish the catalog item")
+	CreateAPIDocCmd.Flags().BoolVarP(&anonAllowed, "anon-allowed", "",
+		false, "Allow anonymous users to view the catalog item")
+	CreateAPIDocCmd.Flags().BoolVarP(&requireCallbackURL, "require-callback-url", "",
+		false, "Require a callback url when registering apps")
+
+	_ = CreateAPIDocCmd.MarkFlagRequired("title")
+	_ = CreateAPIDocCmd.MarkFlagRequired("product")
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"internal/apiclient"
+
+	"internal/client/portals"
+
+	"github.com/spf13/cobra"
+)
+
+// DelAPIDocCmd to delete a catalog item
+var DelAPIDocCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Deletes a catalog item",
+	Long:  "Deletes a catalog item",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = portals.DeleteAPIDoc(siteID, id)
+		return
+	},
+}
+
+func init() {
+	DelAPIDocCmd.Flags().StringVarP(&id, "id", "i",
+		"", "Id of the catalog item")
+
+	_ = DelAPIDocCmd.MarkFlagRequired("id")
+}
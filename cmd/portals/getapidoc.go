@@ -0,0 +1,52 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"internal/apiclient"
+
+	"internal/client/portals"
+
+	"github.com/spf13/cobra"
+)
+
+// GetAPIDocCmd to get a catalog item
+var GetAPIDocCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Returns a catalog item",
+	Long:  "Returns a catalog item, or the spec uploaded to it",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if spec {
+			_, err = portals.GetDocumentation(siteID, id)
+			return
+		}
+		_, err = portals.GetAPIDoc(siteID, id)
+		return
+	},
+}
+
+var spec bool
+
+func init() {
+	GetAPIDocCmd.Flags().StringVarP(&id, "id", "i",
+		"", "Id of the catalog item")
+	GetAPIDocCmd.Flags().BoolVarP(&spec, "spec", "",
+		false, "Return the spec uploaded to the catalog item")
+
+	_ = GetAPIDocCmd.MarkFlagRequired("id")
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"internal/apiclient"
+
+	"internal/client/portals"
+
+	"github.com/spf13/cobra"
+)
+
+// ListAPIDocsCmd to list catalog items
+var ListAPIDocsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Returns the catalog items of a portal",
+	Long:  "Returns the catalog items of a portal",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = portals.ListAPIDocs(siteID, pageSize, pageToken)
+		return
+	},
+}
+
+var (
+	pageSize  int
+	pageToken string
+)
+
+func init() {
+	ListAPIDocsCmd.Flags().IntVarP(&pageSize, "page-size", "",
+		-1, "Number of catalog items; limit is 100")
+	ListAPIDocsCmd.Flags().StringVarP(&pageToken, "page-token", "",
+		"", "Token returned by a previous list call")
+}
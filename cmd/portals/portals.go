@@ -0,0 +1,40 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd to manage integrated developer portals
+var Cmd = &cobra.Command{
+	Use:   "portals",
+	Short: "Manage Apigee integrated developer portals",
+	Long:  "Manage the API catalog and categories of Apigee integrated developer portals",
+}
+
+var org, siteID string
+
+func init() {
+	Cmd.PersistentFlags().StringVarP(&org, "org", "o",
+		"", "Apigee organization name")
+	Cmd.PersistentFlags().StringVarP(&siteID, "site", "s",
+		"", "Portal site id, ex: myorg-myportal")
+
+	_ = Cmd.MarkPersistentFlagRequired("site")
+
+	Cmd.AddCommand(APIDocsCmd)
+	Cmd.AddCommand(CategoriesCmd)
+}
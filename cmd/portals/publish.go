@@ -0,0 +1,68 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"internal/apiclient"
+
+	bundle "internal/bundlegen"
+
+	"internal/client/portals"
+
+	"github.com/spf13/cobra"
+)
+
+// PublishCmd to publish an OpenAPI spec to the catalog
+var PublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Publish an OpenAPI spec to the catalog",
+	Long: "Create or update the catalog item of an API Product from an OpenAPI spec. " +
+		"The title, description and image (x-logo) are read from the spec info and the spec is uploaded " +
+		"as the documentation of the catalog item. Categories that do not exist are created",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		specName, content, err := bundle.NewGenerator().LoadDocumentFromFile(specFile, validateSpec, false)
+		if err != nil {
+			return err
+		}
+		_, err = portals.Publish(siteID, apiProduct, specName, content, categories, published, anonAllowed)
+		return
+	},
+}
+
+var (
+	specFile     string
+	validateSpec bool
+)
+
+func init() {
+	PublishCmd.Flags().StringVarP(&specFile, "spec", "f",
+		"", "OpenAPI 3.0 Specification file")
+	PublishCmd.Flags().StringVarP(&apiProduct, "product", "p",
+		"", "Name of the API Product")
+	PublishCmd.Flags().StringArrayVarP(&categories, "category", "c",
+		[]string{}, "Name or id of a category, can be repeated")
+	PublishCmd.Flags().BoolVarP(&published, "published", "",
+		true, "Publish the catalog item")
+	PublishCmd.Flags().BoolVarP(&anonAllowed, "anon-allowed", "",
+		false, "Allow anonymous users to view the catalog item")
+	PublishCmd.Flags().BoolVarP(&validateSpec, "validate", "",
+		true, "Validate the spec before publishing")
+
+	_ = PublishCmd.MarkFlagRequired("spec")
+	_ = PublishCmd.MarkFlagRequired("product")
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"internal/apiclient"
+
+	"internal/client/portals"
+
+	"github.com/spf13/cobra"
+)
+
+// UpdateAPIDocCmd to update a catalog item
+var UpdateAPIDocCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update a catalog item",
+	Long:  "Update a catalog item, only the flags that are set are changed",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		apiDoc, err := portals.GetAPIDocData(siteID, id)
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("title") {
+			apiDoc.Title = title
+		}
+		if flags.Changed("description") {
+			apiDoc.Description = description
+		}
+		if flags.Changed("product") {
+			apiDoc.APIProductName = apiProduct
+		}
+		if flags.Changed("image-url") {
+			apiDoc.ImageURL = imageURL
+		}
+		if flags.Changed("published") {
+			apiDoc.Published = published
+		}
+		if flags.Changed("anon-allowed") {
+			apiDoc.AnonAllowed = anonAllowed
+		}
+		if flags.Changed("require-callback-url") {
+			apiDoc.RequireCallbackURL = requireCallbackURL
+		}
+		if flags.Changed("category") {
+			if apiDoc.CategoryIDs, err = portals.ResolveCategories(siteID, categories, false); err != nil {
+				return err
+			}
+		}
+
+		_, err = portals.UpdateAPIDoc(siteID, id, apiDoc)
+		return
+	},
+}
+
+func init() {
+	UpdateAPIDocCmd.Flags().StringVarP(&id, "id", "i",
+		"", "Id of the catalog item")
+	UpdateAPIDocCmd.Flags().StringVarP(&title, "title", "t",
+		"", "Title of the catalog item")
+	UpdateAPIDocCmd.Flags().StringVarP(&description, "description", "d",
+		"", "Description of the catalog item")
+	UpdateAPIDocCmd.Flags().StringVarP(&apiProduct, "product", "p",
+		"", "Name of the API Product")
+	UpdateAPIDocCmd.Flags().StringVarP(&imageURL, "image-url", "",
+		"", "URL of the image shown in the catalog")
+	UpdateAPIDocCmd.Flags().StringArrayVarP(&categories, "category", "c",
+		[]string{}, "Name or id of a category, can be repeated. Replaces the categories")
+	UpdateAPIDocCmd.Flags().BoolVarP(&published, "published", "",
+		false, "Publish the catalog item")
+	UpdateAPIDocCmd.Flags().BoolVarP(&anonAllowed, "anon-allowed", "",
+		false, "Allow anonymous users to view the catalog item")
+	UpdateAPIDocCmd.Flags().BoolVarP(&requireCallbackURL, "require-callback-url", "",
+		false, "Require a callback url when registering apps")
+
+	_ = UpdateAPIDocCmd.MarkFlagRequired("id")
+}
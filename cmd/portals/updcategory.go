@@ -0,0 +1,47 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"internal/apiclient"
+
+	"internal/client/portals"
+
+	"github.com/spf13/cobra"
+)
+
+// UpdateCategoryCmd to rename a category
+var UpdateCategoryCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Rename a category",
+	Long:  "Rename a category",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = portals.UpdateCategory(siteID, id, categoryName)
+		return
+	},
+}
+
+func init() {
+	UpdateCategoryCmd.Flags().StringVarP(&id, "id", "i",
+		"", "Id of the category")
+	UpdateCategoryCmd.Flags().StringVarP(&categoryName, "name", "n",
+		"", "New name of the category")
+
+	_ = UpdateCategoryCmd.MarkFlagRequired("id")
+	_ = UpdateCategoryCmd.MarkFlagRequired("name")
+}
@@ -102,4 +102,6 @@ func init() {
 
 	_ = CreateCmd.MarkFlagRequired("name")
 	_ = CreateCmd.MarkFlagRequired("approval")
+
+	CreateCmd.AddCommand(CreateOasCmd)
 }
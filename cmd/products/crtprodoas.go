@@ -0,0 +1,222 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package products
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	bundle "internal/bundlegen"
+
+	"internal/client/products"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateOasCmd to create API products from an OpenAPI spec
+var CreateOasCmd = &cobra.Command{
+	Use:     "openapi",
+	Aliases: []string{"oas"},
+	Short:   "Create API products from an OpenAPI Specification",
+	Long: "Create API products with an operation group derived from the operations of an OpenAPI Specification. " +
+		"Operation quotas are read from the x-google-quota or x-google-ratelimit extensions",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if byTag && len(tagGroups) > 0 {
+			return fmt.Errorf("by-tag and group cannot be combined")
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		g := bundle.NewGenerator()
+
+		if strings.HasPrefix(specFile, "http://") || strings.HasPrefix(specFile, "https://") {
+			_, _, err = g.LoadDocumentFromURI(specFile, false, false)
+		} else {
+			_, _, err = g.LoadDocumentFromFile(specFile, false, false)
+		}
+		if err != nil {
+			return err
+		}
+
+		operations, err := g.GetOperations()
+		if err != nil {
+			return err
+		}
+
+		productGroups, err := getProductGroups(operations)
+		if err != nil {
+			return err
+		}
+
+		for _, group := range productGroups {
+			p := products.APIProduct{}
+
+			p.Name = group.name
+			p.DisplayName = group.displayName
+			p.ApprovalType = approval
+			p.Description = description
+			p.Environments = environments
+			p.Scopes = scopes
+			p.Attributes = getAttributes(attrs)
+			p.OperationGroup = &products.OperationGroup{}
+
+			for _, operation := range operations {
+				if !group.match(operation) {
+					continue
+				}
+				limit, interval, timeUnit := "", "", ""
+				if operation.Quota != nil {
+					limit, interval, timeUnit = operation.Quota.Limit, operation.Quota.Interval, operation.Quota.TimeUnit
+				}
+				p.OperationGroup.AddOperation(proxyName, operation.Resource, operation.Method, limit, interval, timeUnit)
+			}
+
+			if len(p.OperationGroup.OperationConfigs) == 0 {
+				clilog.Warning.Printf("No operations found for API product %s, skipping\n", p.Name)
+				continue
+			}
+
+			if _, err = products.Create(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+type productGroup struct {
+	name, displayName string
+	match             func(operation bundle.Operation) bool
+}
+
+// getProductGroups returns the API products to create and the operations they include
+func getProductGroups(operations []bundle.Operation) (groups []productGroup, err error) {
+	if displayName == "" {
+		displayName = name
+	}
+
+	// * matches every operation, including the untagged ones
+	hasTag := func(tags []string) func(operation bundle.Operation) bool {
+		return func(operation bundle.Operation) bool {
+			for _, t := range tags {
+				if t == "*" {
+					return true
+				}
+				for _, tag := range operation.Tags {
+					if tag == t {
+						return true
+					}
+				}
+			}
+			return false
+		}
+	}
+
+	switch {
+	case byTag:
+		tags := map[string]bool{}
+		untagged := false
+		for _, operation := range operations {
+			for _, tag := range operation.Tags {
+				tags[tag] = true
+			}
+			untagged = untagged || len(operation.Tags) == 0
+		}
+		sortedTags := []string{}
+		for tag := range tags {
+			sortedTags = append(sortedTags, tag)
+		}
+		sort.Strings(sortedTags)
+		for _, tag := range sortedTags {
+			groups = append(groups, productGroup{
+				name:        productName(tag),
+				displayName: displayName + " (" + tag + ")",
+				match:       hasTag([]string{tag}),
+			})
+		}
+		if untagged {
+			groups = append(groups, productGroup{
+				name:        name,
+				displayName: displayName,
+				match:       func(operation bundle.Operation) bool { return len(operation.Tags) == 0 },
+			})
+		}
+	case len(tagGroups) > 0:
+		for _, tagGroup := range tagGroups {
+			suffix, tags, found := strings.Cut(tagGroup, "=")
+			if !found || suffix == "" || tags == "" {
+				return nil, fmt.Errorf("invalid group %s, must be of the form name=tag1,tag2", tagGroup)
+			}
+			groups = append(groups, productGroup{
+				name:        productName(suffix),
+				displayName: displayName + " (" + suffix + ")",
+				match:       hasTag(strings.Split(tags, ",")),
+			})
+		}
+	default:
+		groups = append(groups, productGroup{
+			name:        name,
+			displayName: displayName,
+			match:       func(operation bundle.Operation) bool { return true },
+		})
+	}
+	return groups, nil
+}
+
+func productName(suffix string) string {
+	return name + "-" + regexp.MustCompile(`[^a-zA-Z0-9_-]+`).ReplaceAllString(suffix, "-")
+}
+
+var (
+	specFile, proxyName string
+	byTag               bool
+	tagGroups           []string
+)
+
+func init() {
+	CreateOasCmd.Flags().StringVarP(&name, "name", "n",
+		"", "Name of the API Product, used as the prefix when grouping by tag")
+	CreateOasCmd.Flags().StringVarP(&displayName, "displayname", "m",
+		"", "Display Name of the API Product")
+	CreateOasCmd.Flags().StringVarP(&description, "desc", "d",
+		"", "Description for the API Product")
+	CreateOasCmd.Flags().StringVarP(&specFile, "spec", "",
+		"", "Path or URL of the Open API 3.0 Specification")
+	CreateOasCmd.Flags().StringVarP(&proxyName, "proxy", "",
+		"", "API Proxy generated from the spec")
+	CreateOasCmd.Flags().StringArrayVarP(&environments, "envs", "e",
+		[]string{}, "Environments to enable")
+	CreateOasCmd.Flags().StringArrayVarP(&scopes, "scopes", "s",
+		[]string{}, "OAuth scopes")
+	CreateOasCmd.Flags().StringVarP(&approval, "approval", "f",
+		"auto", "Approval type")
+	CreateOasCmd.Flags().StringToStringVar(&attrs, "attrs",
+		nil, "Custom attributes")
+	CreateOasCmd.Flags().BoolVarP(&byTag, "by-tag", "",
+		false, "Create an API product per tag, named <name>-<tag>")
+	CreateOasCmd.Flags().StringArrayVarP(&tagGroups, "group", "",
+		[]string{}, "Create an API product <name>-<group> with the operations of the tags, "+
+			"ex: --group read-only=pets --group full=*")
+
+	_ = CreateOasCmd.MarkFlagRequired("name")
+	_ = CreateOasCmd.MarkFlagRequired("spec")
+	_ = CreateOasCmd.MarkFlagRequired("proxy")
+}
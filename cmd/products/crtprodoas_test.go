@@ -0,0 +1,112 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package products
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	bundle "internal/bundlegen"
+)
+
+func TestGetProductGroups(t *testing.T) {
+	operations := []bundle.Operation{
+		{OperationID: "listPets", Tags: []string{"pets"}},
+		{OperationID: "addPet", Tags: []string{"pets", "admin"}},
+		{OperationID: "getStore", Tags: []string{"store"}},
+		{OperationID: "health"},
+	}
+
+	tests := []struct {
+		name      string
+		byTag     bool
+		tagGroups []string
+		want      map[string][]string
+		wantErr   string
+	}{
+		{
+			name: "single product",
+			want: map[string][]string{"petstore": {"listPets", "addPet", "getStore", "health"}},
+		},
+		{
+			name:  "by tag",
+			byTag: true,
+			want: map[string][]string{
+				"petstore-admin": {"addPet"},
+				"petstore-pets":  {"listPets", "addPet"},
+				"petstore-store": {"getStore"},
+				"petstore":       {"health"},
+			},
+		},
+		{
+			name:      "groups of tags",
+			tagGroups: []string{"read=pets,store", "admin=admin"},
+			want: map[string][]string{
+				"petstore-read":  {"listPets", "addPet", "getStore"},
+				"petstore-admin": {"addPet"},
+			},
+		},
+		{
+			name:      "wildcard includes untagged operations",
+			tagGroups: []string{"all=*"},
+			want:      map[string][]string{"petstore-all": {"listPets", "addPet", "getStore", "health"}},
+		},
+		{
+			name:      "wildcard with other tags",
+			tagGroups: []string{"full=*,admin"},
+			want:      map[string][]string{"petstore-full": {"listPets", "addPet", "getStore", "health"}},
+		},
+		{
+			name:      "group name is sanitized",
+			tagGroups: []string{"read only=store"},
+			want:      map[string][]string{"petstore-read-only": {"getStore"}},
+		},
+		{
+			name:      "invalid group",
+			tagGroups: []string{"read"},
+			wantErr:   "invalid group read",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, displayName, byTag, tagGroups = "petstore", "", tt.byTag, tt.tagGroups
+
+			groups, err := getProductGroups(operations)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("getProductGroups() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getProductGroups() error = %v", err)
+			}
+
+			got := map[string][]string{}
+			for _, group := range groups {
+				for _, operation := range operations {
+					if group.match(operation) {
+						got[group.name] = append(got[group.name], operation.OperationID)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getProductGroups() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -37,11 +37,16 @@ var ListCmd = &cobra.Command{
 			return fmt.Errorf("invalid filter options. Filter option must be proxies, " +
 				"expand must be set to true and count cannot be set")
 		}
+		if all && (count != -1 || startKey != "") {
+			return fmt.Errorf("all cannot be combined with count or start")
+		}
 		return apiclient.SetApigeeOrg(org)
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		if len(filter) > 0 {
 			_, err = products.ListFilter(filter)
+		} else if all {
+			_, err = products.ListAll(expand)
 		} else {
 			_, err = products.List(count, startKey, expand)
 		}
@@ -54,6 +59,7 @@ var (
 	count    int
 	filter   map[string]string
 	startKey string
+	all      bool
 )
 
 func init() {
@@ -71,4 +77,7 @@ func init() {
 
 	ListCmd.Flags().BoolVarP(&expand, "expand", "x",
 		false, "Expand Details")
+
+	ListCmd.Flags().BoolVarP(&all, "all", "",
+		false, "Fetch every page of products")
 }
@@ -0,0 +1,50 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reports
+
+import (
+	"internal/apiclient"
+
+	"internal/client/reports"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateCmd to create a custom report
+var CreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a custom report",
+	Long:  "Create a custom report from flags or a json file, flags override values in the file",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		report := reports.CustomReport{}
+		if filePath != "" {
+			if report, err = reports.ReadReportFile(filePath); err != nil {
+				return err
+			}
+		}
+		if report, err = buildReport(cmd, report); err != nil {
+			return err
+		}
+		_, err = reports.Create(report)
+		return
+	},
+}
+
+func init() {
+	addReportFlags(CreateCmd)
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reports
+
+import (
+	"internal/apiclient"
+
+	"internal/client/reports"
+
+	"github.com/spf13/cobra"
+)
+
+// ImpCmd to import custom reports
+var ImpCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import custom reports",
+	Long:  "Import custom reports, reports with the same display name are updated",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		return reports.Import(importFile)
+	},
+}
+
+var importFile string
+
+func init() {
+	ImpCmd.Flags().StringVarP(&importFile, "file", "f",
+		"", "File containing custom reports")
+
+	_ = ImpCmd.MarkFlagRequired("file")
+}
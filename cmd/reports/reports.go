@@ -0,0 +1,117 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reports
+
+import (
+	"fmt"
+	"strconv"
+
+	"internal/client/reports"
+
+	"github.com/spf13/cobra"
+)
+
+// Cmd to manage custom reports
+var Cmd = &cobra.Command{
+	Use:   "reports",
+	Short: "Manage Apigee analytics custom reports",
+	Long:  "Manage Apigee analytics custom reports",
+}
+
+var (
+	org, name, displayName, filter, chartType, timeUnit string
+	sortOrder, filePath                                 string
+	dimensions, metrics, sortBy                         []string
+	limit                                               int
+)
+
+func init() {
+	Cmd.PersistentFlags().StringVarP(&org, "org", "o",
+		"", "Apigee organization name")
+
+	Cmd.AddCommand(CreateCmd)
+	Cmd.AddCommand(GetCmd)
+	Cmd.AddCommand(ListCmd)
+	Cmd.AddCommand(UpdateCmd)
+	Cmd.AddCommand(DelCmd)
+	Cmd.AddCommand(ExpCmd)
+	Cmd.AddCommand(ImpCmd)
+}
+
+// addReportFlags adds the flags to define a report to create and update
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&displayName, "display-name", "d",
+		"", "Display name of the report")
+	cmd.Flags().StringArrayVarP(&dimensions, "dimensions", "",
+		[]string{}, "Dimension of the report, ex: apiproxy. Can be repeated")
+	cmd.Flags().StringArrayVarP(&metrics, "metrics", "",
+		[]string{}, "Metric of the report in the form function(name), ex: sum(message_count). Can be repeated")
+	cmd.Flags().StringVarP(&filter, "filter", "",
+		"", "Filter expression, ex: (response_status_code ge 500)")
+	cmd.Flags().StringVarP(&chartType, "chart-type", "",
+		"", "Chart type; column, bar, line, pie or area")
+	cmd.Flags().StringVarP(&timeUnit, "time-unit", "",
+		"", "Time unit; minute, hour, day, week or month")
+	cmd.Flags().StringArrayVarP(&sortBy, "sort-by", "",
+		[]string{}, "Column to sort by, can be repeated")
+	cmd.Flags().StringVarP(&sortOrder, "sort-order", "",
+		"", "Sort order; ASC or DESC")
+	cmd.Flags().IntVarP(&limit, "limit", "",
+		0, "Maximum number of rows")
+	cmd.Flags().StringVarP(&filePath, "file", "f",
+		"", "Path to a json file with the report definition")
+}
+
+// buildReport applies the flags that were set to the report
+func buildReport(cmd *cobra.Command, report reports.CustomReport) (reports.CustomReport, error) {
+	if cmd.Flags().Changed("display-name") {
+		report.DisplayName = displayName
+	}
+	if cmd.Flags().Changed("dimensions") {
+		report.Dimensions = dimensions
+	}
+	if cmd.Flags().Changed("metrics") {
+		report.Metrics = nil
+		for _, m := range metrics {
+			metric, err := reports.ParseMetric(m)
+			if err != nil {
+				return report, err
+			}
+			report.Metrics = append(report.Metrics, metric)
+		}
+	}
+	if cmd.Flags().Changed("filter") {
+		report.Filter = filter
+	}
+	if cmd.Flags().Changed("chart-type") {
+		report.ChartType = reports.ChartType(chartType)
+	}
+	if cmd.Flags().Changed("time-unit") {
+		report.TimeUnit = timeUnit
+	}
+	if cmd.Flags().Changed("sort-by") {
+		report.SortByCols = sortBy
+	}
+	if cmd.Flags().Changed("sort-order") {
+		if sortOrder != "ASC" && sortOrder != "DESC" {
+			return report, fmt.Errorf("sort-order must be ASC or DESC")
+		}
+		report.SortOrder = sortOrder
+	}
+	if cmd.Flags().Changed("limit") {
+		report.Limit = strconv.Itoa(limit)
+	}
+	return report, report.Validate()
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reports
+
+import (
+	"internal/apiclient"
+
+	"internal/client/reports"
+
+	"github.com/spf13/cobra"
+)
+
+// UpdateCmd to update a custom report
+var UpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update a custom report",
+	Long: "Update a custom report. The report is replaced by the json file when one is set, " +
+		"otherwise the flags are applied to the current report",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var report reports.CustomReport
+		if filePath != "" {
+			report, err = reports.ReadReportFile(filePath)
+		} else {
+			report, err = reports.GetReport(name)
+		}
+		if err != nil {
+			return err
+		}
+		if report, err = buildReport(cmd, report); err != nil {
+			return err
+		}
+		_, err = reports.Update(name, report)
+		return
+	},
+}
+
+func init() {
+	UpdateCmd.Flags().StringVarP(&name, "name", "n",
+		"", "Name (id) of the custom report")
+	addReportFlags(UpdateCmd)
+
+	_ = UpdateCmd.MarkFlagRequired("name")
+}
@@ -28,6 +28,7 @@ import (
 	"internal/clilog"
 
 	"github.com/apigee/apigeecli/cmd/apis"
+	"github.com/apigee/apigeecli/cmd/appgroups"
 	"github.com/apigee/apigeecli/cmd/apps"
 	cache "github.com/apigee/apigeecli/cmd/cache"
 	"github.com/apigee/apigeecli/cmd/datacollectors"
@@ -44,11 +45,14 @@ import (
 	"github.com/apigee/apigeecli/cmd/ops"
 	"github.com/apigee/apigeecli/cmd/org"
 	"github.com/apigee/apigeecli/cmd/overrides"
+	"github.com/apigee/apigeecli/cmd/portals"
 	"github.com/apigee/apigeecli/cmd/preferences"
 	"github.com/apigee/apigeecli/cmd/products"
 	"github.com/apigee/apigeecli/cmd/projects"
 	"github.com/apigee/apigeecli/cmd/references"
+	"github.com/apigee/apigeecli/cmd/reports"
 	res "github.com/apigee/apigeecli/cmd/res"
+	"github.com/apigee/apigeecli/cmd/security"
 	"github.com/apigee/apigeecli/cmd/sharedflows"
 	"github.com/apigee/apigeecli/cmd/sync"
 	targetservers "github.com/apigee/apigeecli/cmd/targetservers"
@@ -125,6 +129,7 @@ func init() {
 	RootCmd.AddCommand(datacollectors.Cmd)
 	RootCmd.AddCommand(developers.Cmd)
 	RootCmd.AddCommand(apps.Cmd)
+	RootCmd.AddCommand(appgroups.Cmd)
 	RootCmd.AddCommand(sharedflows.Cmd)
 	RootCmd.AddCommand(kvm.Cmd)
 	RootCmd.AddCommand(flowhooks.Cmd)
@@ -142,6 +147,9 @@ func init() {
 	RootCmd.AddCommand(preferences.Cmd)
 	RootCmd.AddCommand(overrides.Cmd)
 	RootCmd.AddCommand(eptattachment.Cmd)
+	RootCmd.AddCommand(reports.Cmd)
+	RootCmd.AddCommand(portals.Cmd)
+	RootCmd.AddCommand(security.Cmd)
 }
 
 func initConfig() {
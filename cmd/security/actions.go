@@ -0,0 +1,45 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ActionsCmd to manage security actions
+var ActionsCmd = &cobra.Command{
+	Use:   "actions",
+	Short: "Manage security actions for the environment",
+	Long:  "Manage security actions that allow, deny or flag traffic in the environment",
+}
+
+var actionID string
+
+func init() {
+	ActionsCmd.PersistentFlags().StringVarP(&environment, "env", "e",
+		"", "Apigee environment name")
+
+	_ = ActionsCmd.MarkPersistentFlagRequired("env")
+
+	ActionsCmd.AddCommand(CreateActionCmd)
+	ActionsCmd.AddCommand(GetActionCmd)
+	ActionsCmd.AddCommand(ListActionsCmd)
+	ActionsCmd.AddCommand(UpdateActionCmd)
+	ActionsCmd.AddCommand(DelActionCmd)
+	ActionsCmd.AddCommand(EnableActionCmd)
+	ActionsCmd.AddCommand(DisableActionCmd)
+	ActionsCmd.AddCommand(ExpActionsCmd)
+	ActionsCmd.AddCommand(ImpActionsCmd)
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// AttachProfileCmd to attach a security profile to an environment
+var AttachProfileCmd = &cobra.Command{
+	Use:   "attach",
+	Short: "Attach a security profile to an environment",
+	Long:  "Attach a security profile to an environment",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = security.AttachProfile(profileName, environment)
+		return
+	},
+}
+
+func init() {
+	AttachProfileCmd.Flags().StringVarP(&profileName, "name", "n",
+		"", "Name of the security profile")
+	AttachProfileCmd.Flags().StringVarP(&environment, "env", "e",
+		"", "Apigee environment name")
+
+	_ = AttachProfileCmd.MarkFlagRequired("name")
+	_ = AttachProfileCmd.MarkFlagRequired("env")
+}
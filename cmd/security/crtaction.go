@@ -0,0 +1,111 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"fmt"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateActionCmd to create a security action
+var CreateActionCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a security action",
+	Long: "Create a security action from a json definition, or one that allows, denies or flags " +
+		"the traffic from ip address ranges, api keys, developers or developer apps",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if filePath == "" && actionType == "" {
+			return fmt.Errorf("either file or action must be set")
+		}
+		if filePath != "" && actionType != "" {
+			return fmt.Errorf("file and action cannot be combined")
+		}
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if filePath != "" {
+			_, err = security.CreateActionFromFile(actionID, filePath)
+			return
+		}
+
+		conditions := security.ConditionConfig{
+			IPAddressRanges: ipAddressRanges,
+			APIKeys:         apiKeys,
+			Developers:      developers,
+			DeveloperApps:   developerApps,
+			UserAgents:      userAgents,
+			RegionCodes:     regionCodes,
+		}
+		action, err := security.NewSecurityAction(actionType, description, conditions, responseCode, headers)
+		if err != nil {
+			return err
+		}
+		action.APIProxies = apiProxies
+		if expireIn > 0 {
+			action.TTL = fmt.Sprintf("%ds", int64(expireIn/time.Second))
+		}
+		_, err = security.CreateAction(actionID, action)
+		return
+	},
+}
+
+var (
+	actionType                                          string
+	ipAddressRanges, apiKeys, developers, developerApps []string
+	userAgents, regionCodes, apiProxies                 []string
+	responseCode                                        int
+	headers                                             map[string]string
+	expireIn                                            time.Duration
+)
+
+func init() {
+	CreateActionCmd.Flags().StringVarP(&actionID, "name", "n",
+		"", "Name of the security action")
+	CreateActionCmd.Flags().StringVarP(&filePath, "file", "f",
+		"", "Path to the security action definition in json")
+	CreateActionCmd.Flags().StringVarP(&actionType, "action", "",
+		"", "Action to take on matching traffic; allow, deny or flag")
+	CreateActionCmd.Flags().StringVarP(&description, "description", "d",
+		"", "Description of the security action")
+	CreateActionCmd.Flags().StringArrayVarP(&ipAddressRanges, "ip", "",
+		[]string{}, "IP address or CIDR range, can be repeated")
+	CreateActionCmd.Flags().StringArrayVarP(&apiKeys, "api-key", "",
+		[]string{}, "API key, can be repeated")
+	CreateActionCmd.Flags().StringArrayVarP(&developers, "developer", "",
+		[]string{}, "Developer id, can be repeated")
+	CreateActionCmd.Flags().StringArrayVarP(&developerApps, "developer-app", "",
+		[]string{}, "Developer app id, can be repeated")
+	CreateActionCmd.Flags().StringArrayVarP(&userAgents, "user-agent", "",
+		[]string{}, "User agent, can be repeated")
+	CreateActionCmd.Flags().StringArrayVarP(&regionCodes, "region", "",
+		[]string{}, "Two letter region code, can be repeated")
+	CreateActionCmd.Flags().StringArrayVarP(&apiProxies, "proxy", "",
+		[]string{}, "Limit the action to an API proxy, can be repeated")
+	CreateActionCmd.Flags().IntVarP(&responseCode, "response-code", "",
+		403, "Response code returned by deny actions")
+	CreateActionCmd.Flags().StringToStringVar(&headers, "header",
+		nil, "Header added by flag actions, ex: x-flagged=true")
+	CreateActionCmd.Flags().DurationVarP(&expireIn, "expire-in", "",
+		0, "Remove the action after this duration, ex: 24h")
+
+	_ = CreateActionCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"fmt"
+	"strings"
+
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateProfileCmd to create a security profile
+var CreateProfileCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a security profile",
+	Long: "Create a security profile from a json definition, or with the categories to assess; " +
+		strings.Join(security.ProfileCategories, ", "),
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if filePath == "" && len(categories) == 0 {
+			return fmt.Errorf("either file or category must be set")
+		}
+		if filePath != "" && len(categories) > 0 {
+			return fmt.Errorf("file and category cannot be combined")
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if filePath != "" {
+			_, err = security.CreateProfileFromFile(profileName, filePath)
+			return
+		}
+		_, err = security.CreateProfile(profileName, displayName, description, categories)
+		return
+	},
+}
+
+var (
+	displayName, description string
+	categories               []string
+)
+
+func init() {
+	CreateProfileCmd.Flags().StringVarP(&profileName, "name", "n",
+		"", "Name of the security profile")
+	CreateProfileCmd.Flags().StringVarP(&filePath, "file", "f",
+		"", "Path to the security profile definition in json")
+	CreateProfileCmd.Flags().StringVarP(&displayName, "display-name", "",
+		"", "Display name of the security profile")
+	CreateProfileCmd.Flags().StringVarP(&description, "description", "d",
+		"", "Description of the security profile")
+	CreateProfileCmd.Flags().StringArrayVarP(&categories, "category", "c",
+		[]string{}, "Category to assess, can be repeated")
+
+	_ = CreateProfileCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"fmt"
+
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// CreateReportCmd to submit a security report
+var CreateReportCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Submit an asynchronous security report",
+	Long:  "Submit an asynchronous security report from a json or yaml query definition",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if !utils.FileExists(filePath) {
+			return fmt.Errorf("query file %s was not found", filePath)
+		}
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = security.CreateReport(filePath)
+		return
+	},
+}
+
+func init() {
+	CreateReportCmd.Flags().StringVarP(&filePath, "file", "f",
+		"", "Path to the query definition in json or yaml")
+
+	_ = CreateReportCmd.MarkFlagRequired("file")
+}
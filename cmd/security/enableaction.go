@@ -0,0 +1,63 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// EnableActionCmd to enable a security action
+var EnableActionCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enables a security action",
+	Long:  "Enables a security action",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = security.EnableAction(actionID)
+		return
+	},
+}
+
+// DisableActionCmd to disable a security action
+var DisableActionCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disables a security action",
+	Long:  "Disables a security action",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = security.DisableAction(actionID)
+		return
+	},
+}
+
+func init() {
+	EnableActionCmd.Flags().StringVarP(&actionID, "name", "n",
+		"", "Name of the security action")
+	DisableActionCmd.Flags().StringVarP(&actionID, "name", "n",
+		"", "Name of the security action")
+
+	_ = EnableActionCmd.MarkFlagRequired("name")
+	_ = DisableActionCmd.MarkFlagRequired("name")
+}
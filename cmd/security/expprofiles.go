@@ -0,0 +1,42 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// ExpProfilesCmd to export security profiles
+var ExpProfilesCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export security profiles to a file",
+	Long:  "Export security profiles to a file",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		const exportFileName = "securityprofiles.json"
+
+		respBody, err := security.ExportProfiles()
+		if err != nil {
+			return err
+		}
+		return apiclient.WriteByteArrayToFile(exportFileName, false, respBody)
+	},
+}
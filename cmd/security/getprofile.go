@@ -0,0 +1,52 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// GetProfileCmd to get a security profile
+var GetProfileCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Returns a security profile",
+	Long:  "Returns a security profile, or its revisions",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if revisions {
+			_, err = security.ListProfileRevisions(profileName)
+			return
+		}
+		_, err = security.GetProfile(profileName)
+		return
+	},
+}
+
+var revisions bool
+
+func init() {
+	GetProfileCmd.Flags().StringVarP(&profileName, "name", "n",
+		"", "Name of the security profile")
+	GetProfileCmd.Flags().BoolVarP(&revisions, "revisions", "r",
+		false, "List the revisions of the security profile")
+
+	_ = GetProfileCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// GetReportCmd to get the status of a security report
+var GetReportCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Returns the status of a security report",
+	Long:  "Returns the status of a security report",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = security.GetReport(reportID)
+		return
+	},
+}
+
+func init() {
+	GetReportCmd.Flags().StringVarP(&reportID, "id", "i",
+		"", "Security report id")
+
+	_ = GetReportCmd.MarkFlagRequired("id")
+}
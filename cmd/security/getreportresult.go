@@ -0,0 +1,70 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"os"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// GetReportResultCmd to download the result of a security report
+var GetReportResultCmd = &cobra.Command{
+	Use:   "result",
+	Short: "Download the result of a completed security report",
+	Long:  "Download the result of a completed security report as a zip file, or print the rows with --view",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if view {
+			_, err = security.GetReportResultView(reportID)
+			return
+		}
+
+		data, err := security.GetReportResult(reportID)
+		if err != nil {
+			return err
+		}
+		if outputFile == "" {
+			outputFile = reportID + ".zip"
+		}
+		if err = os.WriteFile(outputFile, data, 0o644); err != nil {
+			return err
+		}
+		clilog.Info.Printf("Security report result written to %s\n", outputFile)
+		return nil
+	},
+}
+
+var view bool
+
+func init() {
+	GetReportResultCmd.Flags().StringVarP(&reportID, "id", "i",
+		"", "Security report id")
+	GetReportResultCmd.Flags().BoolVarP(&view, "view", "",
+		false, "Print the rows of the result instead of downloading the zip file")
+	GetReportResultCmd.Flags().StringVarP(&outputFile, "output", "",
+		"", "Path of the zip file, default is <id>.zip")
+
+	_ = GetReportResultCmd.MarkFlagRequired("id")
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// ImpActionsCmd to import security actions
+var ImpActionsCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a file containing security actions",
+	Long:  "Import a file containing security actions to the environment",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		return security.ImportActions(filePath)
+	},
+}
+
+func init() {
+	ImpActionsCmd.Flags().StringVarP(&filePath, "file", "f",
+		"", "File containing security actions")
+
+	_ = ImpActionsCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// ListActionsCmd to list security actions
+var ListActionsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Returns the security actions in the environment",
+	Long:  "Returns the security actions in the environment",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = security.ListActions(pageSize, pageToken, filter)
+		return
+	},
+}
+
+var filter string
+
+func init() {
+	ListActionsCmd.Flags().IntVarP(&pageSize, "page-size", "",
+		-1, "Number of security actions")
+	ListActionsCmd.Flags().StringVarP(&pageToken, "page-token", "",
+		"", "Token returned by a previous list call")
+	ListActionsCmd.Flags().StringVarP(&filter, "filter", "",
+		"", "Filter the security actions, ex: state=ENABLED")
+}
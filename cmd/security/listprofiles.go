@@ -0,0 +1,44 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// ListProfilesCmd to list security profiles
+var ListProfilesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Returns the security profiles",
+	Long:  "Returns the security profiles in the organization",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = security.ListProfiles(pageSize, pageToken)
+		return
+	},
+}
+
+func init() {
+	ListProfilesCmd.Flags().IntVarP(&pageSize, "page-size", "",
+		-1, "Number of security profiles")
+	ListProfilesCmd.Flags().StringVarP(&pageToken, "page-token", "",
+		"", "Token returned by a previous list call")
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// ListReportsCmd to list security reports
+var ListReportsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Returns the security reports in the environment",
+	Long:  "Returns the security reports in the environment",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		apiclient.SetApigeeEnv(environment)
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = security.ListReports(submittedBy, status, from, to, pageSize, pageToken)
+		return
+	},
+}
+
+var submittedBy, status, from, to string
+
+func init() {
+	ListReportsCmd.Flags().StringVarP(&submittedBy, "submitted-by", "",
+		"", "Filter by the email of the user who submitted the report")
+	ListReportsCmd.Flags().StringVarP(&status, "status", "",
+		"", "Filter by status; enqueued, running, completed, expired or failed")
+	ListReportsCmd.Flags().StringVarP(&from, "from", "",
+		"", "Filter reports created after this time, RFC3339")
+	ListReportsCmd.Flags().StringVarP(&to, "to", "",
+		"", "Filter reports created before this time, RFC3339")
+	ListReportsCmd.Flags().IntVarP(&pageSize, "page-size", "",
+		-1, "Number of security reports")
+	ListReportsCmd.Flags().StringVarP(&pageToken, "page-token", "",
+		"", "Token returned by a previous list call")
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ProfilesCmd to manage security profiles
+var ProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Manage security profiles",
+	Long:  "Manage security profiles and the environments they are attached to",
+}
+
+var profileName string
+
+func init() {
+	ProfilesCmd.AddCommand(CreateProfileCmd)
+	ProfilesCmd.AddCommand(GetProfileCmd)
+	ProfilesCmd.AddCommand(ListProfilesCmd)
+	ProfilesCmd.AddCommand(UpdateProfileCmd)
+	ProfilesCmd.AddCommand(DelProfileCmd)
+	ProfilesCmd.AddCommand(AttachProfileCmd)
+	ProfilesCmd.AddCommand(DetachProfileCmd)
+	ProfilesCmd.AddCommand(ExpProfilesCmd)
+	ProfilesCmd.AddCommand(ImpProfilesCmd)
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ReportsCmd to manage security reports
+var ReportsCmd = &cobra.Command{
+	Use:   "reports",
+	Short: "Manage security reports for the environment",
+	Long:  "Create asynchronous security reports and download the results",
+}
+
+var reportID string
+
+func init() {
+	ReportsCmd.PersistentFlags().StringVarP(&environment, "env", "e",
+		"", "Apigee environment name")
+
+	_ = ReportsCmd.MarkPersistentFlagRequired("env")
+
+	ReportsCmd.AddCommand(CreateReportCmd)
+	ReportsCmd.AddCommand(GetReportCmd)
+	ReportsCmd.AddCommand(ListReportsCmd)
+	ReportsCmd.AddCommand(GetReportResultCmd)
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"time"
+
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/apigee/apigeecli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// ScoresCmd to compute the security scores of an environment
+var ScoresCmd = &cobra.Command{
+	Use:   "scores",
+	Short: "Returns the security scores of an environment",
+	Long: "Returns the security scores of an environment assessed with a security profile, " +
+		"with a breakdown by category and the recommendations to improve the score",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if err = utils.ValidateReportFormat(format); err != nil {
+			return err
+		}
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		end := time.Now()
+		start := end.Add(-since)
+
+		apiclient.ClientPrintHttpResponse.Set(false)
+		respBody, err := security.ComputeScores(profileName, environment, start, end, scorePaths)
+		apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+		if err != nil {
+			return err
+		}
+
+		scores, err := security.ParseScores(respBody)
+		if err != nil {
+			return err
+		}
+		header, rows := security.ScoreBreakdown(scores)
+		if scores == nil {
+			scores = []security.EnvironmentScore{}
+		}
+		return utils.WriteReport(format, outputFile, header, rows, scores)
+	},
+}
+
+var (
+	since              time.Duration
+	scorePaths         []string
+	format, outputFile string
+)
+
+func init() {
+	ScoresCmd.Flags().StringVarP(&profileName, "profile", "p",
+		"google-default", "Name of the security profile")
+	ScoresCmd.Flags().StringVarP(&environment, "env", "e",
+		"", "Apigee environment name")
+	ScoresCmd.Flags().DurationVarP(&since, "since", "",
+		24*time.Hour, "Compute the scores for this period until now, ex: 168h")
+	ScoresCmd.Flags().StringArrayVarP(&scorePaths, "path", "",
+		[]string{}, "Return the scores of this component, ex: /org@myorg/envs@test/source@abuse")
+	ScoresCmd.Flags().StringVarP(&format, "format", "f",
+		"table", "Output format; table, csv or json")
+	ScoresCmd.Flags().StringVarP(&outputFile, "output", "",
+		"", "Write the scores to a file instead of stdout")
+
+	_ = ScoresCmd.MarkFlagRequired("env")
+}
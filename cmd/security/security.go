@@ -0,0 +1,42 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd to manage Advanced API Security
+var Cmd = &cobra.Command{
+	Use:   "security",
+	Short: "Manage Advanced API Security",
+	Long:  "Manage Advanced API Security profiles, scores, reports and actions",
+}
+
+var (
+	org, environment, filePath string
+	pageSize                   int
+	pageToken                  string
+)
+
+func init() {
+	Cmd.PersistentFlags().StringVarP(&org, "org", "o",
+		"", "Apigee organization name")
+
+	Cmd.AddCommand(ProfilesCmd)
+	Cmd.AddCommand(ScoresCmd)
+	Cmd.AddCommand(ReportsCmd)
+	Cmd.AddCommand(ActionsCmd)
+}
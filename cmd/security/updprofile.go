@@ -0,0 +1,47 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"internal/apiclient"
+
+	"internal/client/security"
+
+	"github.com/spf13/cobra"
+)
+
+// UpdateProfileCmd to update a security profile
+var UpdateProfileCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update a security profile",
+	Long:  "Update the display name, description and categories of a security profile from a json definition",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		return apiclient.SetApigeeOrg(org)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		_, err = security.UpdateProfile(profileName, filePath)
+		return
+	},
+}
+
+func init() {
+	UpdateProfileCmd.Flags().StringVarP(&profileName, "name", "n",
+		"", "Name of the security profile")
+	UpdateProfileCmd.Flags().StringVarP(&filePath, "file", "f",
+		"", "Path to the security profile definition in json")
+
+	_ = UpdateProfileCmd.MarkFlagRequired("name")
+	_ = UpdateProfileCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// ReportFormats are the output formats supported by report commands
+var ReportFormats = []string{"table", "csv", "json"}
+
+// ValidateReportFormat checks if the format is one of ReportFormats
+func ValidateReportFormat(format string) error {
+	for _, f := range ReportFormats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid format %s, must be one of %s", format, strings.Join(ReportFormats, ", "))
+}
+
+// WriteReport writes a report as a table, csv or json. The header and rows
+// are used for table and csv output, the payload is marshalled for json output.
+// The report is written to stdout when filePath is empty
+func WriteReport(format string, filePath string, header []string, rows [][]string, payload interface{}) (err error) {
+	var out io.Writer = os.Stdout
+
+	if filePath != "" {
+		var f *os.File
+		if f, err = os.Create(filePath); err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch format {
+	case "csv":
+		w := csv.NewWriter(out)
+		if err = w.Write(header); err != nil {
+			return err
+		}
+		if err = w.WriteAll(rows); err != nil {
+			return err
+		}
+	case "json":
+		var body []byte
+		if body, err = json.MarshalIndent(payload, "", "  "); err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintln(out, string(body)); err != nil {
+			return err
+		}
+	default:
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, strings.ToUpper(strings.Join(header, "\t")))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+	return nil
+}
+
+// ParseDays converts a duration in days (30d) or a go duration (72h) to days
+func ParseDays(duration string) (days int, err error) {
+	if strings.HasSuffix(duration, "d") {
+		if days, err = strconv.Atoi(strings.TrimSuffix(duration, "d")); err != nil {
+			return -1, fmt.Errorf("invalid duration %s: %v", duration, err)
+		}
+		return days, nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return -1, fmt.Errorf("invalid duration %s: %v", duration, err)
+	}
+	return int(d.Hours() / 24), nil
+}
@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path"
@@ -231,6 +232,35 @@ func FetchBundle(entityType string, folder string, name string, revision string,
 	return nil
 }
 
+// DownloadBundle downloads a revision of a shared flow or proxy bundle to outputFile.
+// The file is only created once the download has started
+func DownloadBundle(entityType string, name string, revision string, outputFile string) (err error) {
+	u, _ := url.Parse(BaseURL)
+	q := u.Query()
+	q.Set("format", "bundle")
+	u.RawQuery = q.Encode()
+	u.Path = path.Join(u.Path, GetApigeeOrg(), entityType, name, "revisions", revision)
+
+	resp, err := DownloadFile(u.String(), true)
+	if err != nil {
+		return fmt.Errorf("error downloading %s revision %s: %v", name, revision, err)
+	}
+	if resp == nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	out, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
 // ImportBundleAsync imports a sharedflow or api proxy bundle meantot be called asynchronously
 func ImportBundleAsync(entityType string, name string, bundlePath string, wg *sync.WaitGroup) {
 	defer wg.Done()
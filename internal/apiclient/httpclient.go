@@ -34,6 +34,9 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// ErrNotFound is returned when the server cannot find the requested resource
+var ErrNotFound = errors.New(getErrorMessage(http.StatusNotFound))
+
 // RateLimitedHttpClient
 type RateLimitedHTTPClient struct {
 	client      *http.Client
@@ -435,6 +438,9 @@ func handleResponse(resp *http.Response) (respBody []byte, err error) {
 	} else if resp.StatusCode > 399 {
 		clilog.Debug.Printf("status code %d, error in response: %s\n", resp.StatusCode, string(respBody))
 		clilog.HttpError.Println(string(respBody))
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
 		return nil, errors.New(getErrorMessage(resp.StatusCode))
 	}
 
@@ -31,6 +31,7 @@ import (
 const (
 	CrmURL     = "https://cloudresourcemanager.googleapis.com/v1/projects/"
 	crmBetaURL = "https://cloudresourcemanager.googleapis.com/v1beta1/projects/"
+	iamURL     = "https://iam.googleapis.com/v1/projects/"
 )
 
 // binding for IAM Roles
@@ -59,18 +60,10 @@ type condition struct {
 	Expression  string `json:"expression,omitempty"`
 }
 
-// CreateIAMServiceAccount create a new IAM SA with the necessary roles for Apigee
-func CreateIAMServiceAccount(name string, iamRole string) (err error) {
-	type KeyResponse struct {
-		Name            string `json:"name,omitempty"`
-		PrivateKeyType  string `json:"privateKeyType,omitempty"`
-		PrivateKeyData  string `json:"privateKeyData,omitempty"`
-		ValidBeforeTime string `json:"validBeforeTime,omitempty"`
-		ValidAfterTime  string `json:"validAfterTime,omitempty"`
-		KeyAlgorithm    string `json:"keyAlgorithm,omitempty"`
-	}
-
-	const iamURL = "https://iam.googleapis.com/v1/projects/"
+// CreateIAMServiceAccount create a new IAM SA with the necessary roles for Apigee.
+// When generateKey is set, a new key is created and written to keyFile. If keyFile
+// is empty, the key is written to <project>-<name>.json in the current folder
+func CreateIAMServiceAccount(name string, iamRole string, generateKey bool, keyFile string) (err error) {
 	var role string
 
 	serviceAccountName := name + "@" + GetProjectID() + ".iam.gserviceaccount.com"
@@ -132,65 +125,42 @@ func CreateIAMServiceAccount(name string, iamRole string) (err error) {
 		return err
 	}
 
-	// Step 2: create a new service account key
-	u, _ = url.Parse(iamURL)
-	u.Path = path.Join(u.Path, GetProjectID(), "serviceAccounts",
-		serviceAccountName, "keys")
-
-	respKeyBody, err := HttpClient(u.String(), "")
-	if err != nil {
-		clilog.Error.Println(err)
-		return err
-	}
-
-	// Step 3: read the response
-	keyResponse := KeyResponse{}
-	err = json.Unmarshal(respKeyBody, &keyResponse)
-	if err != nil {
-		return err
+	// mart doesn't need any roles
+	if iamRole != "mart" {
+		if err = bindProjectRoles(serviceAccountName, iamRole, role); err != nil {
+			clilog.Error.Println(err)
+			return err
+		}
 	}
 
-	// Step 4: base64 decode the response to get the private key.json
-	privateKey, err := base64.StdEncoding.DecodeString(keyResponse.PrivateKeyData)
-	if err != nil {
-		clilog.Error.Println(err)
-		return err
+	if !generateKey {
+		return nil
 	}
 
-	// Step 5: Write the data to a file
-	file, err := os.Create(GetProjectID() + "-" + name + ".json")
-	if err != nil {
-		clilog.Error.Println("cannot open private key file: ", err)
-		return err
+	if keyFile == "" {
+		keyFile = GetProjectID() + "-" + name + ".json"
 	}
 
-	defer file.Close()
+	return createServiceAccountKey(serviceAccountName, keyFile)
+}
 
-	_, err = file.Write([]byte(privateKey))
+// bindProjectRoles adds the role (or all the runtime roles) for the service account
+// to the project IAM policy
+func bindProjectRoles(serviceAccountName string, iamRole string, role string) (err error) {
+	// Step 2: get the current IAM policies for the project
+	respBody, err := GetProjectIAMPolicy()
 	if err != nil {
-		clilog.Error.Println("error writing to file: ", err)
-		return err
-	}
-
-	// mart doesn't need any roles, return here.
-	if iamRole == "mart" {
 		return err
 	}
 
-	// Step 6: get the current IAM policies for the project
-	u, _ = url.Parse(CrmURL)
-	u.Path = path.Join(u.Path, GetProjectID()+":getIamPolicy")
-	respBody, err := HttpClient(u.String(), "")
-
 	iamPolicy := iamPolicy{}
 
 	err = json.Unmarshal(respBody, &iamPolicy)
 	if err != nil {
-		clilog.Error.Println(err)
 		return err
 	}
 
-	// Step 7: create a new policy binding for apigee
+	// Step 3: create a new policy binding for apigee
 	if iamRole == "all" {
 		bindings := createAllRoleBindings(serviceAccountName)
 		iamPolicy.Bindings = append(iamPolicy.Bindings, bindings...)
@@ -205,9 +175,12 @@ func CreateIAMServiceAccount(name string, iamRole string) (err error) {
 	setIamPolicy := setIamPolicy{}
 	setIamPolicy.Policy = iamPolicy
 	setIamPolicyBody, err := json.Marshal(setIamPolicy)
+	if err != nil {
+		return err
+	}
 
-	// Step 8: set the iam policy
-	u, _ = url.Parse(crmBetaURL)
+	// Step 4: set the iam policy
+	u, _ := url.Parse(crmBetaURL)
 	u.Path = path.Join(u.Path, GetProjectID()+":setIamPolicy")
 
 	_, err = HttpClient(u.String(), string(setIamPolicyBody))
@@ -215,16 +188,91 @@ func CreateIAMServiceAccount(name string, iamRole string) (err error) {
 	return err
 }
 
-func createAllRoleBindings(name string) []roleBinding {
-	roles := [...]string{
-		"roles/apigee.synchronizerManager", "roles/apigee.analyticsAgent",
-		"roles/monitoring.metricWriter", "roles/logging.logWriter", "roles/storage.objectAdmin",
-		"roles/apigeeconnect.Agent", "roles/apigee.runtimeAgent",
+// createServiceAccountKey creates a new key for the service account and
+// writes it to keyFile, readable only by the owner
+func createServiceAccountKey(serviceAccountName string, keyFile string) (err error) {
+	type KeyResponse struct {
+		Name            string `json:"name,omitempty"`
+		PrivateKeyType  string `json:"privateKeyType,omitempty"`
+		PrivateKeyData  string `json:"privateKeyData,omitempty"`
+		ValidBeforeTime string `json:"validBeforeTime,omitempty"`
+		ValidAfterTime  string `json:"validAfterTime,omitempty"`
+		KeyAlgorithm    string `json:"keyAlgorithm,omitempty"`
 	}
 
+	// Step 1: create a new service account key
+	u, _ := url.Parse(iamURL)
+	u.Path = path.Join(u.Path, GetProjectID(), "serviceAccounts",
+		serviceAccountName, "keys")
+
+	respKeyBody, err := HttpClient(u.String(), "")
+	if err != nil {
+		clilog.Error.Println(err)
+		return err
+	}
+
+	// Step 2: read the response
+	keyResponse := KeyResponse{}
+	err = json.Unmarshal(respKeyBody, &keyResponse)
+	if err != nil {
+		return err
+	}
+
+	// Step 3: base64 decode the response to get the private key.json
+	privateKey, err := base64.StdEncoding.DecodeString(keyResponse.PrivateKeyData)
+	if err != nil {
+		clilog.Error.Println(err)
+		return err
+	}
+
+	// Step 4: Write the data to a file
+	file, err := os.OpenFile(keyFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		clilog.Error.Println("cannot open private key file: ", err)
+		return err
+	}
+
+	defer file.Close()
+
+	// an existing file keeps its permissions, so tighten them
+	if err = file.Chmod(0o600); err != nil {
+		clilog.Error.Println("cannot set permissions on private key file: ", err)
+		return err
+	}
+
+	_, err = file.Write(privateKey)
+	if err != nil {
+		clilog.Error.Println("error writing to file: ", err)
+		return err
+	}
+
+	clilog.Info.Printf("Service account key written to %s\n", keyFile)
+	return nil
+}
+
+// runtimeRoles are the roles needed by the Apigee hybrid runtime components
+var runtimeRoles = [...]string{
+	"roles/apigee.synchronizerManager", "roles/apigee.analyticsAgent",
+	"roles/monitoring.metricWriter", "roles/logging.logWriter", "roles/storage.objectAdmin",
+	"roles/apigeeconnect.Agent", "roles/apigee.runtimeAgent",
+}
+
+// GetRuntimeRoles returns the roles needed by the Apigee hybrid runtime components
+func GetRuntimeRoles() []string {
+	return runtimeRoles[:]
+}
+
+// GetProjectIAMPolicy gets the IAM policy for the GCP project
+func GetProjectIAMPolicy() (respBody []byte, err error) {
+	u, _ := url.Parse(CrmURL)
+	u.Path = path.Join(u.Path, GetProjectID()+":getIamPolicy")
+	return HttpClient(u.String(), "")
+}
+
+func createAllRoleBindings(name string) []roleBinding {
 	bindings := []roleBinding{}
 
-	for _, role := range roles {
+	for _, role := range runtimeRoles {
 		binding := roleBinding{}
 		binding.Role = role
 		binding.Members = append(binding.Members, "serviceAccount:"+name)
@@ -455,3 +503,83 @@ func AddWid(projectID string, namespace string, kServiceAccount string, gService
 
 	return err
 }
+
+// AddWidMembers allows each of the Kubernetes service accounts in the namespace
+// to impersonate the Google service account through workload identity
+func AddWidMembers(projectID string, namespace string, kServiceAccounts []string, gServiceAccount string) (err error) {
+	const role = "roles/iam.workloadIdentityUser"
+	var respBody, setIamPolicyBody []byte
+
+	if !strings.Contains(gServiceAccount, "@") {
+		gServiceAccount = gServiceAccount + "@" + GetProjectID() + ".iam.gserviceaccount.com"
+	}
+
+	ClientPrintHttpResponse.Set(false)
+	defer ClientPrintHttpResponse.Set(GetCmdPrintHttpResponseSetting())
+
+	// the role is granted on the Google service account, preserve existing bindings
+	u, _ := url.Parse(iamURL)
+	u.Path = path.Join(u.Path, GetProjectID(), "serviceAccounts", gServiceAccount+":getIamPolicy")
+	if respBody, err = HttpClient(u.String(), ""); err != nil {
+		return err
+	}
+
+	iamPolicy := iamPolicy{}
+	if err = json.Unmarshal(respBody, &iamPolicy); err != nil {
+		return err
+	}
+
+	members := []string{}
+	for _, kServiceAccount := range kServiceAccounts {
+		members = append(members, "serviceAccount:"+projectID+".svc.id.goog["+namespace+"/"+kServiceAccount+"]")
+	}
+	if added := addBindingMembers(&iamPolicy, role, members); !added {
+		clilog.Info.Printf("Workload identity is already set for %s\n", gServiceAccount)
+		return nil
+	}
+
+	setIamPolicy := setIamPolicy{}
+	setIamPolicy.Policy = iamPolicy
+	if setIamPolicyBody, err = json.Marshal(setIamPolicy); err != nil {
+		return err
+	}
+
+	u, _ = url.Parse(iamURL)
+	u.Path = path.Join(u.Path, GetProjectID(), "serviceAccounts", gServiceAccount+":setIamPolicy")
+	_, err = HttpClient(u.String(), string(setIamPolicyBody))
+
+	return err
+}
+
+// addBindingMembers adds the members to the unconditional binding of the role,
+// which is created when missing. Members already in the binding are skipped.
+// It returns whether the policy changed
+func addBindingMembers(policy *iamPolicy, role string, members []string) (added bool) {
+	bindingIndex := -1
+	for i, binding := range policy.Bindings {
+		if binding.Role == role && binding.Condition == nil {
+			bindingIndex = i
+			break
+		}
+	}
+	if bindingIndex == -1 {
+		policy.Bindings = append(policy.Bindings, roleBinding{Role: role})
+		bindingIndex = len(policy.Bindings) - 1
+	}
+
+	binding := &policy.Bindings[bindingIndex]
+	for _, member := range members {
+		found := false
+		for _, m := range binding.Members {
+			if m == member {
+				found = true
+				break
+			}
+		}
+		if !found {
+			binding.Members = append(binding.Members, member)
+			added = true
+		}
+	}
+	return added
+}
@@ -0,0 +1,265 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// PageStyle is the way a collection is paged by the API
+type PageStyle uint8
+
+const (
+	// StartKeyPage pages with count and startKey, the last item of a page
+	// is returned again as the first item of the next page
+	StartKeyPage PageStyle = iota
+	// RowPage pages with rows and startKey, like StartKeyPage
+	RowPage
+	// TokenPage pages with pageSize and pageToken, the next token is returned in the response
+	TokenPage
+)
+
+// Pager iterates over all the pages of a list API
+type Pager struct {
+	// URL of the collection, including any fixed query parameters
+	URL string
+	// Style of paging supported by the collection
+	Style PageStyle
+	// PageSize is the number of items requested per page
+	PageSize int
+	// ItemsField is the name of the json array holding the items, ex: developer.
+	// When empty the response is expected to be a json array
+	ItemsField string
+	// KeyField is the name of the item field passed as startKey, ex: email.
+	// When empty the item is expected to be a string
+	KeyField string
+	// SizeParam and TokenParam override the default query parameter names
+	SizeParam, TokenParam string
+	// TokenField overrides the name of the field holding the next page token
+	TokenField string
+
+	nextKey string
+	done    bool
+	pages   int
+	total   int
+}
+
+// NewPager returns a Pager for the collection at rawURL
+func NewPager(rawURL string, style PageStyle, pageSize int, itemsField string, keyField string) *Pager {
+	return &Pager{
+		URL:        rawURL,
+		Style:      style,
+		PageSize:   pageSize,
+		ItemsField: itemsField,
+		KeyField:   keyField,
+	}
+}
+
+// Next returns the items in the next page. ok is false when there are no more pages.
+// Paging stops when the next key or token does not move forward, so a server that
+// ignores it cannot loop forever, and for the startKey styles when a page is shorter
+// than PageSize
+func (p *Pager) Next() (items []json.RawMessage, ok bool, err error) {
+	var respBody []byte
+
+	if p.done {
+		return nil, false, nil
+	}
+
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, false, err
+	}
+	q := u.Query()
+	if p.PageSize > 0 {
+		q.Set(p.sizeParam(), strconv.Itoa(p.PageSize))
+	}
+	if p.nextKey != "" {
+		q.Set(p.tokenParam(), p.nextKey)
+	}
+	u.RawQuery = q.Encode()
+
+	if respBody, err = HttpClient(u.String()); err != nil {
+		return nil, false, err
+	}
+	p.pages++
+
+	previousKey := p.nextKey
+	if items, err = p.parse(respBody); err != nil {
+		return nil, false, err
+	}
+
+	if p.Style == TokenPage {
+		if p.nextKey != "" && p.nextKey == previousKey {
+			// the pageToken was ignored and the same page returned again
+			p.done = true
+			return nil, false, nil
+		}
+		if p.nextKey == "" {
+			p.done = true
+		}
+	} else {
+		lastPage := p.PageSize > 0 && len(items) < p.PageSize
+		// the startKey item is returned again, skip it
+		if p.nextKey != "" && len(items) > 0 {
+			if key, _ := p.key(items[0]); key == p.nextKey {
+				items = items[1:]
+			}
+		}
+		if len(items) == 0 {
+			p.done = true
+			return nil, false, nil
+		}
+		if p.nextKey, err = p.key(items[len(items)-1]); err != nil {
+			return nil, false, err
+		}
+		if p.nextKey == previousKey {
+			// the startKey was ignored and the same page returned again
+			p.done = true
+			return nil, false, nil
+		}
+		if lastPage || p.nextKey == "" {
+			p.done = true
+		}
+	}
+
+	p.total += len(items)
+	return items, true, nil
+}
+
+// All returns the items in every page
+func (p *Pager) All() (items []json.RawMessage, err error) {
+	for {
+		page, ok, err := p.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return items, nil
+		}
+		items = append(items, page...)
+	}
+}
+
+// Total is the number of items fetched so far
+func (p *Pager) Total() int {
+	return p.total
+}
+
+// Pages is the number of pages fetched so far
+func (p *Pager) Pages() int {
+	return p.pages
+}
+
+// ListAll fetches every page of a collection and returns the items wrapped in
+// itemsField, the same shape as a single page response. The total number of items
+// is returned so callers can report it
+func ListAll(p *Pager) (respBody []byte, total int, err error) {
+	var items []json.RawMessage
+
+	if items, err = p.All(); err != nil {
+		return nil, 0, err
+	}
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+
+	if p.ItemsField == "" {
+		respBody, err = json.Marshal(items)
+	} else {
+		respBody, err = json.Marshal(map[string][]json.RawMessage{p.ItemsField: items})
+	}
+	return respBody, p.Total(), err
+}
+
+func (p *Pager) parse(respBody []byte) (items []json.RawMessage, err error) {
+	if p.ItemsField == "" {
+		if err = json.Unmarshal(respBody, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+
+	page := map[string]json.RawMessage{}
+	if err = json.Unmarshal(respBody, &page); err != nil {
+		return nil, err
+	}
+	if raw, ok := page[p.ItemsField]; ok {
+		if err = json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.Style == TokenPage {
+		p.nextKey = ""
+		if raw, ok := page[p.tokenField()]; ok {
+			if err = json.Unmarshal(raw, &p.nextKey); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return items, nil
+}
+
+func (p *Pager) key(item json.RawMessage) (key string, err error) {
+	if p.KeyField == "" {
+		err = json.Unmarshal(item, &key)
+		return key, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(item, &fields); err != nil {
+		return "", err
+	}
+	raw, ok := fields[p.KeyField]
+	if !ok {
+		return "", fmt.Errorf("field %s not found in the list response", p.KeyField)
+	}
+	err = json.Unmarshal(raw, &key)
+	return key, err
+}
+
+func (p *Pager) sizeParam() string {
+	if p.SizeParam != "" {
+		return p.SizeParam
+	}
+	switch p.Style {
+	case RowPage:
+		return "rows"
+	case TokenPage:
+		return "pageSize"
+	default:
+		return "count"
+	}
+}
+
+func (p *Pager) tokenParam() string {
+	if p.TokenParam != "" {
+		return p.TokenParam
+	}
+	if p.Style == TokenPage {
+		return "pageToken"
+	}
+	return "startKey"
+}
+
+func (p *Pager) tokenField() string {
+	if p.TokenField != "" {
+		return p.TokenField
+	}
+	return "nextPageToken"
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// pageServer serves items in pages like the Apigee list APIs. With ignoreKey
+// the start key or page token is ignored and the first page is always returned
+func pageServer(style PageStyle, items []string, ignoreKey bool, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		p := &Pager{Style: style}
+		size, _ := strconv.Atoi(r.URL.Query().Get(p.sizeParam()))
+		key := r.URL.Query().Get(p.tokenParam())
+
+		start := 0
+		if key != "" && !ignoreKey {
+			for i, item := range items {
+				if item == key {
+					start = i
+					if style == TokenPage {
+						start++
+					}
+				}
+			}
+		}
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+
+		page := map[string]interface{}{"items": items[start:end]}
+		if style == TokenPage && end < len(items) {
+			page["nextPageToken"] = items[end-1]
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func TestPagerNext(t *testing.T) {
+	tests := []struct {
+		name      string
+		style     PageStyle
+		items     []string
+		pageSize  int
+		ignoreKey bool
+		want      int
+		requests  int
+	}{
+		{
+			name:     "short last page",
+			style:    StartKeyPage,
+			items:    []string{"a", "b", "c", "d"},
+			pageSize: 3,
+			want:     4,
+			requests: 2,
+		},
+		{
+			name:     "single page",
+			style:    RowPage,
+			items:    []string{"a", "b"},
+			pageSize: 3,
+			want:     2,
+			requests: 1,
+		},
+		{
+			name:     "last page repeats the start key only",
+			style:    StartKeyPage,
+			items:    []string{"a", "b", "c", "d", "e"},
+			pageSize: 5,
+			want:     5,
+			requests: 2,
+		},
+		{
+			name:      "start key ignored",
+			style:     StartKeyPage,
+			items:     []string{"a", "b", "c", "d", "e"},
+			pageSize:  2,
+			ignoreKey: true,
+			want:      2,
+			requests:  2,
+		},
+		{
+			name:     "page token",
+			style:    TokenPage,
+			items:    []string{"a", "b", "c", "d", "e"},
+			pageSize: 2,
+			want:     5,
+			requests: 3,
+		},
+		{
+			name:      "page token ignored",
+			style:     TokenPage,
+			items:     []string{"a", "b", "c", "d", "e"},
+			pageSize:  2,
+			ignoreKey: true,
+			want:      2,
+			requests:  2,
+		},
+		{
+			name:     "empty collection",
+			style:    StartKeyPage,
+			pageSize: 3,
+			want:     0,
+			requests: 1,
+		},
+	}
+
+	NewApigeeClient(ApigeeClientOptions{Token: "token", Org: "org", SkipCache: true})
+	ClientPrintHttpResponse.Set(false)
+	defer ClientPrintHttpResponse.Set(GetCmdPrintHttpResponseSetting())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := 0
+			items := test.items
+			if items == nil {
+				items = []string{}
+			}
+			srv := pageServer(test.style, items, test.ignoreKey, &requests)
+			defer srv.Close()
+
+			p := NewPager(srv.URL, test.style, test.pageSize, "items", "")
+			all, err := p.All()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != test.want || p.Total() != test.want {
+				t.Errorf("got %d items, total %d, want %d", len(all), p.Total(), test.want)
+			}
+			if requests != test.requests || p.Pages() != test.requests {
+				t.Errorf("got %d requests, pages %d, want %d", requests, p.Pages(), test.requests)
+			}
+		})
+	}
+}
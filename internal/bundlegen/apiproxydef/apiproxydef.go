@@ -49,7 +49,8 @@ type resourcesDef struct {
 	Resource []string `xml:"Resource,omitempty"`
 }
 
-type apiProxyDef struct {
+// APIProxyDef is the APIProxy descriptor of a bundle
+type APIProxyDef struct {
 	XMLName              xml.Name                `xml:"APIProxy"`
 	Name                 string                  `xml:"name,attr"`
 	Revision             string                  `xml:"revision,attr"`
@@ -69,34 +70,32 @@ type apiProxyDef struct {
 	Validate             string                  `xml:"validate,omitempty"`
 }
 
-var apiProxy apiProxyDef
-
-func SetDisplayName(name string) {
+func (apiProxy *APIProxyDef) SetDisplayName(name string) {
 	apiProxy.DisplayName = name
 	apiProxy.Name = name
 }
 
-func AddProxyEndpoint(name string) {
+func (apiProxy *APIProxyDef) AddProxyEndpoint(name string) {
 	apiProxy.ProxyEndpoints.ProxyEndpoint = append(apiProxy.ProxyEndpoints.ProxyEndpoint, name)
 }
 
-func AddTargetEndpoint(name string) {
+func (apiProxy *APIProxyDef) AddTargetEndpoint(name string) {
 	apiProxy.TargetEndpoints.TargetEndpoint = append(apiProxy.TargetEndpoints.TargetEndpoint, name)
 }
 
-func AddIntegrationEndpoint(name string) {
+func (apiProxy *APIProxyDef) AddIntegrationEndpoint(name string) {
 	apiProxy.IntegrationEndpoints.IntegrationEndpoint = append(apiProxy.IntegrationEndpoints.IntegrationEndpoint, name)
 }
 
-func SetCreatedAt() {
+func (apiProxy *APIProxyDef) SetCreatedAt() {
 	apiProxy.CreatedAt = strconv.FormatInt((time.Now().UTC().UnixNano())/1000000, 10)
 }
 
-func SetLastModifiedAt() {
+func (apiProxy *APIProxyDef) SetLastModifiedAt() {
 	apiProxy.LastModifiedAt = strconv.FormatInt((time.Now().UTC().UnixNano())/1000000, 10)
 }
 
-func AddPolicy(name string) {
+func (apiProxy *APIProxyDef) AddPolicy(name string) {
 	for index := range apiProxy.Policies.Policy {
 		if apiProxy.Policies.Policy[index] == name {
 			return
@@ -105,19 +104,19 @@ func AddPolicy(name string) {
 	apiProxy.Policies.Policy = append(apiProxy.Policies.Policy, name)
 }
 
-func SetBasePath(basePath string) {
+func (apiProxy *APIProxyDef) SetBasePath(basePath string) {
 	apiProxy.BasePaths = basePath
 }
 
-func SetRevision(revision string) {
+func (apiProxy *APIProxyDef) SetRevision(revision string) {
 	apiProxy.Revision = revision
 }
 
-func SetDescription(description string) {
+func (apiProxy *APIProxyDef) SetDescription(description string) {
 	apiProxy.Description = description
 }
 
-func GetAPIProxy() (string, error) {
+func (apiProxy *APIProxyDef) GetAPIProxy() (string, error) {
 	proxyBody, err := xml.MarshalIndent(apiProxy, "", " ")
 	if err != nil {
 		return "", err
@@ -125,11 +124,11 @@ func GetAPIProxy() (string, error) {
 	return string(proxyBody), nil
 }
 
-func SetConfigurationVersion() {
+func (apiProxy *APIProxyDef) SetConfigurationVersion() {
 	apiProxy.ConfigurationVersion.MajorVersion = "4"
 	apiProxy.ConfigurationVersion.MinorVersion = "0"
 }
 
-func AddResource(name string, resType string) {
+func (apiProxy *APIProxyDef) AddResource(name string, resType string) {
 	apiProxy.Resources.Resource = append(apiProxy.Resources.Resource, resType+"://"+name)
 }
@@ -41,7 +41,8 @@ type ResponseFlowDef struct {
 }
 
 type StepDef struct {
-	Name string `xml:"Name"`
+	Condition string `xml:"Condition,omitempty"`
+	Name      string `xml:"Name"`
 }
 
 type FlowsDef struct {
@@ -61,3 +62,8 @@ type FlowDef struct {
 type ConditionDef struct {
 	ConditionData string `xml:",innerxml"`
 }
+
+// FaultRulesDef keeps the fault rules of an endpoint as written
+type FaultRulesDef struct {
+	FaultRulesData string `xml:",innerxml"`
+}
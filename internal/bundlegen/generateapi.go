@@ -21,14 +21,13 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"internal/clilog"
 
-	apiproxy "internal/bundlegen/apiproxydef"
 	"internal/bundlegen/policies"
 	"internal/bundlegen/proxies"
-	targets "internal/bundlegen/targets"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/ghodss/yaml"
@@ -44,6 +43,7 @@ type pathDetailDef struct {
 	SecurityScheme securitySchemesDef
 	SpikeArrest    spikeArrestDef
 	Quota          quotaDef
+	Extensions     apigeeExtensionsDef
 }
 
 type spikeArrestDef struct {
@@ -99,25 +99,17 @@ type jwtPolicyDef struct {
 	Location         map[string]string // only one location supported for now
 }
 
-var generateSetTarget bool
-
-var securitySchemesList = securitySchemesListDef{}
-
-var (
-	quotaPolicyContent       = map[string]string{}
-	spikeArrestPolicyContent = map[string]string{}
-)
-
-var doc *openapi3.T
-
-func LoadDocumentFromFile(filePath string, validate bool, formatValidation bool) (string, []byte, error) {
+func (g *Generator) LoadDocumentFromFile(filePath string, validate bool, formatValidation bool) (string, []byte, error) {
 	var err error
 	var jsonContent []byte
 
+	loaderMu.Lock()
+	defer loaderMu.Unlock()
+
 	// see ./test/circular-reference.json and https://github.com/apigee/apigeecli/issues/199
 	openapi3.CircularReferenceCounter = 20
 
-	doc, err = openapi3.NewLoader().LoadFromFile(filePath)
+	g.doc, err = openapi3.NewLoader().LoadFromFile(filePath)
 	if err != nil {
 		clilog.Error.Println(err)
 		return "", nil, err
@@ -133,13 +125,13 @@ func LoadDocumentFromFile(filePath string, validate bool, formatValidation bool)
 	}
 
 	if validate {
-		if err = doc.Validate(openapi3.NewLoader().Context); err != nil {
+		if err = g.doc.Validate(openapi3.NewLoader().Context); err != nil {
 			clilog.Error.Println(err)
 			return "", nil, err
 		}
 	}
 
-	if jsonContent, err = doc.MarshalJSON(); err != nil {
+	if jsonContent, err = g.doc.MarshalJSON(); err != nil {
 		clilog.Error.Println(err)
 		return "", nil, err
 	}
@@ -152,10 +144,13 @@ func LoadDocumentFromFile(filePath string, validate bool, formatValidation bool)
 	}
 }
 
-func LoadDocumentFromURI(uri string, validate bool, formatValidation bool) (string, []byte, error) {
+func (g *Generator) LoadDocumentFromURI(uri string, validate bool, formatValidation bool) (string, []byte, error) {
 	var err error
 	var jsonContent []byte
 
+	loaderMu.Lock()
+	defer loaderMu.Unlock()
+
 	u, err := url.Parse(uri)
 	if err != nil {
 		clilog.Error.Println(err)
@@ -165,7 +160,7 @@ func LoadDocumentFromURI(uri string, validate bool, formatValidation bool) (stri
 	// see ./test/circular-reference.json and https://github.com/apigee/apigeecli/issues/199
 	openapi3.CircularReferenceCounter = 20
 
-	doc, err = openapi3.NewLoader().LoadFromURI(u)
+	g.doc, err = openapi3.NewLoader().LoadFromURI(u)
 	if err != nil {
 		return "", nil, err
 	}
@@ -178,13 +173,13 @@ func LoadDocumentFromURI(uri string, validate bool, formatValidation bool) (stri
 	}
 
 	if validate {
-		if err = doc.Validate(openapi3.NewLoader().Context); err != nil {
+		if err = g.doc.Validate(openapi3.NewLoader().Context); err != nil {
 			clilog.Error.Println(err)
 			return "", nil, err
 		}
 	}
 
-	if jsonContent, err = doc.MarshalJSON(); err != nil {
+	if jsonContent, err = g.doc.MarshalJSON(); err != nil {
 		clilog.Error.Println(err)
 		return "", nil, err
 	}
@@ -204,7 +199,7 @@ func isFileYaml(name string) bool {
 	return false
 }
 
-func GenerateAPIProxyDefFromOAS(name string,
+func (g *Generator) GenerateAPIProxyDefFromOAS(name string,
 	oasDocName string,
 	skipPolicy bool,
 	addCORS bool,
@@ -214,32 +209,34 @@ func GenerateAPIProxyDefFromOAS(name string,
 	oasTargetUrlRef string,
 	targetUrl string,
 ) (err error) {
-	if doc == nil {
+	if g.doc == nil {
 		return fmt.Errorf("the Open API document not loaded")
 	}
 
 	// load security schemes
-	loadSecurityRequirements(doc.Components.SecuritySchemes)
+	g.loadSecurityRequirements(g.doc.Components.SecuritySchemes)
 
-	apiproxy.SetDisplayName(name)
-	if doc.Info != nil {
-		if doc.Info.Description != "" {
-			apiproxy.SetDescription(doc.Info.Description)
+	g.apiProxy.SetDisplayName(name)
+	if g.doc.Info != nil {
+		if g.doc.Info.Description != "" {
+			g.apiProxy.SetDescription(g.doc.Info.Description)
 		}
 	}
 
-	apiproxy.SetCreatedAt()
-	apiproxy.SetLastModifiedAt()
-	apiproxy.SetConfigurationVersion()
-	apiproxy.AddTargetEndpoint(NoAuthTargetName)
-	apiproxy.AddProxyEndpoint("default")
+	g.apiProxy.SetCreatedAt()
+	g.apiProxy.SetLastModifiedAt()
+	g.apiProxy.SetConfigurationVersion()
+	if !g.mock {
+		g.apiProxy.AddTargetEndpoint(NoAuthTargetName)
+	}
+	g.apiProxy.AddProxyEndpoint("default")
 
 	if !skipPolicy {
-		apiproxy.AddResource(oasDocName, "oas")
-		apiproxy.AddPolicy("Validate-" + name + "-Schema")
+		g.apiProxy.AddResource(oasDocName, "oas")
+		g.apiProxy.AddPolicy("Validate-" + name + "-Schema")
 	}
 
-	u, err := getEndpoint(doc)
+	u, err := getEndpoint(g.doc)
 	if err != nil {
 		return err
 	}
@@ -248,72 +245,103 @@ func GenerateAPIProxyDefFromOAS(name string,
 		return fmt.Errorf("the OpenAPI url is missing a path. Don't use https://api.example.com, instead try https://api.example.com/basePath")
 	}
 
-	apiproxy.SetBasePath(u.Path)
+	g.apiProxy.SetBasePath(u.Path)
 
-	// if target is not set, derive it from the OAS file
-	if targetUrl == "" {
-		targets.NewTargetEndpoint(NoAuthTargetName, u.Scheme+"://"+u.Hostname()+u.Path, oasGoogleAcessTokenScopeLiteral, oasGoogleIdTokenAudLiteral, oasGoogleIdTokenAudRef)
+	// a mock proxy has no target, the flows respond with the examples
+	if g.mock {
+		targetUrl, oasTargetUrlRef = "", ""
+	} else if targetUrl == "" { // if target is not set, derive it from the OAS file
+		g.targetEndpoints.NewTargetEndpoint(NoAuthTargetName, u.Scheme+"://"+u.Hostname()+u.Path, oasGoogleAcessTokenScopeLiteral, oasGoogleIdTokenAudLiteral, oasGoogleIdTokenAudRef)
 	} else { // an explicit target url is set
 		if _, err = url.Parse(targetUrl); err != nil {
 			return fmt.Errorf("invalid target url: %v", err)
 		}
-		targets.NewTargetEndpoint(NoAuthTargetName, targetUrl, oasGoogleAcessTokenScopeLiteral, oasGoogleIdTokenAudLiteral, oasGoogleIdTokenAudRef)
+		g.targetEndpoints.NewTargetEndpoint(NoAuthTargetName, targetUrl, oasGoogleAcessTokenScopeLiteral, oasGoogleIdTokenAudLiteral, oasGoogleIdTokenAudRef)
 	}
 
 	// set a dynamic target url
 	if oasTargetUrlRef != "" {
-		targets.AddStepToPreFlowRequest("Set-Target-1", NoAuthTargetName)
-		apiproxy.AddPolicy("Set-Target-1")
-		generateSetTarget = true
+		g.targetEndpoints.AddStepToPreFlowRequest("Set-Target-1", NoAuthTargetName)
+		g.apiProxy.AddPolicy("Set-Target-1")
+		g.generateSetTarget = true
 	}
 
-	proxies.NewProxyEndpoint(u.Path, true)
+	g.proxyEndpoint = proxies.NewProxyEndpoint(u.Path, true)
+	if g.mock {
+		g.proxyEndpoint.SetNoRoute()
+	}
 
 	// add any preflow security schemes
-	if securityScheme := getSecurityRequirements(doc.Security); securityScheme.SchemeName != "" {
+	if securityScheme := g.getSecurityRequirements(g.doc.Security); securityScheme.SchemeName != "" {
 		if securityScheme.APIKeyPolicy.APIKeyPolicyEnabled {
-			proxies.AddStepToPreFlowRequest("Verify-API-Key-" + securityScheme.SchemeName)
+			g.proxyEndpoint.AddStepToPreFlowRequest("Verify-API-Key-" + securityScheme.SchemeName)
 		} else if securityScheme.OAuthPolicy.OAuthPolicyEnabled {
-			proxies.AddStepToPreFlowRequest("OAuth-v20-1")
+			g.proxyEndpoint.AddStepToPreFlowRequest("OAuth-v20-1")
 		}
 	}
 
 	// add any preflow quota or rate limit policies
-	if doc.Extensions != nil {
-		spikeArrestList, quotaList, err := processPreFlowExtensions(doc.Extensions)
+	if g.doc.Extensions != nil {
+		spikeArrestList, quotaList, err := g.processPreFlowExtensions(g.doc.Extensions)
 		if err != nil {
 			return err
 		}
 		if len(spikeArrestList) > 0 {
 			for _, spikeArrest := range spikeArrestList {
-				proxies.AddStepToPreFlowRequest("Spike-Arrest-" + spikeArrest.SpikeArrestName)
+				g.proxyEndpoint.AddStepToPreFlowRequest("Spike-Arrest-" + spikeArrest.SpikeArrestName)
 			}
 		}
 		if len(quotaList) > 0 {
 			for _, quota := range quotaList {
-				proxies.AddStepToPreFlowRequest("Quota-" + quota.QuotaName)
+				g.proxyEndpoint.AddStepToPreFlowRequest("Quota-" + quota.QuotaName)
 			}
 		}
 	}
 
+	// add any preflow policies and target server from the x-apigee extensions
+	docExtensions, err := g.processApigeeExtensions(g.doc.Extensions)
+	if err != nil {
+		return err
+	}
+	for _, step := range docExtensions.RequestSteps {
+		g.proxyEndpoint.AddStepToPreFlowRequest(step)
+	}
+	for _, step := range docExtensions.ResponseSteps {
+		g.proxyEndpoint.AddStepToPostFlowResponse(step)
+	}
+	// the document response caches are added to every operation flow, after
+	// the security policies of the operation
+	g.docCacheSteps = docExtensions.CacheSteps
+	if docExtensions.TargetServer != nil && !g.mock && targetUrl == "" && oasTargetUrlRef == "" {
+		g.targetEndpoints.SetLoadBalancer(NoAuthTargetName, []string{docExtensions.TargetServer.Name},
+			g.targetServerPath(docExtensions.TargetServer))
+	}
+
+	// load balance over the target servers of the OAS servers
+	if g.useTargetServers && !g.mock && targetUrl == "" && oasTargetUrlRef == "" {
+		if err = g.setTargetServers(name, u.Path); err != nil {
+			return err
+		}
+	}
+
 	if addCORS {
-		proxies.AddStepToPreFlowRequest("Add-CORS")
-		apiproxy.AddPolicy("Add-CORS")
+		g.proxyEndpoint.AddStepToPreFlowRequest("Add-CORS")
+		g.apiProxy.AddPolicy("Add-CORS")
 	}
 
 	if !skipPolicy {
-		proxies.AddStepToPreFlowRequest("OpenAPI-Spec-Validation-1")
+		g.proxyEndpoint.AddStepToPreFlowRequest("OpenAPI-Spec-Validation-1")
 	}
 
-	if err = generateFlows(doc.Paths); err != nil {
+	if err = g.generateFlows(g.doc.Paths); err != nil {
 		return err
 	}
 
-	for _, securityScheme := range securitySchemesList.SecuritySchemes {
+	for _, securityScheme := range g.securitySchemesList.SecuritySchemes {
 		if securityScheme.APIKeyPolicy.APIKeyPolicyEnabled {
-			apiproxy.AddPolicy("Verify-API-Key-" + securityScheme.SchemeName)
+			g.apiProxy.AddPolicy("Verify-API-Key-" + securityScheme.SchemeName)
 		} else if securityScheme.OAuthPolicy.OAuthPolicyEnabled {
-			apiproxy.AddPolicy("OAuth-v20-1")
+			g.apiProxy.AddPolicy("OAuth-v20-1")
 		}
 	}
 
@@ -328,7 +356,7 @@ func getEndpoint(doc *openapi3.T) (u *url.URL, err error) {
 	return url.Parse(doc.Servers[0].URL)
 }
 
-func getHTTPMethod(pathItem *openapi3.PathItem, keyPath string) (map[string]pathDetailDef, error) {
+func (g *Generator) getHTTPMethod(pathItem *openapi3.PathItem, keyPath string) (map[string]pathDetailDef, error) {
 	var err error
 	pathMap := make(map[string]pathDetailDef)
 	alternateOperationId := strings.ReplaceAll(keyPath, "\\", "_")
@@ -345,11 +373,11 @@ func getHTTPMethod(pathItem *openapi3.PathItem, keyPath string) (map[string]path
 		}
 		if pathItem.Get.Security != nil {
 			securityRequirements := []openapi3.SecurityRequirement(*pathItem.Get.Security)
-			getPathDetail.SecurityScheme = getSecurityRequirements(securityRequirements)
+			getPathDetail.SecurityScheme = g.getSecurityRequirements(securityRequirements)
 		}
 		// check for google extensions
 		if pathItem.Get.Extensions != nil {
-			if getPathDetail, err = processPathExtensions(pathItem.Get.Extensions, getPathDetail); err != nil {
+			if getPathDetail, err = g.processPathExtensions(pathItem.Get.Extensions, getPathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -368,11 +396,11 @@ func getHTTPMethod(pathItem *openapi3.PathItem, keyPath string) (map[string]path
 		}
 		if pathItem.Post.Security != nil {
 			securityRequirements := []openapi3.SecurityRequirement(*pathItem.Post.Security)
-			postPathDetail.SecurityScheme = getSecurityRequirements(securityRequirements)
+			postPathDetail.SecurityScheme = g.getSecurityRequirements(securityRequirements)
 		}
 		// check for google extensions
 		if pathItem.Post.Extensions != nil {
-			if postPathDetail, err = processPathExtensions(pathItem.Post.Extensions, postPathDetail); err != nil {
+			if postPathDetail, err = g.processPathExtensions(pathItem.Post.Extensions, postPathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -391,11 +419,11 @@ func getHTTPMethod(pathItem *openapi3.PathItem, keyPath string) (map[string]path
 		}
 		if pathItem.Put.Security != nil {
 			securityRequirements := []openapi3.SecurityRequirement(*pathItem.Put.Security)
-			putPathDetail.SecurityScheme = getSecurityRequirements(securityRequirements)
+			putPathDetail.SecurityScheme = g.getSecurityRequirements(securityRequirements)
 		}
 		// check for google extensions
 		if pathItem.Put.Extensions != nil {
-			if putPathDetail, err = processPathExtensions(pathItem.Put.Extensions, putPathDetail); err != nil {
+			if putPathDetail, err = g.processPathExtensions(pathItem.Put.Extensions, putPathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -414,11 +442,11 @@ func getHTTPMethod(pathItem *openapi3.PathItem, keyPath string) (map[string]path
 		}
 		if pathItem.Patch.Security != nil {
 			securityRequirements := []openapi3.SecurityRequirement(*pathItem.Patch.Security)
-			patchPathDetail.SecurityScheme = getSecurityRequirements(securityRequirements)
+			patchPathDetail.SecurityScheme = g.getSecurityRequirements(securityRequirements)
 		}
 		// check for google extensions
 		if pathItem.Patch.Extensions != nil {
-			if patchPathDetail, err = processPathExtensions(pathItem.Patch.Extensions, patchPathDetail); err != nil {
+			if patchPathDetail, err = g.processPathExtensions(pathItem.Patch.Extensions, patchPathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -437,11 +465,11 @@ func getHTTPMethod(pathItem *openapi3.PathItem, keyPath string) (map[string]path
 		}
 		if pathItem.Delete.Security != nil {
 			securityRequirements := []openapi3.SecurityRequirement(*pathItem.Delete.Security)
-			deletePathDetail.SecurityScheme = getSecurityRequirements(securityRequirements)
+			deletePathDetail.SecurityScheme = g.getSecurityRequirements(securityRequirements)
 		}
 		// check for google extensions
 		if pathItem.Delete.Extensions != nil {
-			if deletePathDetail, err = processPathExtensions(pathItem.Delete.Extensions, deletePathDetail); err != nil {
+			if deletePathDetail, err = g.processPathExtensions(pathItem.Delete.Extensions, deletePathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -490,30 +518,62 @@ func getHTTPMethod(pathItem *openapi3.PathItem, keyPath string) (map[string]path
 	return pathMap, nil
 }
 
-func generateFlows(paths openapi3.Paths) (err error) {
-	for keyPath := range paths {
-		pathMap, err := getHTTPMethod(paths[keyPath], keyPath)
+func (g *Generator) generateFlows(paths openapi3.Paths) (err error) {
+	// sorted, so that regenerated proxies only change with the spec
+	for _, keyPath := range sortedKeys(paths) {
+		pathMap, err := g.getHTTPMethod(paths[keyPath], keyPath)
 		if err != nil {
 			return err
 		}
-		for method, pathDetail := range pathMap {
-			proxies.AddFlow(pathDetail.OperationID, replacePathWithWildCard(keyPath), method, pathDetail.Description)
+		for _, method := range sortedKeys(pathMap) {
+			pathDetail := pathMap[method]
+			g.proxyEndpoint.AddFlow(pathDetail.OperationID, replacePathWithWildCard(keyPath), method, pathDetail.Description)
 			if pathDetail.SecurityScheme.OAuthPolicy.OAuthPolicyEnabled {
-				if err = proxies.AddStepToFlowRequest("OAuth-v20-1", pathDetail.OperationID); err != nil {
+				if err = g.proxyEndpoint.AddStepToFlowRequest("OAuth-v20-1", pathDetail.OperationID); err != nil {
 					return err
 				}
 			} else if pathDetail.SecurityScheme.APIKeyPolicy.APIKeyPolicyEnabled {
-				if err = proxies.AddStepToFlowRequest("Verify-API-Key-"+pathDetail.SecurityScheme.SchemeName, pathDetail.OperationID); err != nil {
+				if err = g.proxyEndpoint.AddStepToFlowRequest("Verify-API-Key-"+pathDetail.SecurityScheme.SchemeName, pathDetail.OperationID); err != nil {
 					return err
 				}
 			}
 			if pathDetail.SpikeArrest.SpikeArrestEnabled {
-				if err = proxies.AddStepToFlowRequest("Spike-Arrest-"+pathDetail.SpikeArrest.SpikeArrestName, pathDetail.OperationID); err != nil {
+				if err = g.proxyEndpoint.AddStepToFlowRequest("Spike-Arrest-"+pathDetail.SpikeArrest.SpikeArrestName, pathDetail.OperationID); err != nil {
 					return err
 				}
 			}
 			if pathDetail.Quota.QuotaEnabled {
-				if err = proxies.AddStepToFlowRequest("Quota-"+pathDetail.Quota.QuotaName, pathDetail.OperationID); err != nil {
+				if err = g.proxyEndpoint.AddStepToFlowRequest("Quota-"+pathDetail.Quota.QuotaName, pathDetail.OperationID); err != nil {
+					return err
+				}
+			}
+			for _, step := range pathDetail.Extensions.RequestSteps {
+				if err = g.proxyEndpoint.AddStepToFlowRequest(step, pathDetail.OperationID); err != nil {
+					return err
+				}
+			}
+			for _, step := range pathDetail.Extensions.ResponseSteps {
+				if err = g.proxyEndpoint.AddStepToFlowResponse(step, pathDetail.OperationID); err != nil {
+					return err
+				}
+			}
+			for _, step := range g.flowCacheSteps(pathDetail.Extensions.CacheSteps) {
+				if err = g.proxyEndpoint.AddStepToFlowRequest(step, pathDetail.OperationID); err != nil {
+					return err
+				}
+				if err = g.proxyEndpoint.AddStepToFlowResponse(step, pathDetail.OperationID); err != nil {
+					return err
+				}
+			}
+			if pathDetail.Extensions.TargetServer != nil && !g.mock {
+				endpointName, err := g.addTargetServerEndpoint(pathDetail.Extensions.TargetServer)
+				if err != nil {
+					return err
+				}
+				g.proxyEndpoint.AddRoute(pathDetail.OperationID, endpointName, getConditionString(keyPath, method))
+			}
+			if g.mock {
+				if err = g.addMockResponse(paths[keyPath].GetOperation(strings.ToUpper(method)), pathDetail.OperationID); err != nil {
 					return err
 				}
 			}
@@ -522,8 +582,19 @@ func generateFlows(paths openapi3.Paths) (err error) {
 	return nil
 }
 
-func GenerateSetTargetPolicy() bool {
-	return generateSetTarget
+// flowCacheSteps returns the response caches of the document followed by
+// those of the operation, without duplicates
+func (g *Generator) flowCacheSteps(operationCacheSteps []string) (steps []string) {
+	for _, step := range append(append([]string{}, g.docCacheSteps...), operationCacheSteps...) {
+		if !contains(steps, step) {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+func (g *Generator) GenerateSetTargetPolicy() bool {
+	return g.generateSetTarget
 }
 
 func replacePathWithWildCard(keyPath string) string {
@@ -572,31 +643,31 @@ func loadSecurityType(secSchemeName string, securityScheme openapi3.SecuritySche
 	return secScheme
 }
 
-func getSecurityRequirements(securityRequirements []openapi3.SecurityRequirement) securitySchemesDef {
+func (g *Generator) getSecurityRequirements(securityRequirements []openapi3.SecurityRequirement) securitySchemesDef {
 	for _, secReq := range securityRequirements {
 		for secReqName := range secReq {
-			return getSecurityType(secReqName)
+			return g.getSecurityType(secReqName)
 		}
 	}
 	return securitySchemesDef{}
 }
 
-func loadSecurityRequirements(securitySchemes openapi3.SecuritySchemes) {
+func (g *Generator) loadSecurityRequirements(securitySchemes openapi3.SecuritySchemes) {
 	for secSchemeName, secScheme := range securitySchemes {
-		securitySchemesList.SecuritySchemes = append(securitySchemesList.SecuritySchemes, loadSecurityType(secSchemeName, *secScheme))
+		g.securitySchemesList.SecuritySchemes = append(g.securitySchemesList.SecuritySchemes, loadSecurityType(secSchemeName, *secScheme))
 	}
 }
 
-func GetSecuritySchemesList() []securitySchemesDef {
-	return securitySchemesList.SecuritySchemes
+func (g *Generator) GetSecuritySchemesList() []securitySchemesDef {
+	return g.securitySchemesList.SecuritySchemes
 }
 
-func getQuotaDefinition(i interface{}) (quotaDef, error) {
+func (g *Generator) getQuotaDefinition(i interface{}) (quotaDef, error) {
 	var jsonArrayMap []map[string]interface{}
 
 	quota := quotaDef{}
 	jsonMap := map[string]string{}
-	str := fmt.Sprintf("%s", i)
+	str := extensionString(i)
 
 	if err := json.Unmarshal([]byte(str), &jsonArrayMap); err != nil {
 		return quotaDef{}, err
@@ -652,7 +723,7 @@ func getQuotaDefinition(i interface{}) (quotaDef, error) {
 	}
 
 	// store policy XML contents
-	quotaPolicyContent[quota.QuotaName] = policies.AddQuotaPolicy(
+	g.quotaPolicyContent[quota.QuotaName] = policies.AddQuotaPolicy(
 		"Quota-"+quota.QuotaName,
 		quota.QuotaConfigStepName,
 		quota.QuotaAllowRef,
@@ -667,12 +738,12 @@ func getQuotaDefinition(i interface{}) (quotaDef, error) {
 	return quota, nil
 }
 
-func getSpikeArrestDefinition(i interface{}) (spikeArrestDef, error) {
+func (g *Generator) getSpikeArrestDefinition(i interface{}) (spikeArrestDef, error) {
 	var jsonArrayMap []map[string]interface{}
 
 	spikeArrest := spikeArrestDef{}
 	jsonMap := map[string]string{}
-	str := fmt.Sprintf("%s", i)
+	str := extensionString(i)
 
 	if err := json.Unmarshal([]byte(str), &jsonArrayMap); err != nil {
 		return spikeArrestDef{}, err
@@ -706,7 +777,7 @@ func getSpikeArrestDefinition(i interface{}) (spikeArrestDef, error) {
 	}
 
 	// store policy XML contents
-	spikeArrestPolicyContent[spikeArrest.SpikeArrestName] = policies.AddSpikeArrestPolicy("Spike-Arrest-"+spikeArrest.SpikeArrestName,
+	g.spikeArrestPolicyContent[spikeArrest.SpikeArrestName] = policies.AddSpikeArrestPolicy("Spike-Arrest-"+spikeArrest.SpikeArrestName,
 		spikeArrest.SpikeArrestIdentifierRef,
 		spikeArrest.SpikeArrestRateRef,
 		spikeArrest.SpikeArrestRateLiteral)
@@ -714,22 +785,26 @@ func getSpikeArrestDefinition(i interface{}) (spikeArrestDef, error) {
 	return spikeArrest, nil
 }
 
-func processPathExtensions(extensions map[string]interface{}, pathDetail pathDetailDef) (pathDetailDef, error) {
+func (g *Generator) processPathExtensions(extensions map[string]interface{}, pathDetail pathDetailDef) (pathDetailDef, error) {
 	var err error
 	for extensionName, extensionValue := range extensions {
 		if extensionName == "x-google-ratelimit" {
 			// process ratelimit
-			pathDetail.SpikeArrest, err = getSpikeArrestDefinition(extensionValue)
+			pathDetail.SpikeArrest, err = g.getSpikeArrestDefinition(extensionValue)
 		}
 		if extensionName == "x-google-quota" {
 			// process quota
-			pathDetail.Quota, err = getQuotaDefinition(extensionValue)
+			pathDetail.Quota, err = g.getQuotaDefinition(extensionValue)
 		}
 	}
+	if err != nil {
+		return pathDetail, err
+	}
+	pathDetail.Extensions, err = g.processApigeeExtensions(extensions)
 	return pathDetail, err
 }
 
-func processPreFlowExtensions(extensions map[string]interface{}) ([]spikeArrestDef, []quotaDef, error) {
+func (g *Generator) processPreFlowExtensions(extensions map[string]interface{}) ([]spikeArrestDef, []quotaDef, error) {
 	var err error
 	spikeArrestList := []spikeArrestDef{}
 	quotaList := []quotaDef{}
@@ -737,7 +812,7 @@ func processPreFlowExtensions(extensions map[string]interface{}) ([]spikeArrestD
 	for extensionName, extensionValue := range extensions {
 		if extensionName == "x-google-ratelimit" {
 			// process ratelimit
-			spikeArrest, err := getSpikeArrestDefinition(extensionValue)
+			spikeArrest, err := g.getSpikeArrestDefinition(extensionValue)
 			if err != nil {
 				return []spikeArrestDef{}, []quotaDef{}, err
 			}
@@ -745,7 +820,7 @@ func processPreFlowExtensions(extensions map[string]interface{}) ([]spikeArrestD
 		}
 		if extensionName == "x-google-quota" {
 			// process quota
-			quota, err := getQuotaDefinition(extensionValue)
+			quota, err := g.getQuotaDefinition(extensionValue)
 			if err != nil {
 				return []spikeArrestDef{}, []quotaDef{}, err
 			}
@@ -755,18 +830,36 @@ func processPreFlowExtensions(extensions map[string]interface{}) ([]spikeArrestD
 	return spikeArrestList, quotaList, err
 }
 
-func GetSpikeArrestPolicies() map[string]string {
-	return spikeArrestPolicyContent
+func (g *Generator) GetSpikeArrestPolicies() map[string]string {
+	return g.spikeArrestPolicyContent
 }
 
-func GetQuotaPolicies() map[string]string {
-	return quotaPolicyContent
+func (g *Generator) GetQuotaPolicies() map[string]string {
+	return g.quotaPolicyContent
 }
 
 func readScopes(scopes map[string]string) string {
-	scopeString := ""
-	for scopeName := range scopes {
-		scopeString = scopeName + " " + scopeString
+	return strings.Join(sortedKeys(scopes), " ")
+}
+
+// extensionString returns the JSON of an extension value. Depending on the
+// loader, extensions are either raw JSON or decoded values
+func extensionString(i interface{}) string {
+	if raw, ok := i.(json.RawMessage); ok {
+		return string(raw)
+	}
+	data, err := json.Marshal(i)
+	if err != nil {
+		return fmt.Sprintf("%s", i)
+	}
+	return string(data)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
-	return strings.TrimSpace(scopeString)
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,250 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"internal/bundlegen/policies"
+)
+
+// apigeeExtensionsDef holds the steps and target added by the x-apigee
+// extensions of the document or of an operation
+type apigeeExtensionsDef struct {
+	RequestSteps  []string
+	ResponseSteps []string
+	// CacheSteps are looked up at the end of the request and populated at
+	// the end of the response of each operation flow, so that a cached
+	// response is only returned once the security policies have run
+	CacheSteps   []string
+	TargetServer *targetServerExtDef
+}
+
+type responseCacheExtDef struct {
+	Name         string   `json:"name"`
+	TTL          *int     `json:"ttl,omitempty"`
+	TTLRef       string   `json:"ttl-ref,omitempty"`
+	KeyFragments []string `json:"key-fragments,omitempty"`
+}
+
+type assignMessageExtDef struct {
+	Name          string            `json:"name"`
+	Flow          string            `json:"flow,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	QueryParams   map[string]string `json:"query-params,omitempty"`
+	RemoveHeaders []string          `json:"remove-headers,omitempty"`
+}
+
+type verifyJWTExtDef struct {
+	Name     string `json:"name"`
+	JwksURI  string `json:"jwks-uri"`
+	Issuer   string `json:"issuer,omitempty"`
+	Audience string `json:"audience,omitempty"`
+	Source   string `json:"source,omitempty"`
+}
+
+type flowCalloutExtDef struct {
+	Name       string            `json:"name"`
+	SharedFlow string            `json:"shared-flow"`
+	Flow       string            `json:"flow,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+type targetServerExtDef struct {
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+}
+
+// processApigeeExtensions reads the x-apigee extensions. The policies are
+// stored in the generator and their steps are returned in the order the
+// extensions are listed here
+func (g *Generator) processApigeeExtensions(extensions map[string]interface{}) (ext apigeeExtensionsDef, err error) {
+	if i, ok := extensions["x-apigee-verify-jwt"]; ok {
+		var jwts []verifyJWTExtDef
+		if err = decodeExtension("x-apigee-verify-jwt", i, &jwts); err != nil {
+			return ext, err
+		}
+		for _, jwt := range jwts {
+			if jwt.Name == "" || jwt.JwksURI == "" {
+				return ext, fmt.Errorf("x-apigee-verify-jwt extension must have a name and a jwks-uri")
+			}
+			jwtPolicy := jwtPolicyDef{
+				JWTPolicyEnabled: true,
+				JwkUri:           jwt.JwksURI,
+				Issuer:           jwt.Issuer,
+				Audience:         jwt.Audience,
+				Source:           jwt.Source,
+			}
+			policyName := "VerifyJWT-" + jwt.Name
+			if err = g.addExtensionPolicy(policyName, policies.AddVerifyJWTPolicy(policyName,
+				jwtPolicy.JwkUri, jwtPolicy.Issuer, jwtPolicy.Audience, jwtPolicy.Source)); err != nil {
+				return ext, err
+			}
+			ext.RequestSteps = append(ext.RequestSteps, policyName)
+		}
+	}
+
+	if i, ok := extensions["x-apigee-response-cache"]; ok {
+		var caches []responseCacheExtDef
+		if err = decodeExtension("x-apigee-response-cache", i, &caches); err != nil {
+			return ext, err
+		}
+		for _, cache := range caches {
+			if cache.Name == "" {
+				return ext, fmt.Errorf("x-apigee-response-cache extension must have a name")
+			}
+			if (cache.TTL == nil) == (cache.TTLRef == "") {
+				return ext, fmt.Errorf("x-apigee-response-cache extension must have either ttl or ttl-ref")
+			}
+			if cache.TTL != nil && *cache.TTL < 1 {
+				return ext, fmt.Errorf("x-apigee-response-cache extension ttl must be at least 1 second")
+			}
+			ttl := ""
+			if cache.TTL != nil {
+				ttl = strconv.Itoa(*cache.TTL)
+			}
+			policyName := "RC-" + cache.Name
+			if err = g.addExtensionPolicy(policyName,
+				policies.AddResponseCachePolicy(policyName, ttl, cache.TTLRef, cache.KeyFragments)); err != nil {
+				return ext, err
+			}
+			ext.CacheSteps = append(ext.CacheSteps, policyName)
+		}
+	}
+
+	if i, ok := extensions["x-apigee-assign-message"]; ok {
+		var assignMessages []assignMessageExtDef
+		if err = decodeExtension("x-apigee-assign-message", i, &assignMessages); err != nil {
+			return ext, err
+		}
+		for _, assignMessage := range assignMessages {
+			if assignMessage.Name == "" {
+				return ext, fmt.Errorf("x-apigee-assign-message extension must have a name")
+			}
+			if err = validateExtensionFlow("x-apigee-assign-message", assignMessage.Flow); err != nil {
+				return ext, err
+			}
+			if assignMessage.Flow == "response" && len(assignMessage.QueryParams) > 0 {
+				return ext, fmt.Errorf("x-apigee-assign-message extension cannot set query-params in the response")
+			}
+			policyName := "AM-" + assignMessage.Name
+			if err = g.addExtensionPolicy(policyName, policies.AddAssignMessagePolicy(policyName, assignMessage.Flow,
+				assignMessage.Headers, assignMessage.QueryParams, assignMessage.RemoveHeaders)); err != nil {
+				return ext, err
+			}
+			ext.addStep(assignMessage.Flow, policyName)
+		}
+	}
+
+	if i, ok := extensions["x-apigee-flow-callout"]; ok {
+		var flowCallouts []flowCalloutExtDef
+		if err = decodeExtension("x-apigee-flow-callout", i, &flowCallouts); err != nil {
+			return ext, err
+		}
+		for _, flowCallout := range flowCallouts {
+			if flowCallout.Name == "" || flowCallout.SharedFlow == "" {
+				return ext, fmt.Errorf("x-apigee-flow-callout extension must have a name and a shared-flow")
+			}
+			if err = validateExtensionFlow("x-apigee-flow-callout", flowCallout.Flow); err != nil {
+				return ext, err
+			}
+			policyName := "FC-" + flowCallout.Name
+			if err = g.addExtensionPolicy(policyName,
+				policies.AddFlowCalloutPolicy(policyName, flowCallout.SharedFlow, flowCallout.Parameters)); err != nil {
+				return ext, err
+			}
+			ext.addStep(flowCallout.Flow, policyName)
+		}
+	}
+
+	if i, ok := extensions["x-apigee-target-server"]; ok {
+		ext.TargetServer = &targetServerExtDef{}
+		if err = decodeExtension("x-apigee-target-server", i, ext.TargetServer); err != nil {
+			return ext, err
+		}
+		if ext.TargetServer.Name == "" {
+			return ext, fmt.Errorf("x-apigee-target-server extension must have a name")
+		}
+	}
+
+	return ext, nil
+}
+
+func (ext *apigeeExtensionsDef) addStep(flow string, name string) {
+	if flow == "response" {
+		ext.ResponseSteps = append(ext.ResponseSteps, name)
+	} else {
+		ext.RequestSteps = append(ext.RequestSteps, name)
+	}
+}
+
+// addExtensionPolicy stores the policy XML contents. The same policy can be
+// used by several operations, as long as it is defined the same way
+func (g *Generator) addExtensionPolicy(name string, content string) error {
+	if existing, ok := g.extensionPolicyContent[name]; ok && existing != content {
+		return fmt.Errorf("policy %s is defined more than once with different settings", name)
+	}
+	g.extensionPolicyContent[name] = content
+	g.apiProxy.AddPolicy(name)
+	return nil
+}
+
+// GetExtensionPolicies returns the policies of the x-apigee extensions
+func (g *Generator) GetExtensionPolicies() map[string]string {
+	return g.extensionPolicyContent
+}
+
+// addTargetServerEndpoint adds a TargetEndpoint for a target server, with the
+// connection settings of the default TargetEndpoint
+func (g *Generator) addTargetServerEndpoint(targetServer *targetServerExtDef) (string, error) {
+	endpointName := "ts-" + targetServer.Name
+	if !g.targetEndpoints.IsExists(endpointName) {
+		if err := g.targetEndpoints.CopyTargetEndpoint(NoAuthTargetName, endpointName); err != nil {
+			return "", err
+		}
+		g.apiProxy.AddTargetEndpoint(endpointName)
+	}
+	g.targetEndpoints.SetLoadBalancer(endpointName, []string{targetServer.Name}, g.targetServerPath(targetServer))
+	return endpointName, nil
+}
+
+// targetServerPath returns the path of a target server, the base path of the
+// API proxy unless set in the extension
+func (g *Generator) targetServerPath(targetServer *targetServerExtDef) string {
+	if targetServer.Path != "" {
+		return targetServer.Path
+	}
+	return g.apiProxy.BasePaths
+}
+
+func validateExtensionFlow(extensionName string, flow string) error {
+	if flow != "" && flow != "request" && flow != "response" {
+		return fmt.Errorf("%s extension flow must be either request or response", extensionName)
+	}
+	return nil
+}
+
+// decodeExtension decodes an extension value, unknown properties are rejected
+func decodeExtension(extensionName string, i interface{}, v interface{}) error {
+	decoder := json.NewDecoder(strings.NewReader(extensionString(i)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid %s extension: %v", extensionName, err)
+	}
+	return nil
+}
@@ -17,13 +17,105 @@ package bundlegen
 import (
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
 
-	apiproxy "internal/bundlegen/apiproxydef"
 	"internal/bundlegen/proxies"
-	targets "internal/bundlegen/targets"
 )
 
-func GenerateAPIProxyDefFromGQL(name string,
+// GraphQLOperationTypes are the root operation types of a GraphQL schema
+// supported by the GraphQL policy
+var GraphQLOperationTypes = []string{"query", "mutation"}
+
+// GraphQLOperations maps the root operation types of a schema to the fields
+// of their root types
+type GraphQLOperations map[string][]string
+
+// PolicyOperationType returns the operation type to validate with the GraphQL
+// policy; query, mutation or query_mutation
+func (operations GraphQLOperations) PolicyOperationType() string {
+	_, query := operations["query"]
+	_, mutation := operations["mutation"]
+	switch {
+	case query && mutation:
+		return "query_mutation"
+	case mutation:
+		return "mutation"
+	default:
+		return "query"
+	}
+}
+
+var (
+	gqlCommentsAndStrings = regexp.MustCompile(`(?s)""".*?"""|"(?:[^"\\\n]|\\.)*"|#[^\n]*`)
+	gqlSchema             = regexp.MustCompile(`\bschema\s*(?:@\w+\s*)*\{([^}]*)\}`)
+	gqlSchemaOperation    = regexp.MustCompile(`\b(query|mutation)\s*:\s*(\w+)`)
+	gqlType               = regexp.MustCompile(`\btype\s+(\w+)[^{]*\{`)
+	gqlField              = regexp.MustCompile(`(\w+)\s*:`)
+)
+
+// ParseGraphQLSchema returns the fields of the query and mutation root types
+// of a GraphQL schema, including the fields of type extensions
+func ParseGraphQLSchema(content string) (operations GraphQLOperations, err error) {
+	// drop comments and descriptions, they may contain braces or colons
+	content = gqlCommentsAndStrings.ReplaceAllString(content, " ")
+
+	rootTypes := map[string]string{
+		"Query":    "query",
+		"Mutation": "mutation",
+	}
+	if match := gqlSchema.FindStringSubmatch(content); match != nil {
+		rootTypes = map[string]string{}
+		for _, operation := range gqlSchemaOperation.FindAllStringSubmatch(match[1], -1) {
+			rootTypes[operation[2]] = operation[1]
+		}
+	}
+
+	operations = GraphQLOperations{}
+	for _, loc := range gqlType.FindAllStringSubmatchIndex(content, -1) {
+		operationType, ok := rootTypes[content[loc[2]:loc[3]]]
+		if !ok {
+			continue
+		}
+		body, err := gqlTypeBody(content[loc[1]:])
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %v", content[loc[2]:loc[3]], err)
+		}
+		for _, field := range gqlField.FindAllStringSubmatch(body, -1) {
+			if !contains(operations[operationType], field[1]) {
+				operations[operationType] = append(operations[operationType], field[1])
+			}
+		}
+	}
+
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("no query or mutation fields found in the schema")
+	}
+	return operations, nil
+}
+
+// gqlTypeBody returns the fields of a type body without their arguments,
+// only field names are followed by a colon then
+func gqlTypeBody(content string) (string, error) {
+	var b strings.Builder
+	depth := 0
+	for _, c := range content {
+		switch {
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case depth > 0:
+		case c == '}':
+			return b.String(), nil
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return "", fmt.Errorf("missing closing brace")
+}
+
+func (g *Generator) GenerateAPIProxyDefFromGQL(name string,
 	gqlDocName string,
 	basePath string,
 	apiKeyLocation string,
@@ -31,51 +123,74 @@ func GenerateAPIProxyDefFromGQL(name string,
 	addCORS bool,
 	targetUrlRef string,
 	targetUrl string,
+	operations GraphQLOperations,
+	quotas map[string]string,
 ) (err error) {
-	apiproxy.SetDisplayName(name)
-	apiproxy.SetCreatedAt()
-	apiproxy.SetLastModifiedAt()
-	apiproxy.SetConfigurationVersion()
-	apiproxy.AddTargetEndpoint(NoAuthTargetName)
-	apiproxy.AddProxyEndpoint("default")
+	g.apiProxy.SetDisplayName(name)
+	g.apiProxy.SetCreatedAt()
+	g.apiProxy.SetLastModifiedAt()
+	g.apiProxy.SetConfigurationVersion()
+	g.apiProxy.AddTargetEndpoint(NoAuthTargetName)
+	g.apiProxy.AddProxyEndpoint("default")
 
-	apiproxy.SetDescription("Generated API Proxy from " + gqlDocName)
+	g.apiProxy.SetDescription("Generated API Proxy from " + gqlDocName)
 
 	if !skipPolicy {
-		apiproxy.AddResource(gqlDocName, "graphql")
-		apiproxy.AddPolicy("Validate-" + name + "-Schema")
+		g.apiProxy.AddResource(gqlDocName, "graphql")
+		g.apiProxy.AddPolicy("Validate-" + name + "-Schema")
 	}
 
-	proxies.NewProxyEndpoint(basePath, true)
+	g.proxyEndpoint = proxies.NewProxyEndpoint(basePath, true)
 
 	if addCORS {
-		proxies.AddStepToPreFlowRequest("Add-CORS")
-		apiproxy.AddPolicy("Add-CORS")
+		g.proxyEndpoint.AddStepToPreFlowRequest("Add-CORS")
+		g.apiProxy.AddPolicy("Add-CORS")
 	}
 
 	// if target is not set, add a default/fake endpoint
 	if targetUrl == "" {
-		targets.NewTargetEndpoint(NoAuthTargetName, "https://api.example.com", "", "", "")
+		g.targetEndpoints.NewTargetEndpoint(NoAuthTargetName, "https://api.example.com", "", "", "")
 	} else { // an explicit target url is set
 		if _, err = url.Parse(targetUrl); err != nil {
 			return fmt.Errorf("invalid target url: %v", err)
 		}
-		targets.NewTargetEndpoint(NoAuthTargetName, targetUrl, "", "", "")
+		g.targetEndpoints.NewTargetEndpoint(NoAuthTargetName, targetUrl, "", "", "")
 	}
 
 	// set a dynamic target url
 	if targetUrlRef != "" {
-		targets.AddStepToPreFlowRequest("Set-Target-1", NoAuthTargetName)
-		apiproxy.AddPolicy("Set-Target-1")
-		generateSetTarget = true
+		g.targetEndpoints.AddStepToPreFlowRequest("Set-Target-1", NoAuthTargetName)
+		g.apiProxy.AddPolicy("Set-Target-1")
+		g.generateSetTarget = true
 	}
 
 	if !skipPolicy {
-		proxies.AddStepToPreFlowRequest("Validate-" + name + "-Schema")
+		g.proxyEndpoint.AddStepToPreFlowRequest("Validate-" + name + "-Schema")
 	}
 
 	if apiKeyLocation != "" {
-		proxies.AddStepToPreFlowRequest("Verify-API-Key-" + name)
+		g.proxyEndpoint.AddStepToPreFlowRequest("Verify-API-Key-" + name)
+	}
+
+	// the GraphQL policy sets graphql.operation.type, add a flow per root
+	// operation type of the schema
+	if !skipPolicy {
+		for _, operationType := range GraphQLOperationTypes {
+			fields, ok := operations[operationType]
+			if !ok {
+				continue
+			}
+			g.proxyEndpoint.AddConditionalFlow(operationType,
+				"(graphql.operation.type = \""+operationType+"\")",
+				"Operations: "+strings.Join(fields, ", "))
+
+			if quotas[operationType] != "" {
+				if err = g.proxyEndpoint.AddStepToFlowRequest("Quota-"+operationType, operationType); err != nil {
+					return err
+				}
+				g.apiProxy.AddPolicy("Quota-" + operationType)
+			}
+		}
 	}
 
 	return err
@@ -0,0 +1,132 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGraphQLSchema(t *testing.T) {
+	schema, err := os.ReadFile(filepath.Join("..", "..", "test", "schema.graphql"))
+	if err != nil {
+		t.Fatalf("failed to read the schema: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		content       string
+		want          GraphQLOperations
+		wantOperation string
+		wantErr       string
+	}{
+		{
+			name:          "test schema",
+			content:       string(schema),
+			want:          GraphQLOperations{"query": {"getOrder", "listOrders"}},
+			wantOperation: "query",
+		},
+		{
+			name: "query and mutation",
+			content: `type Query { book(id: ID!): Book }
+type Mutation { addBook(title: String!, author: String): Book }`,
+			want:          GraphQLOperations{"query": {"book"}, "mutation": {"addBook"}},
+			wantOperation: "query_mutation",
+		},
+		{
+			name: "mutation only",
+			content: `type Mutation { reset: Boolean }
+type Book { title: String }`,
+			want:          GraphQLOperations{"mutation": {"reset"}},
+			wantOperation: "mutation",
+		},
+		{
+			name: "comments and descriptions",
+			content: `# type Mutation { commented: Boolean }
+"""
+A description with a brace } and type Mutation { described: Boolean }
+"""
+type Query {
+  "the book, see {id}"
+  book(id: ID!): Book # trailing } brace
+  # hidden: Boolean
+  books: [Book]
+}`,
+			want:          GraphQLOperations{"query": {"book", "books"}},
+			wantOperation: "query",
+		},
+		{
+			name: "arguments with defaults",
+			content: `type Query {
+  books(filter: Filter = {title: "a)b", limit: 10}, first: Int = 5): [Book]
+  count: Int
+}`,
+			want:          GraphQLOperations{"query": {"books", "count"}},
+			wantOperation: "query",
+		},
+		{
+			name: "type extensions are merged",
+			content: `type Query implements Node @key(fields: "id") { book: Book }
+extend type Query { author: Author book: Book }
+extend type Mutation { addAuthor(name: String): Author }`,
+			want:          GraphQLOperations{"query": {"book", "author"}, "mutation": {"addAuthor"}},
+			wantOperation: "query_mutation",
+		},
+		{
+			name: "schema remaps the root types",
+			content: `schema { query: RootQuery mutation: RootMutation }
+type Query { ignored: Boolean }
+type RootQuery { book: Book }
+type RootMutation { addBook: Book }`,
+			want:          GraphQLOperations{"query": {"book"}, "mutation": {"addBook"}},
+			wantOperation: "query_mutation",
+		},
+		{
+			name:    "missing closing brace",
+			content: `type Query { book: Book`,
+			wantErr: "type Query: missing closing brace",
+		},
+		{
+			name: "no root types",
+			content: `type Book { title: String }
+type Subscription { bookAdded: Book }`,
+			wantErr: "no query or mutation fields found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGraphQLSchema(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseGraphQLSchema() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGraphQLSchema() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGraphQLSchema() = %v, want %v", got, tt.want)
+			}
+			if operation := got.PolicyOperationType(); operation != tt.wantOperation {
+				t.Errorf("PolicyOperationType() = %s, want %s", operation, tt.wantOperation)
+			}
+		})
+	}
+}
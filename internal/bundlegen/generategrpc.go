@@ -0,0 +1,147 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"internal/bundlegen/proxies"
+)
+
+// GrpcService is a service of a proto file and its methods
+type GrpcService struct {
+	// Name is the fully qualified name of the service, ex: helloworld.Greeter
+	Name    string
+	Methods []string
+}
+
+var (
+	protoCommentsAndStrings = regexp.MustCompile(`(?s)"(?:[^"\\\n]|\\.)*"|'(?:[^'\\\n]|\\.)*'|/\*.*?\*/|//[^\n]*`)
+	protoPackage            = regexp.MustCompile(`\bpackage\s+([\w.]+)\s*;`)
+	protoService            = regexp.MustCompile(`\bservice\s+(\w+)\s*\{`)
+	protoMethod             = regexp.MustCompile(`\brpc\s+(\w+)\s*\(`)
+)
+
+// ParseProto returns the services and methods defined in the content of a
+// proto file
+func ParseProto(content string) (services []GrpcService, err error) {
+	// drop comments and string literals, they may contain braces or keywords
+	content = protoCommentsAndStrings.ReplaceAllStringFunc(content, func(s string) string {
+		if strings.HasPrefix(s, "/") {
+			return " "
+		}
+		return `""`
+	})
+
+	prefix := ""
+	if match := protoPackage.FindStringSubmatch(content); match != nil {
+		prefix = match[1] + "."
+	}
+
+	for _, loc := range protoService.FindAllStringSubmatchIndex(content, -1) {
+		body, err := protoBlock(content[loc[1]:])
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %v", content[loc[2]:loc[3]], err)
+		}
+		service := GrpcService{Name: prefix + content[loc[2]:loc[3]]}
+		for _, method := range protoMethod.FindAllStringSubmatch(body, -1) {
+			service.Methods = append(service.Methods, method[1])
+		}
+		services = append(services, service)
+	}
+
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services found in the proto file")
+	}
+	return services, nil
+}
+
+// protoBlock returns the content up to the brace closing an opened block
+func protoBlock(content string) (string, error) {
+	depth := 1
+	for i, c := range content {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return content[:i], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("missing closing brace")
+}
+
+func (g *Generator) GenerateAPIProxyDefFromGRPC(name string,
+	protoName string,
+	services []GrpcService,
+	basePath string,
+	targetServer string,
+	apiKeyLocation string,
+	quotaLimit string,
+	addCORS bool,
+) (err error) {
+	g.apiProxy.SetDisplayName(name)
+	g.apiProxy.SetCreatedAt()
+	g.apiProxy.SetLastModifiedAt()
+	g.apiProxy.SetConfigurationVersion()
+	g.apiProxy.AddTargetEndpoint(NoAuthTargetName)
+	g.apiProxy.AddProxyEndpoint("default")
+
+	g.apiProxy.SetDescription("Generated API Proxy from " + protoName)
+
+	g.proxyEndpoint = proxies.NewProxyEndpoint(basePath, true)
+
+	if addCORS {
+		g.proxyEndpoint.AddStepToPreFlowRequest("Add-CORS")
+		g.apiProxy.AddPolicy("Add-CORS")
+	}
+
+	// the target is a GRPC target server
+	g.targetEndpoints.NewTargetEndpoint(NoAuthTargetName, "", "", "", "")
+	g.targetEndpoints.SetLoadBalancer(NoAuthTargetName, []string{targetServer}, "")
+
+	if apiKeyLocation != "" {
+		g.proxyEndpoint.AddStepToPreFlowRequest("Verify-API-Key-" + name)
+		g.apiProxy.AddPolicy("Verify-API-Key-" + name)
+	}
+
+	if quotaLimit != "" {
+		g.proxyEndpoint.AddStepToPreFlowRequest("Quota-" + name)
+		g.apiProxy.AddPolicy("Quota-" + name)
+	}
+
+	// gRPC clients call POST /package.Service/Method
+	prefix := strings.TrimSuffix(basePath, "/")
+	for _, service := range services {
+		for _, method := range service.Methods {
+			flowName := service.Name + "." + method
+			if g.proxyEndpoint.FlowExists(flowName) {
+				return fmt.Errorf("method %s is defined more than once", flowName)
+			}
+			keyPath := "/" + service.Name + "/" + method
+			if !strings.HasPrefix(keyPath, prefix+"/") {
+				return fmt.Errorf("method %s is not under the base path %s", keyPath, basePath)
+			}
+			keyPath = strings.TrimPrefix(keyPath, prefix)
+			g.proxyEndpoint.AddFlow(flowName, keyPath, "post", "")
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProto(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []GrpcService
+		wantErr string
+	}{
+		{
+			name: "single service with package",
+			content: `syntax = "proto3";
+package orders.v1;
+service OrderService {
+  rpc GetOrder(GetOrderRequest) returns (Order);
+  rpc ListOrders (ListOrdersRequest) returns (stream Order) {}
+}`,
+			want: []GrpcService{{Name: "orders.v1.OrderService", Methods: []string{"GetOrder", "ListOrders"}}},
+		},
+		{
+			name: "no package",
+			content: `service Echo {
+  rpc Say(Msg) returns (Msg);
+}`,
+			want: []GrpcService{{Name: "Echo", Methods: []string{"Say"}}},
+		},
+		{
+			name: "comments with braces and rpc keywords",
+			content: `package demo;
+// service Hidden { rpc Nope(A) returns (B); }
+/* a block comment with a brace }
+   and rpc Ghost(A) returns (B); */
+service Visible {
+  // rpc Commented(A) returns (B);
+  rpc Shown(A) returns (B); // trailing } brace
+}`,
+			want: []GrpcService{{Name: "demo.Visible", Methods: []string{"Shown"}}},
+		},
+		{
+			name: "string literals with braces",
+			content: `package demo;
+service Api {
+  rpc Get(A) returns (B) {
+    option (google.api.http) = { get: "/v1/{name=items/*}" };
+  }
+  rpc Put(A) returns (B) {
+    option (google.api.http) = { put: "/v1/}" body: "*" };
+  }
+}`,
+			want: []GrpcService{{Name: "demo.Api", Methods: []string{"Get", "Put"}}},
+		},
+		{
+			name: "multiple services",
+			content: `package multi;
+service First { rpc One(A) returns (B); }
+message A { string rpc_name = 1; }
+service Second { rpc Two(A) returns (B); rpc Three(A) returns (B); }`,
+			want: []GrpcService{
+				{Name: "multi.First", Methods: []string{"One"}},
+				{Name: "multi.Second", Methods: []string{"Two", "Three"}},
+			},
+		},
+		{
+			name:    "missing closing brace",
+			content: `service Broken { rpc One(A) returns (B);`,
+			wantErr: "missing closing brace",
+		},
+		{
+			name: "no services",
+			content: `package empty;
+// service Commented { rpc One(A) returns (B); }
+message A {}`,
+			wantErr: "no services found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProto(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseProto() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseProto() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProto() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,26 +1,25 @@
 package bundlegen
 
 import (
-	apiproxy "internal/bundlegen/apiproxydef"
 	"internal/bundlegen/proxies"
 )
 
-func GenerateIntegrationAPIProxy(name string,
+func (g *Generator) GenerateIntegrationAPIProxy(name string,
 	integration string,
 	apitrigger string,
 ) (err error) {
-	apiproxy.SetDisplayName(name)
-	apiproxy.SetCreatedAt()
-	apiproxy.SetLastModifiedAt()
-	apiproxy.SetConfigurationVersion()
-	apiproxy.AddProxyEndpoint("default")
-	apiproxy.AddIntegrationEndpoint("default")
-	apiproxy.SetBasePath("/" + apitrigger)
+	g.apiProxy.SetDisplayName(name)
+	g.apiProxy.SetCreatedAt()
+	g.apiProxy.SetLastModifiedAt()
+	g.apiProxy.SetConfigurationVersion()
+	g.apiProxy.AddProxyEndpoint("default")
+	g.apiProxy.AddIntegrationEndpoint("default")
+	g.apiProxy.SetBasePath("/" + apitrigger)
 
-	proxies.NewProxyEndpoint("/"+apitrigger, false)
+	g.proxyEndpoint = proxies.NewProxyEndpoint("/"+apitrigger, false)
 
-	proxies.AddStepToPreFlowRequest("set-integration-request")
-	apiproxy.AddPolicy("set-integration-request")
+	g.proxyEndpoint.AddStepToPreFlowRequest("set-integration-request")
+	g.apiProxy.AddPolicy("set-integration-request")
 
 	return nil
 }
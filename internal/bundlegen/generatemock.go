@@ -0,0 +1,246 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"internal/bundlegen/policies"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxSampleDepth limits the depth of samples derived from recursive schemas
+const maxSampleDepth = 8
+
+// mockResponseDef is the response returned by a mock flow
+type mockResponseDef struct {
+	StatusCode  string
+	Headers     map[string]string
+	ContentType string
+	Payload     string
+}
+
+// SetMock generates an API proxy without a target, where every flow responds
+// with the example response of the operation
+func (g *Generator) SetMock(mock bool) {
+	g.mock = mock
+}
+
+// GetMockPolicies returns the policies returning the mock responses
+func (g *Generator) GetMockPolicies() map[string]string {
+	return g.mockPolicyContent
+}
+
+// GetMockScripts returns the jsc resources of the mock response policies
+func (g *Generator) GetMockScripts() map[string]string {
+	return g.mockScriptContent
+}
+
+// addMockResponse adds the mock response of an operation to its flow
+func (g *Generator) addMockResponse(operation *openapi3.Operation, operationID string) error {
+	if operation == nil {
+		return nil
+	}
+	mockResponse, err := getMockResponse(operation.Responses)
+	if err != nil {
+		return fmt.Errorf("unable to generate the mock response of %s: %v", operationID, err)
+	}
+	policyName := "JS-Mock-" + operationID
+	g.mockPolicyContent[policyName] = policies.AddMockResponsePolicy(policyName)
+	g.mockScriptContent[policyName+".js"] = policies.AddMockResponseScript(mockResponse.StatusCode,
+		mockResponse.Headers, mockResponse.ContentType, mockResponse.Payload)
+	g.apiProxy.AddPolicy(policyName)
+	g.apiProxy.AddResource(policyName+".js", "jsc")
+	return g.proxyEndpoint.AddStepToFlowResponse(policyName, operationID)
+}
+
+// getMockResponse returns the first success response, or the default
+// response, with its example or a sample derived from its schema
+func getMockResponse(responses openapi3.Responses) (mockResponse mockResponseDef, err error) {
+	mockResponse = mockResponseDef{StatusCode: "200", Headers: map[string]string{}}
+
+	var response *openapi3.Response
+	for _, code := range sortedKeys(responses) {
+		if strings.HasPrefix(code, "2") && responses[code].Value != nil {
+			mockResponse.StatusCode = strings.ReplaceAll(strings.ToUpper(code), "XX", "00")
+			response = responses[code].Value
+			break
+		}
+	}
+	if response == nil {
+		if responseRef := responses.Default(); responseRef != nil && responseRef.Value != nil {
+			response = responseRef.Value
+		} else {
+			return mockResponse, nil
+		}
+	}
+
+	for _, headerName := range sortedKeys(response.Headers) {
+		header := response.Headers[headerName].Value
+		if header == nil {
+			continue
+		}
+		value := exampleValue(header.Example, header.Examples, header.Schema)
+		if value == nil {
+			continue
+		}
+		mockResponse.Headers[headerName] = fmt.Sprintf("%v", value)
+	}
+
+	contentType, mediaType := getMockMediaType(response.Content)
+	if mediaType == nil {
+		return mockResponse, nil
+	}
+	mockResponse.ContentType = contentType
+
+	example := exampleValue(mediaType.Example, mediaType.Examples, mediaType.Schema)
+	if example == nil {
+		return mockResponse, nil
+	}
+	if s, ok := example.(string); ok && !strings.Contains(contentType, "json") {
+		mockResponse.Payload = s
+		return mockResponse, nil
+	}
+	payload, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return mockResponse, err
+	}
+	mockResponse.Payload = string(payload)
+	return mockResponse, nil
+}
+
+// getMockMediaType prefers JSON content
+func getMockMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	contentTypes := sortedKeys(content)
+	sort.SliceStable(contentTypes, func(i, j int) bool {
+		return strings.Contains(contentTypes[i], "json") && !strings.Contains(contentTypes[j], "json")
+	})
+	for _, contentType := range contentTypes {
+		if content[contentType] != nil {
+			return contentType, content[contentType]
+		}
+	}
+	return "", nil
+}
+
+// exampleValue returns the example, the first of the named examples or a
+// sample derived from the schema
+func exampleValue(example interface{}, examples openapi3.Examples, schema *openapi3.SchemaRef) interface{} {
+	if example != nil {
+		return example
+	}
+	for _, exampleName := range sortedKeys(examples) {
+		if examples[exampleName] != nil && examples[exampleName].Value != nil && examples[exampleName].Value.Value != nil {
+			return examples[exampleName].Value.Value
+		}
+	}
+	if schema == nil {
+		return nil
+	}
+	return sampleFromSchema(schema.Value, 0)
+}
+
+// sampleFromSchema derives a sample value from a schema
+func sampleFromSchema(schema *openapi3.Schema, depth int) interface{} {
+	if schema == nil || depth > maxSampleDepth {
+		return nil
+	}
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if schema.Default != nil {
+		return schema.Default
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+
+	if len(schema.AllOf) > 0 {
+		sample := map[string]interface{}{}
+		for _, s := range schema.AllOf {
+			if m, ok := sampleFromSchema(s.Value, depth+1).(map[string]interface{}); ok {
+				for k, v := range m {
+					sample[k] = v
+				}
+			}
+		}
+		return sample
+	}
+	if len(schema.OneOf) > 0 {
+		return sampleFromSchema(schema.OneOf[0].Value, depth+1)
+	}
+	if len(schema.AnyOf) > 0 {
+		return sampleFromSchema(schema.AnyOf[0].Value, depth+1)
+	}
+
+	switch schema.Type {
+	case "array":
+		if schema.Items == nil {
+			return []interface{}{}
+		}
+		item := sampleFromSchema(schema.Items.Value, depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "string":
+		switch schema.Format {
+		case "date":
+			return "2023-01-01"
+		case "date-time":
+			return "2023-01-01T00:00:00Z"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+		case "uri", "url":
+			return "https://example.com"
+		case "byte":
+			return "ZXhhbXBsZQ=="
+		}
+		return "string"
+	case "integer":
+		if schema.Min != nil {
+			return int64(*schema.Min)
+		}
+		return 0
+	case "number":
+		if schema.Min != nil {
+			return *schema.Min
+		}
+		return 0.0
+	case "boolean":
+		return true
+	case "object", "":
+		if len(schema.Properties) == 0 {
+			if schema.Type == "" {
+				return nil
+			}
+			return map[string]interface{}{}
+		}
+		sample := map[string]interface{}{}
+		for _, propertyName := range sortedKeys(schema.Properties) {
+			if value := sampleFromSchema(schema.Properties[propertyName].Value, depth+1); value != nil {
+				sample[propertyName] = value
+			}
+		}
+		return sample
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Operation is an operation of the loaded OpenAPI document
+type Operation struct {
+	OperationID string
+	Resource    string
+	Method      string
+	Tags        []string
+	Quota       *OperationQuota
+}
+
+// OperationQuota is the quota of an operation in an API product
+type OperationQuota struct {
+	Limit    string
+	Interval string
+	TimeUnit string
+}
+
+// GetOperations returns the operations of the loaded OpenAPI document. The
+// quota of an operation is read from its x-google-quota extension, or from its
+// x-google-ratelimit extension
+func (g *Generator) GetOperations() (operations []Operation, err error) {
+	if g.doc == nil {
+		return nil, fmt.Errorf("the Open API document not loaded")
+	}
+
+	for _, keyPath := range sortedKeys(g.doc.Paths) {
+		pathItem := g.doc.Paths[keyPath]
+		pathMap, err := g.getHTTPMethod(pathItem, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, method := range sortedKeys(pathMap) {
+			pathDetail := pathMap[method]
+			operation := Operation{
+				OperationID: pathDetail.OperationID,
+				Resource:    replacePathWithWildCard(keyPath),
+				Method:      strings.ToUpper(method),
+			}
+			if op := pathItem.GetOperation(operation.Method); op != nil {
+				operation.Tags = op.Tags
+			}
+			if operation.Quota, err = getOperationQuota(pathDetail); err != nil {
+				return nil, err
+			}
+			operations = append(operations, operation)
+		}
+	}
+	return operations, nil
+}
+
+// getOperationQuota returns the literal quota of an operation; quotas set
+// from references or from the API product are not returned
+func getOperationQuota(pathDetail pathDetailDef) (*OperationQuota, error) {
+	if q := pathDetail.Quota; q.QuotaEnabled && q.QuotaAllowLiteral != "" &&
+		q.QuotaIntervalLiteral != "" && q.QuotaTimeUnitLiteral != "" {
+		return &OperationQuota{
+			Limit:    q.QuotaAllowLiteral,
+			Interval: q.QuotaIntervalLiteral,
+			TimeUnit: q.QuotaTimeUnitLiteral,
+		}, nil
+	}
+
+	if s := pathDetail.SpikeArrest; s.SpikeArrestEnabled && s.SpikeArrestRateLiteral != "" {
+		matches := regexp.MustCompile(`^(\d+)(ps|pm)$`).FindStringSubmatch(s.SpikeArrestRateLiteral)
+		if matches == nil {
+			return nil, fmt.Errorf("invalid rate-literal %s in x-google-ratelimit", s.SpikeArrestRateLiteral)
+		}
+		// API product quotas have no second time unit, a rate per second is
+		// converted to a rate per minute
+		limit := matches[1]
+		if matches[2] == "ps" {
+			rate, err := strconv.Atoi(limit)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rate-literal %s in x-google-ratelimit", s.SpikeArrestRateLiteral)
+			}
+			limit = strconv.Itoa(rate * 60)
+		}
+		return &OperationQuota{Limit: limit, Interval: "1", TimeUnit: "minute"}, nil
+	}
+	return nil, nil
+}
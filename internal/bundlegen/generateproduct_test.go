@@ -0,0 +1,137 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const productSpec = `openapi: 3.0.0
+info:
+  title: products
+  version: "1"
+servers:
+  - url: https://example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      x-google-quota:
+        - name: list
+          interval-literal: 2
+          timeunit-literal: hour
+          allow-literal: 100
+      responses:
+        "200":
+          description: ok
+    post:
+      operationId: addPet
+      tags: [pets, admin]
+      x-google-ratelimit:
+        - name: add
+          rate-literal: 10ps
+          identifier-ref: request.header.x-api-key
+      responses:
+        "200":
+          description: ok
+  /pets/{id}:
+    get:
+      operationId: getPet
+      x-google-ratelimit:
+        - name: get
+          rate-literal: 30pm
+          identifier-ref: request.header.x-api-key
+      responses:
+        "200":
+          description: ok
+    delete:
+      operationId: deletePet
+      x-google-quota:
+        - name: delete
+          interval-ref: request.header.interval
+          timeunit-ref: request.header.timeunit
+          allow-ref: request.header.allow
+      responses:
+        "200":
+          description: ok
+`
+
+func TestGetOperations(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Operation
+		wantErr string
+	}{
+		{
+			name: "tags and quotas",
+			spec: productSpec,
+			want: []Operation{
+				{
+					OperationID: "listPets", Resource: "/pets", Method: "GET", Tags: []string{"pets"},
+					Quota: &OperationQuota{Limit: "100", Interval: "2", TimeUnit: "hour"},
+				},
+				{
+					OperationID: "addPet", Resource: "/pets", Method: "POST", Tags: []string{"pets", "admin"},
+					Quota: &OperationQuota{Limit: "600", Interval: "1", TimeUnit: "minute"},
+				},
+				{
+					OperationID: "deletePet", Resource: "/pets/*", Method: "DELETE",
+				},
+				{
+					OperationID: "getPet", Resource: "/pets/*", Method: "GET",
+					Quota: &OperationQuota{Limit: "30", Interval: "1", TimeUnit: "minute"},
+				},
+			},
+		},
+		{
+			name:    "invalid rate",
+			spec:    strings.Replace(productSpec, "rate-literal: 10ps", "rate-literal: 10px", 1),
+			wantErr: "invalid rate-literal 10px",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specFile := filepath.Join(t.TempDir(), "spec.yaml")
+			if err := os.WriteFile(specFile, []byte(tt.spec), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			g := NewGenerator()
+			if _, _, err := g.LoadDocumentFromFile(specFile, false, false); err != nil {
+				t.Fatalf("LoadDocumentFromFile() error = %v", err)
+			}
+
+			got, err := g.GetOperations()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetOperations() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetOperations() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOperations() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiproxy "internal/bundlegen/apiproxydef"
+	proxytypes "internal/bundlegen/common"
+	"internal/bundlegen/proxies"
+	"internal/bundlegen/targets"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// specPolicyDef holds the parts of the VerifyAPIKey and OAuthV2 policies
+// needed to describe the security schemes of a proxy
+type specPolicyDef struct {
+	XMLName xml.Name
+	Name    string `xml:"name,attr"`
+	Enabled string `xml:"enabled,attr"`
+	APIKey  struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"APIKey"`
+	Operation string `xml:"Operation"`
+	Scope     string `xml:"Scope"`
+}
+
+var (
+	pathSuffixCondition = regexp.MustCompile(
+		`proxy\.pathsuffix\s+(MatchesPath|~/|Matches|~|Equals|==|=|Is)\s+"([^"]*)"`)
+	verbCondition = regexp.MustCompile(
+		`request\.verb\s+(?:Equals|==|=|Is)\s+"([A-Za-z]+)"`)
+	conditionEscaper = strings.NewReplacer("&quot;", `"`, "&apos;", "'",
+		"&lt;", "<", "&gt;", ">", "&amp;", "&")
+)
+
+// GenerateSpecFromBundle reverse engineers a skeleton OpenAPI 3 document from
+// an API proxy bundle. bundleDir is the apiproxy folder or the folder
+// containing it. Flows matching on proxy.pathsuffix and request.verb become
+// operations, VerifyAPIKey and OAuthV2 steps become security schemes, and the
+// base path and target URLs become servers.
+func GenerateSpecFromBundle(bundleDir string) (doc *openapi3.T, err error) {
+	if filepath.Base(bundleDir) != "apiproxy" {
+		bundleDir = filepath.Join(bundleDir, "apiproxy")
+	}
+
+	apiProxy, err := readSpecDescriptor(bundleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	doc = &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       apiProxy.DisplayName,
+			Description: apiProxy.Description,
+			Version:     "1.0.0",
+		},
+		Paths: openapi3.Paths{},
+		Components: &openapi3.Components{
+			SecuritySchemes: openapi3.SecuritySchemes{},
+		},
+	}
+	if doc.Info.Title == "" {
+		doc.Info.Title = apiProxy.Name
+	}
+	if apiProxy.Revision != "" {
+		doc.Info.Version = apiProxy.Revision
+	}
+
+	schemes, err := readSpecSecuritySchemes(bundleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyEndpoints, err := readSpecEndpoints[proxies.ProxyEndpointDef](bundleDir, "proxies")
+	if err != nil {
+		return nil, err
+	}
+
+	basePaths := map[string]bool{}
+	for _, proxyEndpoint := range proxyEndpoints {
+		basePaths[normalizeBasePath(proxyEndpoint.HTTPProxyConnection.BasePath)] = true
+	}
+
+	// with a single base path, it is the server and the paths are relative to it
+	prefixPaths := len(basePaths) > 1
+	if !prefixPaths {
+		for basePath := range basePaths {
+			doc.Servers = append(doc.Servers, &openapi3.Server{
+				URL:         basePath,
+				Description: "API proxy",
+			})
+		}
+	} else {
+		doc.Servers = append(doc.Servers, &openapi3.Server{URL: "/", Description: "API proxy"})
+	}
+
+	operationIDs := map[string]bool{}
+	for _, proxyEndpoint := range proxyEndpoints {
+		prefix := ""
+		if prefixPaths {
+			prefix = strings.TrimSuffix(normalizeBasePath(proxyEndpoint.HTTPProxyConnection.BasePath), "/")
+		}
+		security := securityFromSteps(proxyEndpoint.PreFlow.Request.Step, schemes, doc)
+		for _, flow := range proxyEndpoint.Flows.Flow {
+			addSpecOperations(doc, flow, prefix, security, schemes, operationIDs)
+		}
+	}
+
+	targetEndpoints, err := readSpecEndpoints[targets.TargetEndpointDef](bundleDir, "targets")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, targetEndpoint := range targetEndpoints {
+		if targetEndpoint.HTTPTargetConnection.URL == "" {
+			continue
+		}
+		doc.Servers = append(doc.Servers, &openapi3.Server{
+			URL:         targetEndpoint.HTTPTargetConnection.URL,
+			Description: "Target endpoint " + targetEndpoint.Name,
+		})
+	}
+
+	if err = doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("the generated document is not valid: %v", err)
+	}
+	return doc, nil
+}
+
+func readSpecDescriptor(bundleDir string) (apiProxy *apiproxy.APIProxyDef, err error) {
+	files, err := filepath.Glob(filepath.Join(bundleDir, "*.xml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) != 1 {
+		return nil, fmt.Errorf("expected one API proxy descriptor in %s, found %d", bundleDir, len(files))
+	}
+	apiProxy = &apiproxy.APIProxyDef{}
+	return apiProxy, readSpecXML(files[0], apiProxy)
+}
+
+// readSpecSecuritySchemes maps the VerifyAPIKey and OAuthV2 policies of the
+// bundle to security schemes named after the policies
+func readSpecSecuritySchemes(bundleDir string) (schemes map[string]*openapi3.SecurityScheme, err error) {
+	schemes = map[string]*openapi3.SecurityScheme{}
+
+	files, err := filepath.Glob(filepath.Join(bundleDir, "policies", "*.xml"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		policy := specPolicyDef{}
+		if err = readSpecXML(file, &policy); err != nil {
+			return nil, err
+		}
+		if policy.Enabled == "false" {
+			continue
+		}
+
+		switch policy.XMLName.Local {
+		case "VerifyAPIKey":
+			in, keyName := "query", "apikey"
+			switch ref := policy.APIKey.Ref; {
+			case strings.HasPrefix(ref, "request.queryparam."):
+				keyName = strings.TrimPrefix(ref, "request.queryparam.")
+			case strings.HasPrefix(ref, "request.header."):
+				in, keyName = "header", strings.TrimPrefix(ref, "request.header.")
+			}
+			schemes[policy.Name] = &openapi3.SecurityScheme{
+				Type: "apiKey",
+				In:   in,
+				Name: keyName,
+			}
+		case "OAuthV2":
+			if policy.Operation != "VerifyAccessToken" {
+				continue
+			}
+			scopes := map[string]string{}
+			for _, scope := range strings.Fields(policy.Scope) {
+				scopes[scope] = scope
+			}
+			schemes[policy.Name] = &openapi3.SecurityScheme{
+				Type:        "oauth2",
+				Description: "The token URL is a placeholder, set it to the token endpoint of the API",
+				Flows: &openapi3.OAuthFlows{
+					ClientCredentials: &openapi3.OAuthFlow{
+						TokenURL: "/token",
+						Scopes:   scopes,
+					},
+				},
+			}
+		}
+	}
+	return schemes, nil
+}
+
+// securityFromSteps returns the security requirements of the unconditional
+// steps referring to a security scheme and adds those schemes to the document
+func securityFromSteps(steps []*proxytypes.StepDef, schemes map[string]*openapi3.SecurityScheme,
+	doc *openapi3.T,
+) (security openapi3.SecurityRequirements) {
+	for _, step := range steps {
+		scheme, ok := schemes[step.Name]
+		if !ok || step.Condition != "" {
+			continue
+		}
+		doc.Components.SecuritySchemes[step.Name] = &openapi3.SecuritySchemeRef{Value: scheme}
+
+		requirement := openapi3.SecurityRequirement{step.Name: []string{}}
+		if scheme.Type == "oauth2" {
+			requirement[step.Name] = sortedKeys(scheme.Flows.ClientCredentials.Scopes)
+		}
+		security = append(security, requirement)
+	}
+	return security
+}
+
+func addSpecOperations(doc *openapi3.T, flow proxytypes.FlowDef, prefix string,
+	security openapi3.SecurityRequirements, schemes map[string]*openapi3.SecurityScheme,
+	operationIDs map[string]bool,
+) {
+	condition := conditionEscaper.Replace(flow.Condition.ConditionData)
+
+	var pathSuffix string
+	if match := pathSuffixCondition.FindStringSubmatch(condition); match != nil {
+		pathSuffix = match[2]
+	}
+
+	var verbs []string
+	for _, match := range verbCondition.FindAllStringSubmatch(condition, -1) {
+		verb := strings.ToUpper(match[1])
+		if !contains(verbs, verb) {
+			verbs = append(verbs, verb)
+		}
+	}
+
+	if pathSuffix == "" && len(verbs) == 0 {
+		return
+	}
+
+	description := flow.Description
+	if len(verbs) == 0 {
+		// the flow matches any verb
+		verbs = []string{"GET"}
+		description = strings.TrimSpace(description + " The flow condition does not restrict the verb.")
+	}
+
+	specPath, params := specPathFromSuffix(prefix + pathSuffix)
+
+	pathItem := doc.Paths[specPath]
+	if pathItem == nil {
+		pathItem = &openapi3.PathItem{}
+		doc.Paths[specPath] = pathItem
+	}
+
+	flowSecurity := append(openapi3.SecurityRequirements{}, security...)
+	flowSecurity = append(flowSecurity, securityFromSteps(flow.Request.Step, schemes, doc)...)
+
+	for _, verb := range verbs {
+		// flows are evaluated in order, an earlier flow wins
+		if pathItem.GetOperation(verb) != nil {
+			continue
+		}
+
+		operationID := flow.Name
+		if len(verbs) > 1 {
+			operationID += "-" + strings.ToLower(verb)
+		}
+		base := operationID
+		for n := 2; operationIDs[operationID]; n++ {
+			operationID = base + "-" + strconv.Itoa(n)
+		}
+		operationIDs[operationID] = true
+
+		operation := &openapi3.Operation{
+			OperationID: operationID,
+			Summary:     flow.Name,
+			Description: description,
+			Responses: openapi3.Responses{
+				"default": &openapi3.ResponseRef{
+					Value: openapi3.NewResponse().WithDescription("Default response"),
+				},
+			},
+		}
+		for _, param := range params {
+			operation.AddParameter(openapi3.NewPathParameter(param).
+				WithSchema(openapi3.NewStringSchema()))
+		}
+		if len(flowSecurity) > 0 {
+			operation.Security = &flowSecurity
+		}
+		pathItem.SetOperation(verb, operation)
+	}
+}
+
+// specPathFromSuffix turns a proxy.pathsuffix pattern into an OpenAPI path,
+// wildcard segments become path parameters
+func specPathFromSuffix(pathSuffix string) (specPath string, params []string) {
+	segments := strings.Split(strings.TrimPrefix(pathSuffix, "/"), "/")
+	for i, segment := range segments {
+		if strings.Contains(segment, "*") {
+			param := "param" + strconv.Itoa(len(params)+1)
+			if segment == "**" {
+				param = "path"
+			}
+			params = append(params, param)
+			segments[i] = "{" + param + "}"
+		}
+	}
+	return "/" + strings.Join(segments, "/"), params
+}
+
+func normalizeBasePath(basePath string) string {
+	if !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+	return basePath
+}
+
+// readSpecEndpoints reads the endpoints of a bundle folder sorted by file name
+func readSpecEndpoints[T any](bundleDir string, folder string) (endpoints []*T, err error) {
+	files, err := filepath.Glob(filepath.Join(bundleDir, folder, "*.xml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		endpoint := new(T)
+		if err = readSpecXML(file, endpoint); err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+func readSpecXML(filePath string, v interface{}) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	if err = xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %v", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen_test
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	bundle "internal/bundlegen"
+	"internal/bundlegen/proxybundle"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestGenerateSpecFromBundle(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		skipPolicy  bool
+		targetURL   string
+		wantTarget  string
+		wantSchemes int
+	}{
+		{
+			name:        "petstore yaml",
+			spec:        "petstore.yaml",
+			wantTarget:  "https://petstore.swagger.io/v2",
+			wantSchemes: 2,
+		},
+		{
+			name:        "petstore json without policies",
+			spec:        "petstore.json",
+			skipPolicy:  true,
+			wantTarget:  "https://petstore.swagger.io/v2",
+			wantSchemes: 2,
+		},
+		{
+			name:       "petstore without security",
+			spec:       "petstore-no-sec.yaml",
+			targetURL:  "https://example.com/v2",
+			wantTarget: "https://example.com/v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specFile := filepath.Join("..", "..", "test", tt.spec)
+			outputDir := t.TempDir()
+
+			g := bundle.NewGenerator()
+			oasDocName, content, err := g.LoadDocumentFromFile(specFile, false, false)
+			if err != nil {
+				t.Fatalf("LoadDocumentFromFile() error = %v", err)
+			}
+			if err = g.GenerateAPIProxyDefFromOAS("petstore", oasDocName, tt.skipPolicy,
+				false, "", "", "", "", tt.targetURL); err != nil {
+				t.Fatalf("GenerateAPIProxyDefFromOAS() error = %v", err)
+			}
+			if err = proxybundle.GenerateAPIProxyBundleFromOAS(g, "petstore", string(content), oasDocName,
+				tt.skipPolicy, false, "", "", "", "", tt.targetURL, outputDir); err != nil {
+				t.Fatalf("GenerateAPIProxyBundleFromOAS() error = %v", err)
+			}
+
+			doc, err := bundle.GenerateSpecFromBundle(outputDir)
+			if err != nil {
+				t.Fatalf("GenerateSpecFromBundle() error = %v", err)
+			}
+
+			want, err := openapi3.NewLoader().LoadFromFile(specFile)
+			if err != nil {
+				t.Fatalf("failed to load the spec: %v", err)
+			}
+			if len(doc.Servers) != 2 || doc.Servers[0].URL != "/v2" || doc.Servers[1].URL != tt.wantTarget {
+				t.Errorf("servers = %v, want /v2 and %s", serverURLs(doc), tt.wantTarget)
+			}
+			if got := len(doc.Components.SecuritySchemes); got != tt.wantSchemes {
+				t.Errorf("security schemes = %d, want %d", got, tt.wantSchemes)
+			}
+			if got, wantOps := operations(doc), operations(want); strings.Join(got, "\n") != strings.Join(wantOps, "\n") {
+				t.Errorf("operations = %v, want %v", got, wantOps)
+			}
+		})
+	}
+}
+
+// pathParams matches path parameters, their names are not kept in the flow
+// conditions of the bundle
+var pathParams = regexp.MustCompile(`\{[^}]*\}`)
+
+// operations returns the sorted method, path and operation id of each
+// operation of doc
+func operations(doc *openapi3.T) (ops []string) {
+	for path, pathItem := range doc.Paths {
+		path = pathParams.ReplaceAllString(path, "{}")
+		for method, operation := range pathItem.Operations() {
+			ops = append(ops, method+" "+path+" "+operation.OperationID)
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+func serverURLs(doc *openapi3.T) (urls []string) {
+	for _, server := range doc.Servers {
+		urls = append(urls, server.URL)
+	}
+	return urls
+}
@@ -29,10 +29,8 @@ import (
 
 	"internal/clilog"
 
-	apiproxy "internal/bundlegen/apiproxydef"
 	"internal/bundlegen/policies"
 	"internal/bundlegen/proxies"
-	"internal/bundlegen/targets"
 
 	"github.com/apigee/apigeecli/cmd/utils"
 	"github.com/getkin/kin-openapi/openapi2"
@@ -67,24 +65,12 @@ type ValueDef struct {
 	Standard string
 }
 
-var doc2 *openapi2.T
-
 const (
 	NoAuthTargetName     = "default"
 	GoogleAuthTargetName = "google-auth"
 )
 
-var amPolicyContent = make(map[string]string)
-
-var allowValue, apiName string
-
-var (
-	googMgmt       googleManagementDef
-	quotaList      []quotaDef
-	defaultBackend backendDef
-)
-
-func LoadSwaggerFromUri(endpoint string) (string, []byte, error) {
+func (g *Generator) LoadSwaggerFromUri(endpoint string) (string, []byte, error) {
 	var docType string
 
 	u, err := url.Parse(endpoint)
@@ -106,10 +92,10 @@ func LoadSwaggerFromUri(endpoint string) (string, []byte, error) {
 		return "", nil, err
 	}
 	defer os.Remove(name)
-	return LoadSwaggerFromFile(name)
+	return g.LoadSwaggerFromFile(name)
 }
 
-func LoadSwaggerFromFile(filePath string) (string, []byte, error) {
+func (g *Generator) LoadSwaggerFromFile(filePath string) (string, []byte, error) {
 	var err error
 	var jsonContent, swaggerBytes, swaggerJsonBytes []byte
 
@@ -127,12 +113,12 @@ func LoadSwaggerFromFile(filePath string) (string, []byte, error) {
 		swaggerBytes = swaggerJsonBytes
 	}
 
-	if err = json.Unmarshal(swaggerBytes, &doc2); err != nil {
+	if err = json.Unmarshal(swaggerBytes, &g.doc2); err != nil {
 		clilog.Error.Println(err)
 		return "", nil, err
 	}
 
-	if jsonContent, err = doc2.MarshalJSON(); err != nil {
+	if jsonContent, err = g.doc2.MarshalJSON(); err != nil {
 		clilog.Error.Println(err)
 		return "", nil, err
 	}
@@ -142,7 +128,7 @@ func LoadSwaggerFromFile(filePath string) (string, []byte, error) {
 	return filepath.Base(filePath), jsonContent, err
 }
 
-func GenerateAPIProxyFromSwagger(name string,
+func (g *Generator) GenerateAPIProxyFromSwagger(name string,
 	oasDocName string,
 	basePath string,
 	addCORS bool,
@@ -150,86 +136,86 @@ func GenerateAPIProxyFromSwagger(name string,
 	var err error
 
 	// load the security definitions
-	loadSwaggerSecurityRequirements(doc2.SecurityDefinitions)
+	g.loadSwaggerSecurityRequirements(g.doc2.SecurityDefinitions)
 
 	// load google extensions
-	err = loadGoogleExtensions()
+	err = g.loadGoogleExtensions()
 	if err != nil {
 		clilog.Error.Println(err)
 		return name, err
 	}
 
 	if name != "" {
-		apiproxy.SetDisplayName(name)
+		g.apiProxy.SetDisplayName(name)
 		// set the name for use when generating the bundle
-		apiName = name
-	} else if apiName != "" {
-		apiproxy.SetDisplayName(apiName)
+		g.apiName = name
+	} else if g.apiName != "" {
+		g.apiProxy.SetDisplayName(g.apiName)
 	} else {
 		return name, fmt.Errorf("neither x-google-api-name nor name was set")
 	}
 
-	if doc2.Info.Description != "" {
-		apiproxy.SetDescription(doc2.Info.Description)
+	if g.doc2.Info.Description != "" {
+		g.apiProxy.SetDescription(g.doc2.Info.Description)
 	}
 
-	apiproxy.SetCreatedAt()
-	apiproxy.SetLastModifiedAt()
-	apiproxy.SetConfigurationVersion()
-	apiproxy.AddProxyEndpoint("default")
+	g.apiProxy.SetCreatedAt()
+	g.apiProxy.SetLastModifiedAt()
+	g.apiProxy.SetConfigurationVersion()
+	g.apiProxy.AddProxyEndpoint("default")
 
-	if doc2.BasePath == "" {
+	if g.doc2.BasePath == "" {
 		return name, fmt.Errorf("basePath is missing from the Swagger file. Please add a basePath")
 	}
 
-	apiproxy.SetBasePath(doc2.BasePath)
-	proxies.NewProxyEndpoint(doc2.BasePath, true)
+	g.apiProxy.SetBasePath(g.doc2.BasePath)
+	g.proxyEndpoint = proxies.NewProxyEndpoint(g.doc2.BasePath, true)
 
 	// add global security policies
-	if securityScheme := getSwaggerSecurityRequirements(doc2.Security); securityScheme.SchemeName != "" {
+	if securityScheme := g.getSwaggerSecurityRequirements(g.doc2.Security); securityScheme.SchemeName != "" {
 		if securityScheme.APIKeyPolicy.APIKeyPolicyEnabled {
-			proxies.AddStepToPreFlowRequest("Verify-API-Key-" + securityScheme.SchemeName)
-			enableSecurityPolicy(securityScheme.SchemeName, "apikey")
+			g.proxyEndpoint.AddStepToPreFlowRequest("Verify-API-Key-" + securityScheme.SchemeName)
+			g.enableSecurityPolicy(securityScheme.SchemeName, "apikey")
 		} else if securityScheme.JWTPolicy.JWTPolicyEnabled {
-			proxies.AddStepToPreFlowRequest("VerifyJWT-" + securityScheme.SchemeName)
-			enableSecurityPolicy(securityScheme.SchemeName, "jwt")
+			g.proxyEndpoint.AddStepToPreFlowRequest("VerifyJWT-" + securityScheme.SchemeName)
+			g.enableSecurityPolicy(securityScheme.SchemeName, "jwt")
 		}
 	}
 
-	if err = generateSwaggerFlows(doc2.Paths); err != nil {
+	if err = g.generateSwaggerFlows(g.doc2.Paths); err != nil {
 		clilog.Error.Println(err)
 		return name, err
 	}
 
 	// handle unhandled requests
-	if allowValue == "configured" {
-		proxies.AddFlow("Unknown Request", "", "", "Handle unknown requests")
-		proxies.AddStepToFlowRequest("Raise-Fault-Unknown-Request", "Unknown Request")
-		apiproxy.AddPolicy("Raise-Fault-Unknown-Request")
+	if g.allowValue == "configured" {
+		g.proxyEndpoint.AddFlow("Unknown Request", "", "", "Handle unknown requests")
+		g.proxyEndpoint.AddStepToFlowRequest("Raise-Fault-Unknown-Request", "Unknown Request")
+		g.apiProxy.AddPolicy("Raise-Fault-Unknown-Request")
 	}
 
-	if defaultBackend.Address != "" { // there is a default address
-		if err = addBackend(defaultBackend); err != nil {
+	if g.defaultBackend.Address != "" { // there is a default address
+		if err = g.addBackend(g.defaultBackend); err != nil {
 			return name, err
 		}
-		if defaultBackend.JwtAudience != "" {
-			proxies.AddStepToPreFlowRequest("Copy-Auth-Var")
-			apiproxy.AddPolicy("Copy-Auth-Var")
-			policies.EnableCopyAuthPolicy()
+		if g.defaultBackend.JwtAudience != "" {
+			g.proxyEndpoint.AddStepToPreFlowRequest("Copy-Auth-Var")
+			g.apiProxy.AddPolicy("Copy-Auth-Var")
+			g.copyAuth = true
 		}
 	}
 
-	for _, securityScheme := range securitySchemesList.SecuritySchemes {
+	for _, securityScheme := range g.securitySchemesList.SecuritySchemes {
 		if securityScheme.JWTPolicy.JWTPolicyEnabled {
-			apiproxy.AddPolicy("VerifyJWT-" + securityScheme.SchemeName)
+			g.apiProxy.AddPolicy("VerifyJWT-" + securityScheme.SchemeName)
 		} else if securityScheme.APIKeyPolicy.APIKeyPolicyEnabled {
-			apiproxy.AddPolicy("Verify-API-Key-" + securityScheme.SchemeName)
+			g.apiProxy.AddPolicy("Verify-API-Key-" + securityScheme.SchemeName)
 		}
 	}
 
 	if addCORS {
-		proxies.AddStepToPreFlowRequest("Add-CORS")
-		apiproxy.AddPolicy("Add-CORS")
+		g.proxyEndpoint.AddStepToPreFlowRequest("Add-CORS")
+		g.apiProxy.AddPolicy("Add-CORS")
 	}
 
 	return name, nil
@@ -282,48 +268,48 @@ func loadSecurityDefinition(secDefName string, securityScheme openapi2.SecurityS
 	return secScheme
 }
 
-func loadGoogleExtensions() (err error) {
-	for extensionName, extensionValue := range doc2.Extensions {
+func (g *Generator) loadGoogleExtensions() (err error) {
+	for extensionName, extensionValue := range g.doc2.Extensions {
 		clilog.Debug.Printf("Found extension: %s", extensionName)
 		if extensionName == "x-google-management" {
-			if err := parseManagementExtension(extensionValue); err != nil {
+			if err := g.parseManagementExtension(extensionValue); err != nil {
 				return err
 			}
 		} else if extensionName == "x-google-allow" {
-			allowValue := strings.ReplaceAll(fmt.Sprintf("%s", extensionValue), "\"", "")
-			clilog.Debug.Printf("Allow Value: %s\n", allowValue)
-			if allowValue != "configured" && allowValue != "all" {
-				return fmt.Errorf("invalid value for x-google-allow: %s", allowValue)
+			g.allowValue = strings.ReplaceAll(fmt.Sprintf("%s", extensionValue), "\"", "")
+			clilog.Debug.Printf("Allow Value: %s\n", g.allowValue)
+			if g.allowValue != "configured" && g.allowValue != "all" {
+				return fmt.Errorf("invalid value for x-google-allow: %s", g.allowValue)
 			}
 		} else if extensionName == "x-google-api-name" {
 			clilog.Debug.Printf("Found API Name: %s\n", extensionValue)
-			if apiName, err = parseApiExtension(extensionValue); err != nil {
+			if g.apiName, err = parseApiExtension(extensionValue); err != nil {
 				return err
 			}
 		} else if extensionName == "x-google-backend" {
-			if defaultBackend, err = parseBackendExtension(extensionValue, false); err != nil {
+			if g.defaultBackend, err = parseBackendExtension(extensionValue, false); err != nil {
 				return err
 			}
-			clilog.Debug.Printf("Found default backend: %v", defaultBackend)
+			clilog.Debug.Printf("Found default backend: %v", g.defaultBackend)
 		}
 	}
 	return nil
 }
 
-func enableSecurityPolicy(name string, policyType string) {
-	for index, securityScheme := range securitySchemesList.SecuritySchemes {
+func (g *Generator) enableSecurityPolicy(name string, policyType string) {
+	for index, securityScheme := range g.securitySchemesList.SecuritySchemes {
 		if securityScheme.SchemeName == name {
 			if policyType == "jwt" {
-				securitySchemesList.SecuritySchemes[index].JWTPolicy.JWTPolicyEnabled = true
+				g.securitySchemesList.SecuritySchemes[index].JWTPolicy.JWTPolicyEnabled = true
 			} else if policyType == "apikey" {
-				securitySchemesList.SecuritySchemes[index].APIKeyPolicy.APIKeyPolicyEnabled = true
+				g.securitySchemesList.SecuritySchemes[index].APIKeyPolicy.APIKeyPolicyEnabled = true
 			}
 		}
 	}
 }
 
-func getSecurityType(secName string) securitySchemesDef {
-	for _, securityScheme := range securitySchemesList.SecuritySchemes {
+func (g *Generator) getSecurityType(secName string) securitySchemesDef {
+	for _, securityScheme := range g.securitySchemesList.SecuritySchemes {
 		if securityScheme.SchemeName == secName {
 			return securityScheme
 		}
@@ -331,23 +317,23 @@ func getSecurityType(secName string) securitySchemesDef {
 	return securitySchemesDef{}
 }
 
-func getSwaggerSecurityRequirements(securityRequirements openapi2.SecurityRequirements) securitySchemesDef {
+func (g *Generator) getSwaggerSecurityRequirements(securityRequirements openapi2.SecurityRequirements) securitySchemesDef {
 	for _, secReq := range securityRequirements {
 		for secReqName := range secReq {
-			return getSecurityType(secReqName)
+			return g.getSecurityType(secReqName)
 		}
 	}
 	return securitySchemesDef{}
 }
 
-func loadSwaggerSecurityRequirements(securityDefinitions map[string]*openapi2.SecurityScheme) {
+func (g *Generator) loadSwaggerSecurityRequirements(securityDefinitions map[string]*openapi2.SecurityScheme) {
 	for secDefName, secDef := range securityDefinitions {
 		clilog.Debug.Printf("Loading Security Definition: %s\n", secDefName)
-		securitySchemesList.SecuritySchemes = append(securitySchemesList.SecuritySchemes, loadSecurityDefinition(secDefName, *secDef))
+		g.securitySchemesList.SecuritySchemes = append(g.securitySchemesList.SecuritySchemes, loadSecurityDefinition(secDefName, *secDef))
 	}
 }
 
-func getSwaggerHTTPMethod(pathItem openapi2.PathItem, keyPath string) (map[string]pathDetailDef, error) {
+func (g *Generator) getSwaggerHTTPMethod(pathItem openapi2.PathItem, keyPath string) (map[string]pathDetailDef, error) {
 	var err error
 	pathMap := make(map[string]pathDetailDef)
 	alternateOperationId := strings.ReplaceAll(keyPath, "\\", "_")
@@ -366,14 +352,14 @@ func getSwaggerHTTPMethod(pathItem openapi2.PathItem, keyPath string) (map[strin
 		}
 		if pathItem.Get.Security != nil {
 			securityRequirements := openapi2.SecurityRequirements(*pathItem.Get.Security)
-			getPathDetail.SecurityScheme = getSwaggerSecurityRequirements(securityRequirements)
+			getPathDetail.SecurityScheme = g.getSwaggerSecurityRequirements(securityRequirements)
 			if getPathDetail.SecurityScheme.JWTPolicy.Audience != "" {
 				getPathDetail.SecurityScheme.JWTPolicy.JWTPolicyEnabled = true
 			}
 		}
 		// check for google extensions
 		if pathItem.Get.Extensions != nil {
-			if getPathDetail, err = processPathSwaggerExtensions(pathItem.Get.Extensions, getPathDetail); err != nil {
+			if getPathDetail, err = g.processPathSwaggerExtensions(pathItem.Get.Extensions, getPathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -394,14 +380,14 @@ func getSwaggerHTTPMethod(pathItem openapi2.PathItem, keyPath string) (map[strin
 		}
 		if pathItem.Post.Security != nil {
 			securityRequirements := openapi2.SecurityRequirements(*pathItem.Post.Security)
-			postPathDetail.SecurityScheme = getSwaggerSecurityRequirements(securityRequirements)
+			postPathDetail.SecurityScheme = g.getSwaggerSecurityRequirements(securityRequirements)
 			if postPathDetail.SecurityScheme.JWTPolicy.Audience != "" {
 				postPathDetail.SecurityScheme.JWTPolicy.JWTPolicyEnabled = true
 			}
 		}
 		// check for google extensions
 		if pathItem.Post.Extensions != nil {
-			if postPathDetail, err = processPathSwaggerExtensions(pathItem.Post.Extensions, postPathDetail); err != nil {
+			if postPathDetail, err = g.processPathSwaggerExtensions(pathItem.Post.Extensions, postPathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -422,14 +408,14 @@ func getSwaggerHTTPMethod(pathItem openapi2.PathItem, keyPath string) (map[strin
 		}
 		if pathItem.Put.Security != nil {
 			securityRequirements := openapi2.SecurityRequirements(*pathItem.Put.Security)
-			putPathDetail.SecurityScheme = getSwaggerSecurityRequirements(securityRequirements)
+			putPathDetail.SecurityScheme = g.getSwaggerSecurityRequirements(securityRequirements)
 			if putPathDetail.SecurityScheme.JWTPolicy.Audience != "" {
 				putPathDetail.SecurityScheme.JWTPolicy.JWTPolicyEnabled = true
 			}
 		}
 		// check for google extensions
 		if pathItem.Put.Extensions != nil {
-			if putPathDetail, err = processPathSwaggerExtensions(pathItem.Put.Extensions, putPathDetail); err != nil {
+			if putPathDetail, err = g.processPathSwaggerExtensions(pathItem.Put.Extensions, putPathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -450,14 +436,14 @@ func getSwaggerHTTPMethod(pathItem openapi2.PathItem, keyPath string) (map[strin
 		}
 		if pathItem.Patch.Security != nil {
 			securityRequirements := openapi2.SecurityRequirements(*pathItem.Patch.Security)
-			patchPathDetail.SecurityScheme = getSwaggerSecurityRequirements(securityRequirements)
+			patchPathDetail.SecurityScheme = g.getSwaggerSecurityRequirements(securityRequirements)
 			if patchPathDetail.SecurityScheme.JWTPolicy.Audience != "" {
 				patchPathDetail.SecurityScheme.JWTPolicy.JWTPolicyEnabled = true
 			}
 		}
 		// check for google extensions
 		if pathItem.Patch.Extensions != nil {
-			if patchPathDetail, err = processPathSwaggerExtensions(pathItem.Patch.Extensions, patchPathDetail); err != nil {
+			if patchPathDetail, err = g.processPathSwaggerExtensions(pathItem.Patch.Extensions, patchPathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -478,14 +464,14 @@ func getSwaggerHTTPMethod(pathItem openapi2.PathItem, keyPath string) (map[strin
 		}
 		if pathItem.Delete.Security != nil {
 			securityRequirements := openapi2.SecurityRequirements(*pathItem.Delete.Security)
-			deletePathDetail.SecurityScheme = getSwaggerSecurityRequirements(securityRequirements)
+			deletePathDetail.SecurityScheme = g.getSwaggerSecurityRequirements(securityRequirements)
 			if deletePathDetail.SecurityScheme.JWTPolicy.Audience != "" {
 				deletePathDetail.SecurityScheme.JWTPolicy.JWTPolicyEnabled = true
 			}
 		}
 		// check for google extensions
 		if pathItem.Delete.Extensions != nil {
-			if deletePathDetail, err = processPathSwaggerExtensions(pathItem.Delete.Extensions, deletePathDetail); err != nil {
+			if deletePathDetail, err = g.processPathSwaggerExtensions(pathItem.Delete.Extensions, deletePathDetail); err != nil {
 				return nil, err
 			}
 		}
@@ -521,29 +507,30 @@ func getSwaggerHTTPMethod(pathItem openapi2.PathItem, keyPath string) (map[strin
 	return pathMap, nil
 }
 
-func generateSwaggerFlows(paths map[string]*openapi2.PathItem) (err error) {
-	for pathName, pathItem := range paths {
-		pathMap, err := getSwaggerHTTPMethod(*pathItem, pathName)
+func (g *Generator) generateSwaggerFlows(paths map[string]*openapi2.PathItem) (err error) {
+	for _, pathName := range sortedKeys(paths) {
+		pathMap, err := g.getSwaggerHTTPMethod(*paths[pathName], pathName)
 		if err != nil {
 			return err
 		}
-		for method, pathDetail := range pathMap {
-			if !proxies.FlowExists(pathDetail.OperationID) {
-				proxies.AddFlow(pathDetail.OperationID, replacePathWithWildCard(pathName), method, pathDetail.Description)
+		for _, method := range sortedKeys(pathMap) {
+			pathDetail := pathMap[method]
+			if !g.proxyEndpoint.FlowExists(pathDetail.OperationID) {
+				g.proxyEndpoint.AddFlow(pathDetail.OperationID, replacePathWithWildCard(pathName), method, pathDetail.Description)
 
 				if pathDetail.Backend != (backendDef{}) {
 					if pathDetail.Backend.JwtAudience != "" {
-						if !targets.IsExists(GoogleAuthTargetName) {
-							targets.NewTargetEndpoint(GoogleAuthTargetName, pathDetail.Backend.Address, "", pathDetail.Backend.JwtAudience, "")
+						if !g.targetEndpoints.IsExists(GoogleAuthTargetName) {
+							g.targetEndpoints.NewTargetEndpoint(GoogleAuthTargetName, pathDetail.Backend.Address, "", pathDetail.Backend.JwtAudience, "")
 						}
-						if err = targets.AddFlow(GoogleAuthTargetName, pathDetail.OperationID, replacePathWithWildCard(pathName), method, pathDetail.Description); err != nil {
+						if err = g.targetEndpoints.AddFlow(GoogleAuthTargetName, pathDetail.OperationID, replacePathWithWildCard(pathName), method, pathDetail.Description); err != nil {
 							return err
 						}
 					} else {
-						if !targets.IsExists(NoAuthTargetName) {
-							targets.NewTargetEndpoint(NoAuthTargetName, pathDetail.Backend.Address, "", "", "")
+						if !g.targetEndpoints.IsExists(NoAuthTargetName) {
+							g.targetEndpoints.NewTargetEndpoint(NoAuthTargetName, pathDetail.Backend.Address, "", "", "")
 						}
-						if err = targets.AddFlow(NoAuthTargetName, pathDetail.OperationID, replacePathWithWildCard(pathName), method, pathDetail.Description); err != nil {
+						if err = g.targetEndpoints.AddFlow(NoAuthTargetName, pathDetail.OperationID, replacePathWithWildCard(pathName), method, pathDetail.Description); err != nil {
 							return err
 						}
 					}
@@ -552,46 +539,46 @@ func generateSwaggerFlows(paths map[string]*openapi2.PathItem) (err error) {
 			if pathDetail.AssignMessage != "" {
 				if pathDetail.Backend != (backendDef{}) {
 					if pathDetail.Backend.JwtAudience != "" {
-						if err = targets.AddStepToFlowRequest(GoogleAuthTargetName, "AM-"+pathDetail.OperationID, pathDetail.OperationID); err != nil {
+						if err = g.targetEndpoints.AddStepToFlowRequest(GoogleAuthTargetName, "AM-"+pathDetail.OperationID, pathDetail.OperationID); err != nil {
 							return err
 						}
 					} else {
-						if err = targets.AddStepToFlowRequest(NoAuthTargetName, "AM-"+pathDetail.OperationID, pathDetail.OperationID); err != nil {
+						if err = g.targetEndpoints.AddStepToFlowRequest(NoAuthTargetName, "AM-"+pathDetail.OperationID, pathDetail.OperationID); err != nil {
 							return err
 						}
 					}
-					apiproxy.AddPolicy("AM-" + pathDetail.OperationID)
+					g.apiProxy.AddPolicy("AM-" + pathDetail.OperationID)
 				}
 			}
 			if pathDetail.SecurityScheme.JWTPolicy.JWTPolicyEnabled {
 				// handle jwt locations
 				if len(pathDetail.SecurityScheme.JWTPolicy.Location) != 0 { // jwt-location is specified
-					if err = proxies.AddStepToFlowRequest("ExtractJWT-"+pathDetail.SecurityScheme.SchemeName, pathDetail.OperationID); err != nil {
+					if err = g.proxyEndpoint.AddStepToFlowRequest("ExtractJWT-"+pathDetail.SecurityScheme.SchemeName, pathDetail.OperationID); err != nil {
 						return err
 					}
-					apiproxy.AddPolicy("ExtractJWT-" + pathDetail.SecurityScheme.SchemeName)
+					g.apiProxy.AddPolicy("ExtractJWT-" + pathDetail.SecurityScheme.SchemeName)
 				}
 				// end handle jwt locations
-				if err = proxies.AddStepToFlowRequest("VerifyJWT-"+pathDetail.SecurityScheme.SchemeName, pathDetail.OperationID); err != nil {
+				if err = g.proxyEndpoint.AddStepToFlowRequest("VerifyJWT-"+pathDetail.SecurityScheme.SchemeName, pathDetail.OperationID); err != nil {
 					return err
 				}
-				apiproxy.AddPolicy("VerifyJWT-" + pathDetail.SecurityScheme.SchemeName)
-				enableSecurityPolicy(pathDetail.SecurityScheme.SchemeName, "jwt")
+				g.apiProxy.AddPolicy("VerifyJWT-" + pathDetail.SecurityScheme.SchemeName)
+				g.enableSecurityPolicy(pathDetail.SecurityScheme.SchemeName, "jwt")
 				// copy the original authorization header to X-Forwarded-Authorization
 				// source: https://cloud.google.com/endpoints/docs/openapi/openapi-extensions#jwt_audience
-				if err = proxies.AddStepToFlowRequest("Copy-Auth-Var", pathDetail.OperationID); err != nil {
+				if err = g.proxyEndpoint.AddStepToFlowRequest("Copy-Auth-Var", pathDetail.OperationID); err != nil {
 					return err
 				}
-				apiproxy.AddPolicy("Copy-Auth-Var")
-				policies.EnableCopyAuthPolicy()
+				g.apiProxy.AddPolicy("Copy-Auth-Var")
+				g.copyAuth = true
 			}
 			if pathDetail.SecurityScheme.APIKeyPolicy.APIKeyPolicyEnabled {
-				if err = proxies.AddStepToFlowRequest("Verify-API-Key-"+pathDetail.SecurityScheme.SchemeName, pathDetail.OperationID); err != nil {
+				if err = g.proxyEndpoint.AddStepToFlowRequest("Verify-API-Key-"+pathDetail.SecurityScheme.SchemeName, pathDetail.OperationID); err != nil {
 					return err
 				}
 			}
 			if pathDetail.Quota.QuotaEnabled {
-				if err = proxies.AddStepToFlowRequest("Quota-"+pathDetail.Quota.QuotaName, pathDetail.OperationID); err != nil {
+				if err = g.proxyEndpoint.AddStepToFlowRequest("Quota-"+pathDetail.Quota.QuotaName, pathDetail.OperationID); err != nil {
 					return err
 				}
 			}
@@ -685,29 +672,29 @@ func parseApiExtension(i interface{}) (string, error) {
 	return str, nil
 }
 
-func parseManagementExtension(i interface{}) error {
-	googMgmt = googleManagementDef{}
+func (g *Generator) parseManagementExtension(i interface{}) error {
+	g.googMgmt = googleManagementDef{}
 	str := fmt.Sprintf("%s", i)
 
 	clilog.Debug.Printf("Raw x-google-management: %s\n", str)
 
-	if err := json.Unmarshal([]byte(str), &googMgmt); err != nil {
+	if err := json.Unmarshal([]byte(str), &g.googMgmt); err != nil {
 		return err
 	}
 
-	for _, limit := range googMgmt.Quota.Limits {
+	for _, limit := range g.googMgmt.Quota.Limits {
 		quota := quotaDef{}
 		quota.QuotaName = limit.Metric
 		quota.QuotaTimeUnitLiteral = "minute"
 		quota.QuotaIntervalLiteral = "1"
 		quota.QuotaAllowLiteral = limit.Value.Standard
 		clilog.Debug.Printf("Found quota definition: %v\n", quota)
-		quotaList = append(quotaList, quota)
+		g.quotaList = append(g.quotaList, quota)
 	}
 	return nil
 }
 
-func parseQuotaExtension(i interface{}) (quotaDef, error) {
+func (g *Generator) parseQuotaExtension(i interface{}) (quotaDef, error) {
 	var jsonMap map[string]interface{}
 
 	str := fmt.Sprintf("%s", i)
@@ -722,23 +709,23 @@ func parseQuotaExtension(i interface{}) (quotaDef, error) {
 		tmp = strings.ReplaceAll(tmp, "map[", "")
 		tmp = strings.ReplaceAll(tmp, "]", "")
 		keyValue := strings.Split(tmp, ":")
-		for index, quota := range quotaList {
+		for index, quota := range g.quotaList {
 			if keyValue[0] == quota.QuotaName {
-				quotaList[index].QuotaAllowLiteral = keyValue[1]
-				quotaList[index].QuotaEnabled = true
-				quotaList[index].QuotaIdentiferLiteral = "organization.name" // this mimics rate limit per project which endpoints does.
+				g.quotaList[index].QuotaAllowLiteral = keyValue[1]
+				g.quotaList[index].QuotaEnabled = true
+				g.quotaList[index].QuotaIdentiferLiteral = "organization.name" // this mimics rate limit per project which endpoints does.
 				// store the XML policy contents
-				quotaPolicyContent[quota.QuotaName] = policies.AddQuotaPolicy("Quota-"+quotaList[index].QuotaName,
-					quotaList[index].QuotaConfigStepName,
-					quotaList[index].QuotaAllowRef,
-					quotaList[index].QuotaAllowLiteral,
-					quotaList[index].QuotaIntervalRef,
-					quotaList[index].QuotaIntervalLiteral,
-					quotaList[index].QuotaTimeUnitRef,
-					quotaList[index].QuotaTimeUnitLiteral,
-					quotaList[index].QuotaIdentifierRef,
-					quotaList[index].QuotaIdentiferLiteral)
-				return quotaList[index], nil
+				g.quotaPolicyContent[quota.QuotaName] = policies.AddQuotaPolicy("Quota-"+g.quotaList[index].QuotaName,
+					g.quotaList[index].QuotaConfigStepName,
+					g.quotaList[index].QuotaAllowRef,
+					g.quotaList[index].QuotaAllowLiteral,
+					g.quotaList[index].QuotaIntervalRef,
+					g.quotaList[index].QuotaIntervalLiteral,
+					g.quotaList[index].QuotaTimeUnitRef,
+					g.quotaList[index].QuotaTimeUnitLiteral,
+					g.quotaList[index].QuotaIdentifierRef,
+					g.quotaList[index].QuotaIdentiferLiteral)
+				return g.quotaList[index], nil
 			}
 		}
 	}
@@ -753,7 +740,7 @@ func getConditionString(matchespath string, verb string) string {
 	return "(proxy.pathsuffix MatchesPath \"" + matchespath + "\") and (request.verb = \"" + strings.ToUpper(verb) + "\")"
 }
 
-func processPathSwaggerExtensions(extensions map[string]interface{}, pathDetail pathDetailDef) (pathDetailDef, error) {
+func (g *Generator) processPathSwaggerExtensions(extensions map[string]interface{}, pathDetail pathDetailDef) (pathDetailDef, error) {
 	var err error
 	for extensionName, extensionValue := range extensions {
 		if extensionName == "x-google-backend" {
@@ -763,19 +750,19 @@ func processPathSwaggerExtensions(extensions map[string]interface{}, pathDetail
 				return pathDetail, err
 			}
 			if backend.JwtAudience != "" {
-				proxies.AddRoute(pathDetail.OperationID, GoogleAuthTargetName, getConditionString(pathDetail.Path, pathDetail.Verb))
+				g.proxyEndpoint.AddRoute(pathDetail.OperationID, GoogleAuthTargetName, getConditionString(pathDetail.Path, pathDetail.Verb))
 			} else {
-				proxies.AddRoute(pathDetail.OperationID, NoAuthTargetName, getConditionString(pathDetail.Path, pathDetail.Verb))
+				g.proxyEndpoint.AddRoute(pathDetail.OperationID, NoAuthTargetName, getConditionString(pathDetail.Path, pathDetail.Verb))
 			}
 			pathDetail.AssignMessage = policies.AddSetTargetEndpoint("AM-"+pathDetail.OperationID, backend.Address, backend.PathTranslation)
-			setAMPolicy(pathDetail.OperationID, pathDetail.AssignMessage)
-			if err = addBackend(backend); err != nil {
+			g.setAMPolicy(pathDetail.OperationID, pathDetail.AssignMessage)
+			if err = g.addBackend(backend); err != nil {
 				return pathDetail, err
 			}
 			pathDetail.Backend = backend
 		} else if extensionName == "x-google-quota" {
 			// process quota
-			quota, err := parseQuotaExtension(extensionValue)
+			quota, err := g.parseQuotaExtension(extensionValue)
 			if err != nil {
 				return pathDetail, err
 			}
@@ -785,48 +772,48 @@ func processPathSwaggerExtensions(extensions map[string]interface{}, pathDetail
 	return pathDetail, err
 }
 
-func GetAMPolicies() map[string]string {
-	return amPolicyContent
+func (g *Generator) GetAMPolicies() map[string]string {
+	return g.amPolicyContent
 }
 
-func GetGoogleApiName() string {
-	return apiName
+func (g *Generator) GetGoogleApiName() string {
+	return g.apiName
 }
 
-func GetAllowDefinition() string {
-	return allowValue
+func (g *Generator) GetAllowDefinition() string {
+	return g.allowValue
 }
 
-func setAMPolicy(name string, content string) {
-	amPolicyContent[name] = content
+func (g *Generator) setAMPolicy(name string, content string) {
+	g.amPolicyContent[name] = content
 }
 
-func addBackend(backend backendDef) (err error) {
+func (g *Generator) addBackend(backend backendDef) (err error) {
 	if backend.Address == "" {
 		return fmt.Errorf("address is a mandatory field in x-google-backend")
 	}
 	// if there is a jwt_audience specified and auth is not disabled, use google auth
 	clilog.Debug.Printf("JwtAudience %s and DisableAuth %t\n", backend.JwtAudience, backend.DisableAuth)
 	if backend.JwtAudience != "" && !backend.DisableAuth {
-		if !targets.IsExists(GoogleAuthTargetName) {
+		if !g.targetEndpoints.IsExists(GoogleAuthTargetName) {
 			clilog.Debug.Println("Adding Google Auth Target Server")
-			apiproxy.AddTargetEndpoint(GoogleAuthTargetName)
-			targets.NewTargetEndpoint(GoogleAuthTargetName, backend.Address, "", backend.JwtAudience, "")
+			g.apiProxy.AddTargetEndpoint(GoogleAuthTargetName)
+			g.targetEndpoints.NewTargetEndpoint(GoogleAuthTargetName, backend.Address, "", backend.JwtAudience, "")
 			// at the moment one cannot have different deadlines per target.
 			if backend.Deadline > 0 {
-				targets.AddTargetEndpointProperty(GoogleAuthTargetName, "connect.timeout.millis", fmt.Sprintf("%d", backend.Deadline*1000))
+				g.targetEndpoints.AddTargetEndpointProperty(GoogleAuthTargetName, "connect.timeout.millis", fmt.Sprintf("%d", backend.Deadline*1000))
 			}
 		} else {
 			clilog.Debug.Println("Google Auth Target Server already exists")
 		}
 	} else {
-		if !targets.IsExists(NoAuthTargetName) {
+		if !g.targetEndpoints.IsExists(NoAuthTargetName) {
 			clilog.Debug.Println("Adding Default Target Server")
-			apiproxy.AddTargetEndpoint(NoAuthTargetName)
-			targets.NewTargetEndpoint(NoAuthTargetName, backend.Address, "", "", "")
+			g.apiProxy.AddTargetEndpoint(NoAuthTargetName)
+			g.targetEndpoints.NewTargetEndpoint(NoAuthTargetName, backend.Address, "", "", "")
 			// at the moment one cannot have different deadlines per target.
 			if backend.Deadline > 0 {
-				targets.AddTargetEndpointProperty(NoAuthTargetName, "connect.timeout.millis", fmt.Sprintf("%d", backend.Deadline*1000))
+				g.targetEndpoints.AddTargetEndpointProperty(NoAuthTargetName, "connect.timeout.millis", fmt.Sprintf("%d", backend.Deadline*1000))
 			}
 		} else {
 			clilog.Debug.Println("Default Target Server already exists")
@@ -0,0 +1,145 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TargetServer is a target server derived from a server of the OpenAPI document
+type TargetServer struct {
+	Name string
+	Env  string
+	URL  string
+	Host string
+	Port int
+	TLS  bool
+}
+
+// GetTargetServers returns a target server per server of the loaded document.
+// When servers are annotated with x-apigee-environment, the servers share the
+// target server name, so that the API proxy reaches a different host in every
+// environment; a server without annotation is used for the other environments.
+// Otherwise the servers are named <name>-<n> and load balanced.
+// x-apigee-target-server on a server overrides the name.
+func (g *Generator) GetTargetServers(name string) (targetServers []TargetServer, err error) {
+	if g.doc == nil {
+		return nil, fmt.Errorf("the Open API document not loaded")
+	}
+	if len(g.doc.Servers) == 0 {
+		return nil, fmt.Errorf("at least one server must be present")
+	}
+
+	byEnvironment := false
+	for _, server := range g.doc.Servers {
+		_, ok := server.Extensions["x-apigee-environment"]
+		byEnvironment = byEnvironment || ok
+	}
+
+	seen := map[string]bool{}
+	for index, server := range g.doc.Servers {
+		targetServer := TargetServer{}
+
+		if i, ok := server.Extensions["x-apigee-environment"]; ok {
+			if err = json.Unmarshal([]byte(extensionString(i)), &targetServer.Env); err != nil || targetServer.Env == "" {
+				return nil, fmt.Errorf("x-apigee-environment extension of server %s must be an environment name", server.URL)
+			}
+		}
+		if byEnvironment {
+			targetServer.Name = name
+		} else {
+			targetServer.Name = name + "-" + strconv.Itoa(index+1)
+		}
+
+		if i, ok := server.Extensions["x-apigee-target-server"]; ok {
+			ext := targetServerExtDef{}
+			if err = decodeExtension("x-apigee-target-server", i, &ext); err != nil {
+				return nil, err
+			}
+			if ext.Name == "" {
+				return nil, fmt.Errorf("x-apigee-target-server extension must have a name")
+			}
+			targetServer.Name = ext.Name
+		}
+
+		// server variables are replaced with their default values
+		targetServer.URL = server.URL
+		for variableName, variable := range server.Variables {
+			targetServer.URL = strings.ReplaceAll(targetServer.URL, "{"+variableName+"}", variable.Default)
+		}
+		u, err := url.Parse(targetServer.URL)
+		if err != nil || u.Hostname() == "" {
+			return nil, fmt.Errorf("invalid server url %s", server.URL)
+		}
+		targetServer.Host = u.Hostname()
+		targetServer.TLS = u.Scheme == "https"
+		if u.Port() != "" {
+			if targetServer.Port, err = strconv.Atoi(u.Port()); err != nil {
+				return nil, fmt.Errorf("invalid port in server url %s", server.URL)
+			}
+		} else if targetServer.TLS {
+			targetServer.Port = 443
+		} else {
+			targetServer.Port = 80
+		}
+
+		key := targetServer.Env + "/" + targetServer.Name
+		if seen[key] {
+			if targetServer.Env != "" {
+				return nil, fmt.Errorf("more than one server for target server %s in environment %s", targetServer.Name, targetServer.Env)
+			}
+			return nil, fmt.Errorf("more than one server without x-apigee-environment for target server %s", targetServer.Name)
+		}
+		seen[key] = true
+
+		targetServers = append(targetServers, targetServer)
+	}
+	return targetServers, nil
+}
+
+// UseTargetServers sets the default TargetEndpoint to load balance over the
+// target servers of the document servers, instead of using the first server url
+func (g *Generator) UseTargetServers(useTargetServers bool) {
+	g.useTargetServers = useTargetServers
+}
+
+// setTargetServers sets the LoadBalancer of the default TargetEndpoint
+func (g *Generator) setTargetServers(name string, path string) error {
+	targetServers, err := g.GetTargetServers(name)
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for _, targetServer := range targetServers {
+		if !contains(names, targetServer.Name) {
+			names = append(names, targetServer.Name)
+		}
+	}
+	g.targetEndpoints.SetLoadBalancer(NoAuthTargetName, names, path)
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlegen
+
+import (
+	"sync"
+
+	apiproxy "internal/bundlegen/apiproxydef"
+	"internal/bundlegen/proxies"
+	"internal/bundlegen/targets"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Generator holds the state of a single API proxy being generated. A new
+// Generator must be used for every proxy; separate Generators can be used
+// concurrently.
+type Generator struct {
+	doc  *openapi3.T
+	doc2 *openapi2.T
+
+	apiProxy        *apiproxy.APIProxyDef
+	proxyEndpoint   *proxies.ProxyEndpointDef
+	targetEndpoints targets.TargetEndpoints
+
+	securitySchemesList      securitySchemesListDef
+	quotaPolicyContent       map[string]string
+	spikeArrestPolicyContent map[string]string
+	amPolicyContent          map[string]string
+	extensionPolicyContent   map[string]string
+	mockPolicyContent        map[string]string
+	mockScriptContent        map[string]string
+
+	generateSetTarget bool
+	copyAuth          bool
+
+	allowValue, apiName string
+	googMgmt            googleManagementDef
+	quotaList           []quotaDef
+	docCacheSteps       []string
+	defaultBackend      backendDef
+
+	templateDir      string
+	useTargetServers bool
+	mock             bool
+}
+
+// loaderMu serializes the loading of OpenAPI documents, the kin-openapi
+// loader settings are package level
+var loaderMu sync.Mutex
+
+func NewGenerator() *Generator {
+	return &Generator{
+		apiProxy:                 &apiproxy.APIProxyDef{},
+		proxyEndpoint:            &proxies.ProxyEndpointDef{},
+		quotaPolicyContent:       map[string]string{},
+		spikeArrestPolicyContent: map[string]string{},
+		amPolicyContent:          map[string]string{},
+		extensionPolicyContent:   map[string]string{},
+		mockPolicyContent:        map[string]string{},
+		mockScriptContent:        map[string]string{},
+	}
+}
+
+func (g *Generator) GetAPIProxy() (string, error) {
+	return g.apiProxy.GetAPIProxy()
+}
+
+func (g *Generator) GetProxyEndpoint() (string, error) {
+	return g.proxyEndpoint.GetProxyEndpoint()
+}
+
+func (g *Generator) GetTargetEndpoints() targets.TargetEndpoints {
+	return g.targetEndpoints
+}
+
+func (g *Generator) IsCopyAuthEnabled() bool {
+	return g.copyAuth
+}
+
+// SetTemplateDir sets the folder of the templates applied to the generated bundle
+func (g *Generator) SetTemplateDir(templateDir string) {
+	g.templateDir = templateDir
+}
+
+func (g *Generator) GetTemplateDir() string {
+	return g.templateDir
+}
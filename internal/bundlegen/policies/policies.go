@@ -15,8 +15,10 @@
 package policies
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -223,7 +225,39 @@ var extractJwtHeaderPolicy = `<?xml version="1.0" encoding="UTF-8" standalone="y
 	<IgnoreUnresolvedVariables>true</IgnoreUnresolvedVariables>
 </ExtractVariables>`
 
-var copyAuth = false
+var responseCachePolicy = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<ResponseCache async="false" continueOnError="false" enabled="true" name="RC-1">
+    <DisplayName>RC-1</DisplayName>
+    <CacheKey>
+<KeyFragments/>
+    </CacheKey>
+    <Scope>Exclusive</Scope>
+    <ExpirySettings>
+        <TimeoutInSeconds>300</TimeoutInSeconds>
+    </ExpirySettings>
+</ResponseCache>`
+
+var assignMessagePolicy = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<AssignMessage async="false" continueOnError="false" enabled="true" name="AM-1">
+    <DisplayName>AM-1</DisplayName>
+<Remove/>
+<Set/>
+    <IgnoreUnresolvedVariables>true</IgnoreUnresolvedVariables>
+    <AssignTo createNew="false" transport="http" type="request"/>
+</AssignMessage>`
+
+var flowCalloutPolicy = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<FlowCallout async="false" continueOnError="false" enabled="true" name="FC-1">
+    <DisplayName>FC-1</DisplayName>
+<Parameters/>
+    <SharedFlowBundle>shared-flow</SharedFlowBundle>
+</FlowCallout>`
+
+var mockResponsePolicy = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Javascript async="false" continueOnError="false" enabled="true" timeLimit="200" name="JS-Mock-1">
+    <DisplayName>JS-Mock-1</DisplayName>
+    <ResourceURL>jsc://JS-Mock-1.js</ResourceURL>
+</Javascript>`
 
 func AddSetIntegrationRequestPolicy(integration string, apitrigger string) string {
 	policyString := strings.ReplaceAll(setIntegrationRequestPolicy, "integration_name", integration)
@@ -356,9 +390,15 @@ func AddSetTargetEndpoint(name string, endpoint string, path_transalation string
 	return strings.Replace(policyString, "NAME", name, -1)
 }
 
-func AddGraphQLPolicy(name string, action string, schema string) string {
+func AddGraphQLPolicy(name string, action string, schema string, operationType string, maxDepth int, maxCount int) string {
 	policyString := strings.ReplaceAll(graphQLPolicy, "schema.graphql", schema)
 	policyString = strings.ReplaceAll(policyString, "Validate-name-Schema", "Validate-"+name+"-Schema")
+	policyString = strings.Replace(policyString, "<OperationType>query</OperationType>",
+		"<OperationType>"+operationType+"</OperationType>", 1)
+	policyString = strings.Replace(policyString, "<MaxDepth>4</MaxDepth>",
+		fmt.Sprintf("<MaxDepth>%d</MaxDepth>", maxDepth), 1)
+	policyString = strings.Replace(policyString, "<MaxCount>4</MaxCount>",
+		fmt.Sprintf("<MaxCount>%d</MaxCount>", maxCount), 1)
 	if action != "" {
 		policyString = strings.ReplaceAll(policyString, "parse", action)
 	}
@@ -367,6 +407,12 @@ func AddGraphQLPolicy(name string, action string, schema string) string {
 
 func AddVerifyJWTPolicy(name string, jwks string, issuer string, audience string, source string) string {
 	policyString := strings.ReplaceAll(verifyJwtPolicy, "SECURITY_POLICY_NAME", name)
+	if issuer == "" {
+		policyString = strings.ReplaceAll(policyString, "    <Issuer>JWT_ISSUER</Issuer>\n", "")
+	}
+	if audience == "" {
+		policyString = strings.ReplaceAll(policyString, "    <Audience>JWT_AUDIENCE</Audience>\n", "")
+	}
 	policyString = strings.ReplaceAll(policyString, "JWT_JWKS", jwks)
 	policyString = strings.ReplaceAll(policyString, "JWT_ISSUER", issuer)
 	policyString = strings.ReplaceAll(policyString, "JWT_AUDIENCE", audience)
@@ -376,6 +422,106 @@ func AddVerifyJWTPolicy(name string, jwks string, issuer string, audience string
 	return policyString
 }
 
+func AddResponseCachePolicy(name string, ttl string, ttlRef string, keyFragments []string) string {
+	policyString := strings.ReplaceAll(responseCachePolicy, "RC-1", name)
+	if len(keyFragments) == 0 {
+		keyFragments = []string{"request.uri"}
+	}
+	fragments := []string{}
+	for _, keyFragment := range keyFragments {
+		fragments = append(fragments, "        <KeyFragment ref=\""+escape(keyFragment)+"\" type=\"string\"/>")
+	}
+	policyString = strings.ReplaceAll(policyString, "<KeyFragments/>", strings.Join(fragments, "\n"))
+	if ttlRef != "" {
+		policyString = strings.ReplaceAll(policyString, "<TimeoutInSeconds>300</TimeoutInSeconds>", "<TimeoutInSeconds ref=\""+escape(ttlRef)+"\"/>")
+	} else if ttl != "" {
+		policyString = strings.ReplaceAll(policyString, "<TimeoutInSeconds>300</TimeoutInSeconds>",
+			"<TimeoutInSeconds>"+escape(ttl)+"</TimeoutInSeconds>")
+	}
+	return policyString
+}
+
+func AddAssignMessagePolicy(name string, flow string, headers map[string]string, queryParams map[string]string, removeHeaders []string) string {
+	policyString := strings.ReplaceAll(assignMessagePolicy, "AM-1", name)
+	if flow == "response" {
+		policyString = strings.ReplaceAll(policyString, "type=\"request\"", "type=\"response\"")
+	}
+
+	remove := ""
+	if len(removeHeaders) > 0 {
+		remove = "    <Remove>\n        <Headers>\n"
+		for _, header := range removeHeaders {
+			remove += "            <Header name=\"" + escape(header) + "\"/>\n"
+		}
+		remove += "        </Headers>\n    </Remove>"
+	}
+	policyString = strings.ReplaceAll(policyString, "<Remove/>", remove)
+
+	set := ""
+	if len(headers) > 0 || len(queryParams) > 0 {
+		set = "    <Set>\n"
+		if len(headers) > 0 {
+			set += "        <Headers>\n"
+			for _, header := range sortedKeys(headers) {
+				set += "            <Header name=\"" + escape(header) + "\">" + escape(headers[header]) + "</Header>\n"
+			}
+			set += "        </Headers>\n"
+		}
+		if len(queryParams) > 0 {
+			set += "        <QueryParams>\n"
+			for _, queryParam := range sortedKeys(queryParams) {
+				set += "            <QueryParam name=\"" + escape(queryParam) + "\">" + escape(queryParams[queryParam]) + "</QueryParam>\n"
+			}
+			set += "        </QueryParams>\n"
+		}
+		set += "    </Set>"
+	}
+	policyString = strings.ReplaceAll(policyString, "<Set/>", set)
+	return removeEmptyLines(policyString)
+}
+
+// AddMockResponsePolicy returns a JavaScript policy running the script name.js
+func AddMockResponsePolicy(name string) string {
+	return strings.ReplaceAll(mockResponsePolicy, "JS-Mock-1", name)
+}
+
+// AddMockResponseScript returns the script of a mock response policy. The values
+// are JavaScript string literals, unlike AssignMessage they are never
+// interpreted as message templates
+func AddMockResponseScript(statusCode string, headers map[string]string, contentType string, payload string) string {
+	literal := func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+
+	script := "context.setVariable(\"response.status.code\", " + literal(statusCode) + ");\n"
+	for _, header := range sortedKeys(headers) {
+		script += "context.setVariable(" + literal("response.header."+header) + ", " + literal(headers[header]) + ");\n"
+	}
+	if payload != "" {
+		if contentType != "" {
+			script += "context.setVariable(\"response.header.Content-Type\", " + literal(contentType) + ");\n"
+		}
+		script += "context.setVariable(\"response.content\", " + literal(payload) + ");\n"
+	}
+	return script
+}
+
+func AddFlowCalloutPolicy(name string, sharedFlow string, parameters map[string]string) string {
+	policyString := strings.ReplaceAll(flowCalloutPolicy, "FC-1", name)
+	policyString = strings.ReplaceAll(policyString, "shared-flow", escape(sharedFlow))
+	params := ""
+	if len(parameters) > 0 {
+		params = "    <Parameters>\n"
+		for _, parameter := range sortedKeys(parameters) {
+			params += "        <Parameter name=\"" + escape(parameter) + "\">" + escape(parameters[parameter]) + "</Parameter>\n"
+		}
+		params += "    </Parameters>"
+	}
+	policyString = strings.ReplaceAll(policyString, "<Parameters/>", params)
+	return removeEmptyLines(policyString)
+}
+
 func AddRaiseFaultPolicy() string {
 	return rasiseFaultPolicy
 }
@@ -403,15 +549,26 @@ func AddCopyAuthHeaderPolicy() string {
 	return copyAuthHeaderPolicy
 }
 
-func EnableCopyAuthPolicy() {
-	copyAuth = true
+func replaceTemplateWithPolicy(name string) string {
+	re := regexp.MustCompile(`{(.*?)}`)
+	return re.ReplaceAllLiteralString(oasPolicyTemplate, name)
 }
 
-func IsCopyAuthEnabled() bool {
-	return copyAuth
+var attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;")
+
+func escape(s string) string {
+	return attrEscaper.Replace(s)
 }
 
-func replaceTemplateWithPolicy(name string) string {
-	re := regexp.MustCompile(`{(.*?)}`)
-	return re.ReplaceAllLiteralString(oasPolicyTemplate, name)
+func removeEmptyLines(s string) string {
+	return regexp.MustCompile(`\n\s*\n`).ReplaceAllString(s, "\n")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -23,16 +23,17 @@ import (
 	proxytypes "internal/bundlegen/common"
 )
 
-type proxyEndpointDef struct {
-	XMLName             xml.Name               `xml:"ProxyEndpoint"`
-	Name                string                 `xml:"name,attr"`
-	Description         string                 `xml:"Description,omitempty"`
-	FaultRules          string                 `xml:"FaultRules,omitempty"`
-	PreFlow             proxytypes.PreFlowDef  `xml:"PreFlow,omitempty"`
-	PostFlow            proxytypes.PostFlowDef `xml:"PostFlow,omitempty"`
-	Flows               proxytypes.FlowsDef    `xml:"Flows,omitempty"`
-	HTTPProxyConnection httpProxyConnectionDef `xml:"HTTPProxyConnection,omitempty"`
-	RouteRule           []routeRuleDef         `xml:"RouteRule,omitempty"`
+// ProxyEndpointDef is the default ProxyEndpoint of a bundle
+type ProxyEndpointDef struct {
+	XMLName             xml.Name                  `xml:"ProxyEndpoint"`
+	Name                string                    `xml:"name,attr"`
+	Description         string                    `xml:"Description,omitempty"`
+	FaultRules          *proxytypes.FaultRulesDef `xml:"FaultRules,omitempty"`
+	PreFlow             proxytypes.PreFlowDef     `xml:"PreFlow,omitempty"`
+	PostFlow            proxytypes.PostFlowDef    `xml:"PostFlow,omitempty"`
+	Flows               proxytypes.FlowsDef       `xml:"Flows,omitempty"`
+	HTTPProxyConnection httpProxyConnectionDef    `xml:"HTTPProxyConnection,omitempty"`
+	RouteRule           []routeRuleDef            `xml:"RouteRule,omitempty"`
 }
 
 type routeRuleDef struct {
@@ -51,9 +52,7 @@ type httpProxyConnectionDef struct {
 	VirtualHost []string `xml:"VirtualHost"`
 }
 
-var proxyEndpoint proxyEndpointDef
-
-func GetProxyEndpoint() (string, error) {
+func (proxyEndpoint *ProxyEndpointDef) GetProxyEndpoint() (string, error) {
 	proxyBody, err := xml.MarshalIndent(proxyEndpoint, "", " ")
 	if err != nil {
 		return "", nil
@@ -62,7 +61,8 @@ func GetProxyEndpoint() (string, error) {
 	return string(proxyBody), nil
 }
 
-func NewProxyEndpoint(basePath string, targetEndpoint bool) {
+func NewProxyEndpoint(basePath string, targetEndpoint bool) *ProxyEndpointDef {
+	proxyEndpoint := &ProxyEndpointDef{}
 	routeRule := routeRuleDef{}
 	proxyEndpoint.Name = "default"
 	proxyEndpoint.PreFlow.Name = "PreFlow"
@@ -77,9 +77,15 @@ func NewProxyEndpoint(basePath string, targetEndpoint bool) {
 		*routeRule.IntegrationEndpoint = "default"
 	}
 	proxyEndpoint.RouteRule = append(proxyEndpoint.RouteRule, routeRule)
+	return proxyEndpoint
+}
+
+// SetNoRoute replaces the route rules with a route rule without a target
+func (proxyEndpoint *ProxyEndpointDef) SetNoRoute() {
+	proxyEndpoint.RouteRule = []routeRuleDef{{Name: "noroute"}}
 }
 
-func AddFlow(operationId string, keyPath string, method string, description string) {
+func (proxyEndpoint *ProxyEndpointDef) AddFlow(operationId string, keyPath string, method string, description string) {
 	flow := proxytypes.FlowDef{}
 	flow.Name = operationId
 	if description != "" {
@@ -91,7 +97,16 @@ func AddFlow(operationId string, keyPath string, method string, description stri
 	proxyEndpoint.Flows.Flow = append(proxyEndpoint.Flows.Flow, flow)
 }
 
-func FlowExists(name string) bool {
+// AddConditionalFlow adds a flow with an arbitrary condition
+func (proxyEndpoint *ProxyEndpointDef) AddConditionalFlow(name string, condition string, description string) {
+	flow := proxytypes.FlowDef{}
+	flow.Name = name
+	flow.Description = description
+	flow.Condition.ConditionData = condition
+	proxyEndpoint.Flows.Flow = append(proxyEndpoint.Flows.Flow, flow)
+}
+
+func (proxyEndpoint *ProxyEndpointDef) FlowExists(name string) bool {
 	for _, flow := range proxyEndpoint.Flows.Flow {
 		if flow.Name == name {
 			return true
@@ -100,13 +115,31 @@ func FlowExists(name string) bool {
 	return false
 }
 
-func AddStepToPreFlowRequest(name string) {
+func (proxyEndpoint *ProxyEndpointDef) AddStepToPreFlowRequest(name string) {
 	step := proxytypes.StepDef{}
 	step.Name = name
 	proxyEndpoint.PreFlow.Request.Step = append(proxyEndpoint.PreFlow.Request.Step, &step)
 }
 
-func AddStepToFlowRequest(name string, flowName string) error {
+func (proxyEndpoint *ProxyEndpointDef) AddStepToPostFlowResponse(name string) {
+	step := proxytypes.StepDef{}
+	step.Name = name
+	proxyEndpoint.PostFlow.Response.Step = append(proxyEndpoint.PostFlow.Response.Step, &step)
+}
+
+func (proxyEndpoint *ProxyEndpointDef) AddStepToFlowResponse(name string, flowName string) error {
+	for flowKey, flow := range proxyEndpoint.Flows.Flow {
+		if flow.Name == flowName {
+			step := proxytypes.StepDef{}
+			step.Name = name
+			proxyEndpoint.Flows.Flow[flowKey].Response.Step = append(proxyEndpoint.Flows.Flow[flowKey].Response.Step, &step)
+			return nil
+		}
+	}
+	return fmt.Errorf("flow name not found")
+}
+
+func (proxyEndpoint *ProxyEndpointDef) AddStepToFlowRequest(name string, flowName string) error {
 	for flowKey, flow := range proxyEndpoint.Flows.Flow {
 		if flow.Name == flowName {
 			step := proxytypes.StepDef{}
@@ -118,7 +151,7 @@ func AddStepToFlowRequest(name string, flowName string) error {
 	return fmt.Errorf("flow name not found")
 }
 
-func AddRoute(name string, endpoint string, condition string) {
+func (proxyEndpoint *ProxyEndpointDef) AddRoute(name string, endpoint string, condition string) {
 	routeRule := routeRuleDef{}
 	routeRule.Name = name
 
@@ -127,5 +160,13 @@ func AddRoute(name string, endpoint string, condition string) {
 
 	routeRule.Condition = new(string)
 	*routeRule.Condition = condition
-	proxyEndpoint.RouteRule = append(proxyEndpoint.RouteRule, routeRule)
+
+	// route rules are evaluated in order, keep the ones without a condition last
+	index := len(proxyEndpoint.RouteRule)
+	for index > 0 && proxyEndpoint.RouteRule[index-1].Condition == nil {
+		index--
+	}
+	proxyEndpoint.RouteRule = append(proxyEndpoint.RouteRule, routeRuleDef{})
+	copy(proxyEndpoint.RouteRule[index+1:], proxyEndpoint.RouteRule[index:])
+	proxyEndpoint.RouteRule[index] = routeRule
 }
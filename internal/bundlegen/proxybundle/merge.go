@@ -0,0 +1,494 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxybundle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	apiproxy "internal/bundlegen/apiproxydef"
+	proxytypes "internal/bundlegen/common"
+	"internal/bundlegen/proxies"
+	"internal/bundlegen/targets"
+)
+
+// ManifestFileName records what was generated into an output folder. It is
+// written next to the apiproxy folder and used by MergeAPIProxy to tell
+// generated content apart from hand edits.
+const ManifestFileName = ".apigeecli-generated.json"
+
+type manifest struct {
+	// Files maps the path of every generated file to its sha256
+	Files map[string]string `json:"files"`
+	// Endpoints maps the path of every generated endpoint file to its content,
+	// the common base of the element level merge
+	Endpoints map[string]string `json:"endpoints"`
+}
+
+// writeAPIProxyFolder replaces the apiproxy folder in outputDir with bundleDir
+// and records the generated content in the manifest
+func writeAPIProxyFolder(bundleDir string, outputDir string) (err error) {
+	m, err := newManifest(bundleDir)
+	if err != nil {
+		return err
+	}
+
+	targetDir := filepath.Join(outputDir, rootDir)
+	if err = os.RemoveAll(targetDir); err != nil {
+		return err
+	}
+	if err = copyDir(bundleDir, targetDir); err != nil {
+		return err
+	}
+	return writeManifest(outputDir, m)
+}
+
+// MergeAPIProxy merges the apiproxy folder generated into generatedDir with the
+// one in outputDir. Files and endpoint elements are taken from the newly
+// generated proxy; policies, resources, endpoints and elements added by hand to
+// outputDir are preserved, as are local edits of generated files and elements.
+// Edits that could not be merged are returned as conflicts.
+func MergeAPIProxy(generatedDir string, outputDir string) (conflicts []string, err error) {
+	newDir := filepath.Join(generatedDir, rootDir)
+	oldDir := filepath.Join(outputDir, rootDir)
+
+	if _, err = os.Stat(oldDir); os.IsNotExist(err) {
+		return nil, writeAPIProxyFolder(newDir, outputDir)
+	}
+
+	generated, err := readManifest(generatedDir)
+	if err != nil {
+		return nil, err
+	}
+	// without a manifest, every local file and step is treated as a hand edit
+	previous, err := readManifest(outputDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	mergeDir, err := os.MkdirTemp("", "merge")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(mergeDir)
+
+	mergedDir := filepath.Join(mergeDir, rootDir)
+	if err = copyDir(newDir, mergedDir); err != nil {
+		return nil, err
+	}
+
+	if conflicts, err = mergeFiles(oldDir, mergedDir, previous); err != nil {
+		return conflicts, err
+	}
+
+	endpointConflicts, err := mergeEndpoints(oldDir, mergedDir, previous)
+	conflicts = append(conflicts, endpointConflicts...)
+	if err != nil {
+		return conflicts, err
+	}
+
+	if err = mergeAPIProxyDef(oldDir, mergedDir); err != nil {
+		return conflicts, err
+	}
+
+	if err = os.RemoveAll(oldDir); err != nil {
+		return conflicts, err
+	}
+	if err = copyDir(mergedDir, oldDir); err != nil {
+		return conflicts, err
+	}
+	// the manifest keeps describing the generated content, not the merge
+	return conflicts, writeManifest(outputDir, generated)
+}
+
+// mergeFiles copies hand added and locally modified files from oldDir to mergedDir
+func mergeFiles(oldDir string, mergedDir string, previous *manifest) (conflicts []string, err error) {
+	err = filepath.WalkDir(oldDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(oldDir, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !strings.Contains(rel, "/") {
+			// the APIProxy descriptor is merged by mergeAPIProxyDef
+			return nil
+		}
+		if isEndpointFile(rel) {
+			// endpoints are merged step by step unless added by hand
+			if _, err = os.Stat(filepath.Join(mergedDir, rel)); err == nil {
+				return nil
+			}
+		}
+
+		oldHash, err := hashFile(filePath)
+		if err != nil {
+			return err
+		}
+		newHash, _ := hashFile(filepath.Join(mergedDir, rel))
+
+		generatedHash := ""
+		if previous != nil {
+			generatedHash = previous.Files[rel]
+		}
+
+		switch {
+		case newHash == oldHash:
+			return nil
+		case generatedHash == "" && newHash != "":
+			conflicts = append(conflicts, fmt.Sprintf("%s: local file conflicts with a generated file, kept the local version", rel))
+		case generatedHash == "":
+			// added by hand
+		case oldHash == generatedHash:
+			// not modified locally, the generated version (or its removal) wins
+			return nil
+		case newHash == "":
+			conflicts = append(conflicts, fmt.Sprintf("%s: modified locally but no longer generated, kept the local version", rel))
+		case newHash != generatedHash:
+			conflicts = append(conflicts, fmt.Sprintf("%s: modified locally and by the spec, kept the local version", rel))
+		}
+		return copyFile(filePath, filepath.Join(mergedDir, rel))
+	})
+	return conflicts, err
+}
+
+// mergeEndpoints merges the proxy and target endpoints in oldDir with the
+// regenerated endpoints in mergedDir element by element, using the endpoints
+// recorded in the previous manifest as the common base
+func mergeEndpoints(oldDir string, mergedDir string, previous *manifest) (conflicts []string, err error) {
+	for _, dir := range []string{"proxies", "targets"} {
+		files, _ := filepath.Glob(filepath.Join(oldDir, dir, "*.xml"))
+		for _, oldFile := range files {
+			rel := dir + "/" + filepath.Base(oldFile)
+			newFile := filepath.Join(mergedDir, dir, filepath.Base(oldFile))
+			if _, err = os.Stat(newFile); err != nil {
+				// an endpoint added by hand is kept as is
+				continue
+			}
+
+			oldHash, err := hashFile(oldFile)
+			if err != nil {
+				return conflicts, err
+			}
+			newHash, err := hashFile(newFile)
+			if err != nil {
+				return conflicts, err
+			}
+			generatedHash, baseData := "", ""
+			if previous != nil {
+				generatedHash, baseData = previous.Files[rel], previous.Endpoints[rel]
+			}
+
+			switch {
+			case oldHash == newHash, oldHash == generatedHash:
+				// not modified locally, the generated version wins
+				continue
+			case newHash == generatedHash:
+				// not modified by the spec, the local file is kept as is
+				if err = copyFile(oldFile, newFile); err != nil {
+					return conflicts, err
+				}
+				continue
+			}
+
+			oldData, err := os.ReadFile(oldFile)
+			if err != nil {
+				return conflicts, err
+			}
+			newData, err := os.ReadFile(newFile)
+			if err != nil {
+				return conflicts, err
+			}
+			merged, endpointConflicts, err := mergeEndpoint(baseData, string(oldData), string(newData))
+			if err != nil {
+				return conflicts, fmt.Errorf("%s: %v", rel, err)
+			}
+			for _, conflict := range endpointConflicts {
+				conflicts = append(conflicts, rel+": "+conflict)
+			}
+			if err = writeXMLData(newFile, merged); err != nil {
+				return conflicts, err
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// mergeEndpoint merges the local and the newly generated content of an endpoint
+// file. base is the previously generated content, empty when unknown; without a
+// base every local difference is treated as a hand edit
+func mergeEndpoint(base string, local string, generated string) (merged string, conflicts []string, err error) {
+	var baseNode, localNode, generatedNode *xmlNode
+
+	if base != "" {
+		if baseNode, err = parseXMLNode([]byte(base)); err != nil {
+			return "", nil, err
+		}
+	}
+	if localNode, err = parseXMLNode([]byte(local)); err != nil {
+		return "", nil, err
+	}
+	if generatedNode, err = parseXMLNode([]byte(generated)); err != nil {
+		return "", nil, err
+	}
+	if localNode.Name != generatedNode.Name {
+		return "", nil, fmt.Errorf("root element %s does not match the generated %s", localNode.Name, generatedNode.Name)
+	}
+	if baseNode != nil && baseNode.Name != localNode.Name {
+		baseNode = nil
+	}
+
+	mergedNode := mergeXMLNode(localNode.key(0), baseNode, localNode, generatedNode, &conflicts)
+	return mergedNode.String(), conflicts, nil
+}
+
+// mergeAPIProxyDef keeps the policies, resources and endpoints of the old
+// APIProxy descriptor that are still present in mergedDir
+func mergeAPIProxyDef(oldDir string, mergedDir string) (err error) {
+	oldFiles, _ := filepath.Glob(filepath.Join(oldDir, "*.xml"))
+	newFiles, _ := filepath.Glob(filepath.Join(mergedDir, "*.xml"))
+	if len(oldFiles) == 0 || len(newFiles) == 0 {
+		return nil
+	}
+
+	oldProxy, newProxy := apiproxy.APIProxyDef{}, apiproxy.APIProxyDef{}
+	if err = readXML(oldFiles[0], &oldProxy); err != nil {
+		return err
+	}
+	if err = readXML(newFiles[0], &newProxy); err != nil {
+		return err
+	}
+
+	exists := func(elem ...string) bool {
+		_, err := os.Stat(filepath.Join(append([]string{mergedDir}, elem...)...))
+		return err == nil
+	}
+
+	for _, policy := range oldProxy.Policies.Policy {
+		if !contains(newProxy.Policies.Policy, policy) && exists("policies", policy+".xml") {
+			newProxy.Policies.Policy = append(newProxy.Policies.Policy, policy)
+		}
+	}
+	for _, resource := range oldProxy.Resources.Resource {
+		resType, resName, found := strings.Cut(resource, "://")
+		if found && !contains(newProxy.Resources.Resource, resource) && exists("resources", resType, resName) {
+			newProxy.Resources.Resource = append(newProxy.Resources.Resource, resource)
+		}
+	}
+	for _, endpoint := range oldProxy.ProxyEndpoints.ProxyEndpoint {
+		if !contains(newProxy.ProxyEndpoints.ProxyEndpoint, endpoint) && exists("proxies", endpoint+".xml") {
+			newProxy.ProxyEndpoints.ProxyEndpoint = append(newProxy.ProxyEndpoints.ProxyEndpoint, endpoint)
+		}
+	}
+	for _, endpoint := range oldProxy.TargetEndpoints.TargetEndpoint {
+		if !contains(newProxy.TargetEndpoints.TargetEndpoint, endpoint) && exists("targets", endpoint+".xml") {
+			newProxy.TargetEndpoints.TargetEndpoint = append(newProxy.TargetEndpoints.TargetEndpoint, endpoint)
+		}
+	}
+
+	apiProxyData, err := newProxy.GetAPIProxy()
+	if err != nil {
+		return err
+	}
+	return writeXMLData(newFiles[0], apiProxyData)
+}
+
+// endpoint is a proxy or a target endpoint read from a bundle
+type endpoint struct {
+	proxy  *proxies.ProxyEndpointDef
+	target *targets.TargetEndpointDef
+}
+
+func readEndpoint(filePath string) (e endpoint, err error) {
+	if filepath.Base(filepath.Dir(filePath)) == "proxies" {
+		e.proxy = &proxies.ProxyEndpointDef{}
+		return e, readXML(filePath, e.proxy)
+	}
+	e.target = &targets.TargetEndpointDef{}
+	return e, readXML(filePath, e.target)
+}
+
+func (e endpoint) write(filePath string) (err error) {
+	var data string
+	if e.proxy != nil {
+		data, err = e.proxy.GetProxyEndpoint()
+	} else {
+		data, err = targets.GetTargetEndpoint(*e.target)
+	}
+	if err != nil {
+		return err
+	}
+	return writeXMLData(filePath, data)
+}
+
+// flowSteps returns the steps of every flow of the endpoint keyed by
+// <flow>/<Request|Response>
+func (e endpoint) flowSteps() map[string]*[]*proxytypes.StepDef {
+	var preFlow *proxytypes.PreFlowDef
+	var postFlow *proxytypes.PostFlowDef
+	var flows *proxytypes.FlowsDef
+
+	if e.proxy != nil {
+		preFlow, postFlow, flows = &e.proxy.PreFlow, &e.proxy.PostFlow, &e.proxy.Flows
+	} else {
+		preFlow, postFlow, flows = &e.target.PreFlow, &e.target.PostFlow, &e.target.Flows
+	}
+
+	steps := map[string]*[]*proxytypes.StepDef{
+		"PreFlow/Request":   &preFlow.Request.Step,
+		"PreFlow/Response":  &preFlow.Response.Step,
+		"PostFlow/Response": &postFlow.Response.Step,
+		"PostFlow/Request":  &postFlow.Request.Step,
+	}
+	for index := range flows.Flow {
+		steps[flows.Flow[index].Name+"/Request"] = &flows.Flow[index].Request.Step
+		steps[flows.Flow[index].Name+"/Response"] = &flows.Flow[index].Response.Step
+	}
+	return steps
+}
+
+func hasStep(steps []*proxytypes.StepDef, name string) bool {
+	for _, step := range steps {
+		if step.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isEndpointFile(rel string) bool {
+	dir, file := filepath.Split(rel)
+	return (dir == "proxies/" || dir == "targets/") && filepath.Ext(file) == ".xml"
+}
+
+func newManifest(bundleDir string) (m *manifest, err error) {
+	m = &manifest{Files: map[string]string{}, Endpoints: map[string]string{}}
+	err = filepath.WalkDir(bundleDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(bundleDir, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if m.Files[rel], err = hashFile(filePath); err != nil {
+			return err
+		}
+		if isEndpointFile(rel) {
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			m.Endpoints[rel] = string(data)
+		}
+		return nil
+	})
+	return m, err
+}
+
+func readManifest(dir string) (*manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{}
+	if err = json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func writeManifest(dir string, m *manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFileName), data, 0o644)
+}
+
+func readXML(filePath string, v interface{}) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(data, v)
+}
+
+// hashFile returns the sha256 of a file, or an empty string if it does not exist
+func hashFile(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bytes.TrimSpace(data))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func copyDir(srcDir string, dstDir string) error {
+	return filepath.WalkDir(srcDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, filePath)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dstDir, rel), os.ModePerm)
+		}
+		return copyFile(filePath, filepath.Join(dstDir, rel))
+	})
+}
+
+func copyFile(src string, dst string) (err error) {
+	if err = os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
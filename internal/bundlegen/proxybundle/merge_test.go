@@ -0,0 +1,161 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxybundle
+
+import (
+	"strings"
+	"testing"
+)
+
+const baseProxyEndpoint = `<ProxyEndpoint name="default">
+ <PreFlow name="PreFlow">
+  <Request>
+   <Step>
+    <Name>Verify-API-Key</Name>
+   </Step>
+  </Request>
+  <Response></Response>
+ </PreFlow>
+ <Flows>
+  <Flow name="getPet">
+   <Request></Request>
+   <Response></Response>
+   <Condition>(proxy.pathsuffix MatchesPath "/pets/*") and (request.verb = "GET")</Condition>
+  </Flow>
+ </Flows>
+ <RouteRule name="default">
+  <TargetEndpoint>default</TargetEndpoint>
+ </RouteRule>
+</ProxyEndpoint>`
+
+const baseTargetEndpoint = `<TargetEndpoint name="default">
+ <HTTPTargetConnection>
+  <URL>https://petstore.example.com</URL>
+ </HTTPTargetConnection>
+</TargetEndpoint>`
+
+func TestMergeEndpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      string
+		local     string
+		generated string
+		contains  []string
+		excludes  []string
+		conflicts []string
+	}{
+		{
+			name:      "target URL edited by hand",
+			base:      baseTargetEndpoint,
+			local:     strings.Replace(baseTargetEndpoint, "petstore.example.com", "petstore.internal", 1),
+			generated: strings.Replace(baseTargetEndpoint, "</HTTPTargetConnection>", " <Path>/v2</Path>\n </HTTPTargetConnection>", 1),
+			contains:  []string{"<URL>https://petstore.internal</URL>", "<Path>/v2</Path>"},
+		},
+		{
+			name:      "target URL edited by hand and by the spec",
+			base:      baseTargetEndpoint,
+			local:     strings.Replace(baseTargetEndpoint, "petstore.example.com", "petstore.internal", 1),
+			generated: strings.Replace(baseTargetEndpoint, "petstore.example.com", "petstore.example.org", 1),
+			contains:  []string{"<URL>https://petstore.internal</URL>"},
+			conflicts: []string{"TargetEndpoint[default]/HTTPTargetConnection[0]/URL[0] modified locally and by the spec"},
+		},
+		{
+			name:      "flow condition edited by hand",
+			base:      baseProxyEndpoint,
+			local:     strings.Replace(baseProxyEndpoint, `"/pets/*"`, `"/pets/{petId}"`, 1),
+			generated: strings.Replace(baseProxyEndpoint, "<Response></Response>\n </PreFlow>", "<Response>\n   <Step>\n    <Name>CORS</Name>\n   </Step>\n  </Response>\n </PreFlow>", 1),
+			contains:  []string{`MatchesPath "/pets/{petId}"`, "<Name>CORS</Name>"},
+		},
+		{
+			name:      "flow condition edited by hand and by the spec",
+			base:      baseProxyEndpoint,
+			local:     strings.Replace(baseProxyEndpoint, `"/pets/*"`, `"/pets/{petId}"`, 1),
+			generated: strings.Replace(baseProxyEndpoint, `"GET"`, `"HEAD"`, 1),
+			contains:  []string{`/pets/{petId}`, `(request.verb = "GET")`},
+			conflicts: []string{"Flow[getPet]/Condition[0] modified locally and by the spec"},
+		},
+		{
+			name:      "step added by hand",
+			base:      baseProxyEndpoint,
+			local:     strings.Replace(baseProxyEndpoint, "<Name>Verify-API-Key</Name>\n   </Step>", "<Name>Verify-API-Key</Name>\n   </Step>\n   <Step>\n    <Name>Spike-Arrest</Name>\n   </Step>", 1),
+			generated: strings.Replace(baseProxyEndpoint, "<Request>\n   <Step>", "<Request>\n   <Step>\n    <Name>CORS</Name>\n   </Step>\n   <Step>", 1),
+			contains:  []string{"<Name>CORS</Name>\n   </Step>\n   <Step>\n    <Name>Verify-API-Key</Name>\n   </Step>\n   <Step>\n    <Name>Spike-Arrest</Name>"},
+		},
+		{
+			name:      "step removed by hand",
+			base:      baseProxyEndpoint,
+			local:     strings.Replace(baseProxyEndpoint, "<Step>\n    <Name>Verify-API-Key</Name>\n   </Step>", "", 1),
+			generated: strings.Replace(baseProxyEndpoint, "getPet", "getPetById", 1),
+			contains:  []string{`<Flow name="getPetById">`},
+			excludes:  []string{"Verify-API-Key", `<Flow name="getPet">`},
+		},
+		{
+			name:      "nested fault rules and route rules added by hand",
+			base:      baseProxyEndpoint,
+			local:     strings.Replace(baseProxyEndpoint, " <PreFlow", " <FaultRules>\n  <FaultRule name=\"invalid-key\">\n   <Step>\n    <Name>Raise-Invalid-Key</Name>\n   </Step>\n   <Condition>fault.name = \"InvalidApiKey\"</Condition>\n  </FaultRule>\n </FaultRules>\n <PreFlow", 1) + "",
+			generated: strings.Replace(baseProxyEndpoint, "</ProxyEndpoint>", " <RouteRule name=\"noroute\"></RouteRule>\n</ProxyEndpoint>", 1),
+			contains:  []string{`<FaultRule name="invalid-key">`, "<Name>Raise-Invalid-Key</Name>", `<RouteRule name="noroute">`},
+		},
+		{
+			name:      "route rule edited by hand while the spec removes it",
+			base:      strings.Replace(baseProxyEndpoint, "</ProxyEndpoint>", " <RouteRule name=\"mock\"></RouteRule>\n</ProxyEndpoint>", 1),
+			local:     strings.Replace(baseProxyEndpoint, "</ProxyEndpoint>", " <RouteRule name=\"mock\">\n  <Condition>request.header.mock = \"true\"</Condition>\n </RouteRule>\n</ProxyEndpoint>", 1),
+			generated: baseProxyEndpoint,
+			contains:  []string{`<RouteRule name="mock">`},
+			conflicts: []string{"RouteRule[mock] modified locally but no longer generated"},
+		},
+		{
+			name:      "comment added by hand",
+			base:      baseTargetEndpoint,
+			local:     strings.Replace(baseTargetEndpoint, "  <URL>", "  <!-- staging backend -->\n  <URL>", 1),
+			generated: strings.Replace(baseTargetEndpoint, "petstore.example.com", "petstore.example.org", 1),
+			contains:  []string{"<!-- staging backend -->\n  <URL>https://petstore.example.org</URL>"},
+		},
+		{
+			name:      "without a base local differences are kept",
+			local:     strings.Replace(baseTargetEndpoint, "petstore.example.com", "petstore.internal", 1),
+			generated: baseTargetEndpoint,
+			contains:  []string{"<URL>https://petstore.internal</URL>"},
+			conflicts: []string{"URL[0] modified locally and by the spec"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicts, err := mergeEndpoint(test.base, test.local, test.generated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range test.contains {
+				if !strings.Contains(merged, s) {
+					t.Errorf("merged endpoint does not contain %q:\n%s", s, merged)
+				}
+			}
+			for _, s := range test.excludes {
+				if strings.Contains(merged, s) {
+					t.Errorf("merged endpoint contains %q:\n%s", s, merged)
+				}
+			}
+			if len(conflicts) != len(test.conflicts) {
+				t.Fatalf("got conflicts %v, want %v", conflicts, test.conflicts)
+			}
+			for i, conflict := range test.conflicts {
+				if !strings.Contains(conflicts[i], conflict) {
+					t.Errorf("conflict %q does not contain %q", conflicts[i], conflict)
+				}
+			}
+		})
+	}
+}
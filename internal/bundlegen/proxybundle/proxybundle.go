@@ -29,12 +29,8 @@ import (
 
 	"internal/clilog"
 
-	"internal/bundlegen"
 	genapi "internal/bundlegen"
-	apiproxy "internal/bundlegen/apiproxydef"
 	policies "internal/bundlegen/policies"
-	proxies "internal/bundlegen/proxies"
-	"internal/bundlegen/targets"
 	target "internal/bundlegen/targets"
 
 	"github.com/google/go-github/github"
@@ -43,7 +39,8 @@ import (
 
 var rootDir = "apiproxy"
 
-func GenerateAPIProxyBundleFromOAS(name string,
+func GenerateAPIProxyBundleFromOAS(g *genapi.Generator,
+	name string,
 	content string,
 	fileName string,
 	skipPolicy bool,
@@ -53,6 +50,7 @@ func GenerateAPIProxyBundleFromOAS(name string,
 	oasGoogleIdTokenAudRef string,
 	oasTargetUrlRef string,
 	targetUrl string,
+	outputDir string,
 ) (err error) {
 	var apiProxyData, proxyEndpointData, targetEndpointData string
 	const resourceType = "oas"
@@ -62,32 +60,32 @@ func GenerateAPIProxyBundleFromOAS(name string,
 		return err
 	}
 
-	rootDir = path.Join(tmpDir, rootDir)
+	bundleDir := path.Join(tmpDir, rootDir)
 
-	if err = os.Mkdir(rootDir, os.ModePerm); err != nil {
+	if err = os.Mkdir(bundleDir, os.ModePerm); err != nil {
 		return err
 	}
 
 	// write API Proxy file
-	if apiProxyData, err = apiproxy.GetAPIProxy(); err != nil {
+	if apiProxyData, err = g.GetAPIProxy(); err != nil {
 		return err
 	}
 
-	err = writeXMLData(rootDir+string(os.PathSeparator)+name+".xml", apiProxyData)
+	err = writeXMLData(bundleDir+string(os.PathSeparator)+name+".xml", apiProxyData)
 	if err != nil {
 		return err
 	}
 
-	proxiesDirPath := rootDir + string(os.PathSeparator) + "proxies"
-	policiesDirPath := rootDir + string(os.PathSeparator) + "policies"
-	targetDirPath := rootDir + string(os.PathSeparator) + "targets"
-	resDirPath := rootDir + string(os.PathSeparator) + "resources" + string(os.PathSeparator) + resourceType //"oas"
+	proxiesDirPath := bundleDir + string(os.PathSeparator) + "proxies"
+	policiesDirPath := bundleDir + string(os.PathSeparator) + "policies"
+	targetDirPath := bundleDir + string(os.PathSeparator) + "targets"
+	resDirPath := bundleDir + string(os.PathSeparator) + "resources" + string(os.PathSeparator) + resourceType //"oas"
 
 	if err = os.Mkdir(proxiesDirPath, os.ModePerm); err != nil {
 		return err
 	}
 
-	if proxyEndpointData, err = proxies.GetProxyEndpoint(); err != nil {
+	if proxyEndpointData, err = g.GetProxyEndpoint(); err != nil {
 		return err
 	}
 
@@ -96,11 +94,14 @@ func GenerateAPIProxyBundleFromOAS(name string,
 		return err
 	}
 
-	if err = os.Mkdir(targetDirPath, os.ModePerm); err != nil {
-		return err
+	// mock API proxies have no target
+	if len(g.GetTargetEndpoints()) > 0 {
+		if err = os.Mkdir(targetDirPath, os.ModePerm); err != nil {
+			return err
+		}
 	}
 
-	for _, targetEndpoint := range targets.TargetEndpoints {
+	for _, targetEndpoint := range g.GetTargetEndpoints() {
 		if targetEndpointData, err = target.GetTargetEndpoint(targetEndpoint); err != nil {
 			return err
 		}
@@ -125,7 +126,7 @@ func GenerateAPIProxyBundleFromOAS(name string,
 
 	// add set target url
 	if targetUrl == "" {
-		if genapi.GenerateSetTargetPolicy() {
+		if g.GenerateSetTargetPolicy() {
 			if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Set-Target-1.xml",
 				policies.AddSetTargetEndpointRef(oasTargetUrlRef)); err != nil {
 				return err
@@ -134,7 +135,7 @@ func GenerateAPIProxyBundleFromOAS(name string,
 	}
 
 	// add security policies
-	for _, securityScheme := range genapi.GetSecuritySchemesList() {
+	for _, securityScheme := range g.GetSecuritySchemesList() {
 		if securityScheme.APIKeyPolicy.APIKeyPolicyEnabled {
 			if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Verify-API-Key-"+securityScheme.SchemeName+".xml",
 				policies.AddVerifyApiKeyPolicy(securityScheme.APIKeyPolicy.APIKeyLocation,
@@ -152,19 +153,44 @@ func GenerateAPIProxyBundleFromOAS(name string,
 	}
 
 	// add quota policies
-	for quotaPolicyName, quotaPolicyContent := range genapi.GetQuotaPolicies() {
+	for quotaPolicyName, quotaPolicyContent := range g.GetQuotaPolicies() {
 		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Quota-"+quotaPolicyName+".xml", quotaPolicyContent); err != nil {
 			return err
 		}
 	}
 
 	// add spike arrest policies
-	for spikeArrestPolicyName, spikeArrestPolicyContent := range genapi.GetSpikeArrestPolicies() {
+	for spikeArrestPolicyName, spikeArrestPolicyContent := range g.GetSpikeArrestPolicies() {
 		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Spike-Arrest-"+spikeArrestPolicyName+".xml", spikeArrestPolicyContent); err != nil {
 			return err
 		}
 	}
 
+	// add x-apigee extension policies
+	for policyName, policyContent := range g.GetExtensionPolicies() {
+		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+policyName+".xml", policyContent); err != nil {
+			return err
+		}
+	}
+
+	// add mock response policies
+	for policyName, policyContent := range g.GetMockPolicies() {
+		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+policyName+".xml", policyContent); err != nil {
+			return err
+		}
+	}
+	if len(g.GetMockScripts()) > 0 {
+		jscDirPath := bundleDir + string(os.PathSeparator) + "resources" + string(os.PathSeparator) + "jsc"
+		if err = os.MkdirAll(jscDirPath, os.ModePerm); err != nil {
+			return err
+		}
+		for scriptName, scriptContent := range g.GetMockScripts() {
+			if err = writeXMLData(jscDirPath+string(os.PathSeparator)+scriptName, scriptContent); err != nil {
+				return err
+			}
+		}
+	}
+
 	if !skipPolicy {
 		// add oas policy
 		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"OpenAPI-Spec-Validation-1.xml",
@@ -180,15 +206,20 @@ func GenerateAPIProxyBundleFromOAS(name string,
 		}
 	}
 
-	if err = archiveBundle(rootDir, name+".zip"); err != nil {
+	defer os.RemoveAll(tmpDir) // clean up
+
+	if err = applyTemplates(bundleDir, name, g.GetTemplateDir()); err != nil {
 		return err
 	}
 
-	defer os.RemoveAll(rootDir) // clean up
-	return nil
+	if outputDir != "" {
+		return writeAPIProxyFolder(bundleDir, outputDir)
+	}
+	return archiveBundle(bundleDir, name+".zip")
 }
 
-func GenerateAPIProxyBundleFromGQL(name string,
+func GenerateAPIProxyBundleFromGQL(g *genapi.Generator,
+	name string,
 	content string,
 	fileName string,
 	action string,
@@ -198,6 +229,13 @@ func GenerateAPIProxyBundleFromGQL(name string,
 	addCORS bool,
 	targetUrlRef string,
 	targetUrl string,
+	operationType string,
+	maxDepth int,
+	maxCount int,
+	quotas map[string]string,
+	quotaInterval string,
+	quotaTimeUnit string,
+	outputDir string,
 ) (err error) {
 	var apiProxyData, proxyEndpointData, targetEndpointData string
 	const resourceType = "graphql"
@@ -207,32 +245,32 @@ func GenerateAPIProxyBundleFromGQL(name string,
 		return err
 	}
 
-	rootDir = path.Join(tmpDir, rootDir)
+	bundleDir := path.Join(tmpDir, rootDir)
 
-	if err = os.Mkdir(rootDir, os.ModePerm); err != nil {
+	if err = os.Mkdir(bundleDir, os.ModePerm); err != nil {
 		return err
 	}
 
 	// write API Proxy file
-	if apiProxyData, err = apiproxy.GetAPIProxy(); err != nil {
+	if apiProxyData, err = g.GetAPIProxy(); err != nil {
 		return err
 	}
 
-	err = writeXMLData(rootDir+string(os.PathSeparator)+name+".xml", apiProxyData)
+	err = writeXMLData(bundleDir+string(os.PathSeparator)+name+".xml", apiProxyData)
 	if err != nil {
 		return err
 	}
 
-	proxiesDirPath := rootDir + string(os.PathSeparator) + "proxies"
-	policiesDirPath := rootDir + string(os.PathSeparator) + "policies"
-	targetDirPath := rootDir + string(os.PathSeparator) + "targets"
-	resDirPath := rootDir + string(os.PathSeparator) + "resources" + string(os.PathSeparator) + resourceType //"graphql"
+	proxiesDirPath := bundleDir + string(os.PathSeparator) + "proxies"
+	policiesDirPath := bundleDir + string(os.PathSeparator) + "policies"
+	targetDirPath := bundleDir + string(os.PathSeparator) + "targets"
+	resDirPath := bundleDir + string(os.PathSeparator) + "resources" + string(os.PathSeparator) + resourceType //"graphql"
 
 	if err = os.Mkdir(proxiesDirPath, os.ModePerm); err != nil {
 		return err
 	}
 
-	if proxyEndpointData, err = proxies.GetProxyEndpoint(); err != nil {
+	if proxyEndpointData, err = g.GetProxyEndpoint(); err != nil {
 		return err
 	}
 
@@ -245,7 +283,7 @@ func GenerateAPIProxyBundleFromGQL(name string,
 		return err
 	}
 
-	for _, targetEndpoint := range targets.TargetEndpoints {
+	for _, targetEndpoint := range g.GetTargetEndpoints() {
 		if targetEndpointData, err = target.GetTargetEndpoint(targetEndpoint); err != nil {
 			return err
 		}
@@ -278,9 +316,25 @@ func GenerateAPIProxyBundleFromGQL(name string,
 	if !skipPolicy {
 		// add gql policy
 		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Validate-"+name+"-Schema.xml",
-			policies.AddGraphQLPolicy(name, action, fileName)); err != nil {
+			policies.AddGraphQLPolicy(name, action, fileName, operationType, maxDepth, maxCount)); err != nil {
 			return err
 		}
+
+		// with an API key, the quota of the API product operation is used
+		useQuotaConfigStepName := ""
+		if keyName != "" {
+			useQuotaConfigStepName = "Verify-API-Key-" + name
+		}
+		for _, quotaType := range genapi.GraphQLOperationTypes {
+			if quotas[quotaType] == "" {
+				continue
+			}
+			if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Quota-"+quotaType+".xml",
+				policies.AddQuotaPolicy("Quota-"+quotaType, useQuotaConfigStepName,
+					"", quotas[quotaType], "", quotaInterval, "", quotaTimeUnit, "", "")); err != nil {
+				return err
+			}
+		}
 	}
 
 	if keyName != "" {
@@ -298,15 +352,127 @@ func GenerateAPIProxyBundleFromGQL(name string,
 		}
 	}
 
-	if err = archiveBundle(rootDir, name+".zip"); err != nil {
+	defer os.RemoveAll(tmpDir) // clean up
+
+	if err = applyTemplates(bundleDir, name, g.GetTemplateDir()); err != nil {
 		return err
 	}
 
-	defer os.RemoveAll(rootDir) // clean up
-	return nil
+	if outputDir != "" {
+		return writeAPIProxyFolder(bundleDir, outputDir)
+	}
+	return archiveBundle(bundleDir, name+".zip")
 }
 
-func GenerateIntegrationAPIProxyBundle(name string, integration string, apitrigger string, skipPolicy bool) (err error) {
+func GenerateAPIProxyBundleFromGRPC(g *genapi.Generator,
+	name string,
+	location string,
+	keyName string,
+	quotaLimit string,
+	quotaInterval string,
+	quotaTimeUnit string,
+	addCORS bool,
+	outputDir string,
+) (err error) {
+	var apiProxyData, proxyEndpointData, targetEndpointData string
+
+	tmpDir, err := os.MkdirTemp("", "proxy")
+	if err != nil {
+		return err
+	}
+
+	bundleDir := path.Join(tmpDir, rootDir)
+
+	if err = os.Mkdir(bundleDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	// write API Proxy file
+	if apiProxyData, err = g.GetAPIProxy(); err != nil {
+		return err
+	}
+
+	err = writeXMLData(bundleDir+string(os.PathSeparator)+name+".xml", apiProxyData)
+	if err != nil {
+		return err
+	}
+
+	proxiesDirPath := bundleDir + string(os.PathSeparator) + "proxies"
+	policiesDirPath := bundleDir + string(os.PathSeparator) + "policies"
+	targetDirPath := bundleDir + string(os.PathSeparator) + "targets"
+
+	if err = os.Mkdir(proxiesDirPath, os.ModePerm); err != nil {
+		return err
+	}
+
+	if proxyEndpointData, err = g.GetProxyEndpoint(); err != nil {
+		return err
+	}
+
+	err = writeXMLData(proxiesDirPath+string(os.PathSeparator)+"default.xml", proxyEndpointData)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Mkdir(targetDirPath, os.ModePerm); err != nil {
+		return err
+	}
+
+	for _, targetEndpoint := range g.GetTargetEndpoints() {
+		if targetEndpointData, err = target.GetTargetEndpoint(targetEndpoint); err != nil {
+			return err
+		}
+
+		if err = writeXMLData(targetDirPath+string(os.PathSeparator)+targetEndpoint.Name+".xml", targetEndpointData); err != nil {
+			return err
+		}
+	}
+
+	if err = os.Mkdir(policiesDirPath, os.ModePerm); err != nil {
+		return err
+	}
+
+	if keyName != "" {
+		// add verifyapi key policy
+		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Verify-API-Key-"+name+".xml",
+			policies.AddVerifyApiKeyPolicy(location, name, keyName)); err != nil {
+			return err
+		}
+	}
+
+	if quotaLimit != "" {
+		// with an API key, the quota of the API product operation is used
+		useQuotaConfigStepName := ""
+		if keyName != "" {
+			useQuotaConfigStepName = "Verify-API-Key-" + name
+		}
+		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Quota-"+name+".xml",
+			policies.AddQuotaPolicy("Quota-"+name, useQuotaConfigStepName,
+				"", quotaLimit, "", quotaInterval, "", quotaTimeUnit, "", "")); err != nil {
+			return err
+		}
+	}
+
+	if addCORS {
+		// add cors policy
+		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Add-CORS.xml", policies.AddCORSPolicy()); err != nil {
+			return err
+		}
+	}
+
+	defer os.RemoveAll(tmpDir) // clean up
+
+	if err = applyTemplates(bundleDir, name, g.GetTemplateDir()); err != nil {
+		return err
+	}
+
+	if outputDir != "" {
+		return writeAPIProxyFolder(bundleDir, outputDir)
+	}
+	return archiveBundle(bundleDir, name+".zip")
+}
+
+func GenerateIntegrationAPIProxyBundle(g *genapi.Generator, name string, integration string, apitrigger string, skipPolicy bool, outputDir string) (err error) {
 	var apiProxyData, proxyEndpointData, integrationEndpointData string
 
 	tmpDir, err := os.MkdirTemp("", "proxy")
@@ -314,31 +480,31 @@ func GenerateIntegrationAPIProxyBundle(name string, integration string, apitrigg
 		return err
 	}
 
-	rootDir = path.Join(tmpDir, rootDir)
+	bundleDir := path.Join(tmpDir, rootDir)
 
-	if err = os.Mkdir(rootDir, os.ModePerm); err != nil {
+	if err = os.Mkdir(bundleDir, os.ModePerm); err != nil {
 		return err
 	}
 
 	// write API Proxy file
-	if apiProxyData, err = apiproxy.GetAPIProxy(); err != nil {
+	if apiProxyData, err = g.GetAPIProxy(); err != nil {
 		return err
 	}
 
-	err = writeXMLData(rootDir+string(os.PathSeparator)+name+".xml", apiProxyData)
+	err = writeXMLData(bundleDir+string(os.PathSeparator)+name+".xml", apiProxyData)
 	if err != nil {
 		return err
 	}
 
-	proxiesDirPath := rootDir + string(os.PathSeparator) + "proxies"
-	policiesDirPath := rootDir + string(os.PathSeparator) + "policies"
-	integrationDirPath := rootDir + string(os.PathSeparator) + "integration-endpoints"
+	proxiesDirPath := bundleDir + string(os.PathSeparator) + "proxies"
+	policiesDirPath := bundleDir + string(os.PathSeparator) + "policies"
+	integrationDirPath := bundleDir + string(os.PathSeparator) + "integration-endpoints"
 
 	if err = os.Mkdir(proxiesDirPath, os.ModePerm); err != nil {
 		return err
 	}
 
-	if proxyEndpointData, err = proxies.GetProxyEndpoint(); err != nil {
+	if proxyEndpointData, err = g.GetProxyEndpoint(); err != nil {
 		return err
 	}
 
@@ -366,17 +532,23 @@ func GenerateIntegrationAPIProxyBundle(name string, integration string, apitrigg
 		return err
 	}
 
-	if err = archiveBundle(rootDir, name+".zip"); err != nil {
+	defer os.RemoveAll(tmpDir) // clean up
+
+	if err = applyTemplates(bundleDir, name, g.GetTemplateDir()); err != nil {
 		return err
 	}
 
-	defer os.RemoveAll(rootDir) // clean up
-	return nil
+	if outputDir != "" {
+		return writeAPIProxyFolder(bundleDir, outputDir)
+	}
+	return archiveBundle(bundleDir, name+".zip")
 }
 
-func GenerateAPIProxyBundleFromSwagger(name string,
+func GenerateAPIProxyBundleFromSwagger(g *genapi.Generator,
+	name string,
 	skipPolicy bool,
 	addCORS bool,
+	outputDir string,
 ) (err error) {
 	var apiProxyData, proxyEndpointData, targetEndpointData string
 
@@ -385,35 +557,35 @@ func GenerateAPIProxyBundleFromSwagger(name string,
 		return err
 	}
 
-	rootDir = path.Join(tmpDir, rootDir)
+	bundleDir := path.Join(tmpDir, rootDir)
 
 	if name == "" {
-		name = bundlegen.GetGoogleApiName()
+		name = g.GetGoogleApiName()
 	}
 
-	if err = os.Mkdir(rootDir, os.ModePerm); err != nil {
+	if err = os.Mkdir(bundleDir, os.ModePerm); err != nil {
 		return err
 	}
 
 	// write API Proxy file
-	if apiProxyData, err = apiproxy.GetAPIProxy(); err != nil {
+	if apiProxyData, err = g.GetAPIProxy(); err != nil {
 		return err
 	}
 
-	err = writeXMLData(rootDir+string(os.PathSeparator)+name+".xml", apiProxyData)
+	err = writeXMLData(bundleDir+string(os.PathSeparator)+name+".xml", apiProxyData)
 	if err != nil {
 		return err
 	}
 
-	proxiesDirPath := rootDir + string(os.PathSeparator) + "proxies"
-	policiesDirPath := rootDir + string(os.PathSeparator) + "policies"
-	targetDirPath := rootDir + string(os.PathSeparator) + "targets"
+	proxiesDirPath := bundleDir + string(os.PathSeparator) + "proxies"
+	policiesDirPath := bundleDir + string(os.PathSeparator) + "policies"
+	targetDirPath := bundleDir + string(os.PathSeparator) + "targets"
 
 	if err = os.Mkdir(proxiesDirPath, os.ModePerm); err != nil {
 		return err
 	}
 
-	if proxyEndpointData, err = proxies.GetProxyEndpoint(); err != nil {
+	if proxyEndpointData, err = g.GetProxyEndpoint(); err != nil {
 		return err
 	}
 
@@ -426,7 +598,7 @@ func GenerateAPIProxyBundleFromSwagger(name string,
 		return err
 	}
 
-	for _, targetEndpoint := range targets.TargetEndpoints {
+	for _, targetEndpoint := range g.GetTargetEndpoints() {
 		if targetEndpointData, err = target.GetTargetEndpoint(targetEndpoint); err != nil {
 			return err
 		}
@@ -441,14 +613,14 @@ func GenerateAPIProxyBundleFromSwagger(name string,
 	}
 
 	// add AM policies
-	for amPolicyName, amPolicyContent := range genapi.GetAMPolicies() {
+	for amPolicyName, amPolicyContent := range g.GetAMPolicies() {
 		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"AM-"+amPolicyName+".xml", amPolicyContent); err != nil {
 			return err
 		}
 	}
 
 	// add security policies
-	for _, securityScheme := range genapi.GetSecuritySchemesList() {
+	for _, securityScheme := range g.GetSecuritySchemesList() {
 		if securityScheme.JWTPolicy.JWTPolicyEnabled {
 			if len(securityScheme.JWTPolicy.Location) > 0 {
 				var headerName, headerValue, queryName string
@@ -493,13 +665,13 @@ func GenerateAPIProxyBundleFromSwagger(name string,
 	}
 
 	// add quota policies
-	for quotaPolicyName, quotaPolicyContent := range genapi.GetQuotaPolicies() {
+	for quotaPolicyName, quotaPolicyContent := range g.GetQuotaPolicies() {
 		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Quota-"+quotaPolicyName+".xml", quotaPolicyContent); err != nil {
 			return err
 		}
 	}
 
-	if allow := genapi.GetAllowDefinition(); allow == "configured" {
+	if allow := g.GetAllowDefinition(); allow == "configured" {
 		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Raise-Fault-Unknown-Request.xml", policies.AddRaiseFaultPolicy()); err != nil {
 			return err
 		}
@@ -512,20 +684,23 @@ func GenerateAPIProxyBundleFromSwagger(name string,
 		}
 	}
 
-	if policies.IsCopyAuthEnabled() {
+	if g.IsCopyAuthEnabled() {
 		// add AM policy
 		if err = writeXMLData(policiesDirPath+string(os.PathSeparator)+"Copy-Auth-Var.xml", policies.AddCopyAuthHeaderPolicy()); err != nil {
 			return err
 		}
 	}
 
-	if err = archiveBundle(rootDir, name+".zip"); err != nil {
+	defer os.RemoveAll(tmpDir) // clean up
+
+	if err = applyTemplates(bundleDir, name, g.GetTemplateDir()); err != nil {
 		return err
 	}
 
-	defer os.RemoveAll(rootDir) // clean up
-
-	return err
+	if outputDir != "" {
+		return writeAPIProxyFolder(bundleDir, outputDir)
+	}
+	return archiveBundle(bundleDir, name+".zip")
 }
 
 func writeXMLData(fileName string, data string) error {
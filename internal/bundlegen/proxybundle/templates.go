@@ -0,0 +1,265 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxybundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	apiproxy "internal/bundlegen/apiproxydef"
+	proxytypes "internal/bundlegen/common"
+)
+
+// StepsFileName lists the steps added to every generated API proxy, it is
+// read from the template folder
+const StepsFileName = "steps.json"
+
+// templateSteps are the steps added to the proxy and target endpoints,
+// keyed by PreFlow/Request, PreFlow/Response, PostFlow/Request or PostFlow/Response
+type templateSteps struct {
+	Proxy  map[string][]templateStep `json:"proxy,omitempty"`
+	Target map[string][]templateStep `json:"target,omitempty"`
+}
+
+type templateStep struct {
+	Name      string `json:"name"`
+	Condition string `json:"condition,omitempty"`
+	// First adds the step before the generated steps
+	First bool `json:"first,omitempty"`
+}
+
+// templateData is passed to the policy templates
+type templateData struct {
+	// APIProxy is the name of the generated API proxy
+	APIProxy string
+	// Name and Kind are the name and the root element of the policy
+	Name string
+	Kind string
+	// Default is the policy that would have been generated
+	Default string
+	// Elements holds the top level elements of the generated policy
+	Elements map[string]string
+}
+
+type policyElement struct {
+	XMLName  xml.Name
+	Elements []rawElement `xml:",any"`
+}
+
+type rawElement struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   string     `xml:",innerxml"`
+}
+
+// applyTemplates customizes the API proxy in bundleDir with the templates in
+// templateDir:
+//
+//	<Kind>.xml or <Policy name>.xml replaces the generated policies of that kind or name
+//	policies/*.xml are added to the API proxy
+//	resources/<type>/* are added to the API proxy
+//	steps.json lists the steps to add to the pre and post flows
+//
+// Templates use the text/template syntax and are passed a templateData
+func applyTemplates(bundleDir string, name string, templateDir string) (err error) {
+	if templateDir == "" {
+		return nil
+	}
+
+	apiProxyFile := filepath.Join(bundleDir, name+".xml")
+	apiProxy := apiproxy.APIProxyDef{}
+	if err = readXML(apiProxyFile, &apiProxy); err != nil {
+		return err
+	}
+
+	policiesDir := filepath.Join(bundleDir, "policies")
+	if err = os.MkdirAll(policiesDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	// override generated policies
+	generated, err := filepath.Glob(filepath.Join(policiesDir, "*.xml"))
+	if err != nil {
+		return err
+	}
+	for _, policyFile := range generated {
+		content, err := os.ReadFile(policyFile)
+		if err != nil {
+			return err
+		}
+		policy := policyElement{}
+		if err = xml.Unmarshal(content, &policy); err != nil {
+			return fmt.Errorf("unable to parse policy %s: %v", filepath.Base(policyFile), err)
+		}
+		policyName := strings.TrimSuffix(filepath.Base(policyFile), ".xml")
+		for _, templateFile := range []string{policyName + ".xml", policy.XMLName.Local + ".xml"} {
+			templatePath := filepath.Join(templateDir, templateFile)
+			if _, err = os.Stat(templatePath); err != nil {
+				continue
+			}
+			data := templateData{
+				APIProxy: name,
+				Name:     policyName,
+				Kind:     policy.XMLName.Local,
+				Default:  string(content),
+				Elements: map[string]string{},
+			}
+			for _, element := range policy.Elements {
+				raw, err := xml.Marshal(element)
+				if err != nil {
+					return err
+				}
+				data.Elements[element.XMLName.Local] = string(raw)
+			}
+			if err = renderTemplate(templatePath, policyFile, data); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	// add policies
+	extraPolicies, err := filepath.Glob(filepath.Join(templateDir, "policies", "*.xml"))
+	if err != nil {
+		return err
+	}
+	for _, templatePath := range extraPolicies {
+		policyName := strings.TrimSuffix(filepath.Base(templatePath), ".xml")
+		data := templateData{APIProxy: name, Name: policyName, Elements: map[string]string{}}
+		if err = renderTemplate(templatePath, filepath.Join(policiesDir, policyName+".xml"), data); err != nil {
+			return err
+		}
+		apiProxy.AddPolicy(policyName)
+	}
+
+	// add resources
+	resourcesDir := filepath.Join(templateDir, "resources")
+	if _, err = os.Stat(resourcesDir); err == nil {
+		err = filepath.WalkDir(resourcesDir, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(resourcesDir, filePath)
+			if err != nil {
+				return err
+			}
+			resType, resName, found := strings.Cut(filepath.ToSlash(rel), "/")
+			if !found || strings.Contains(resName, "/") {
+				return fmt.Errorf("resource %s must be in resources/<type>/", rel)
+			}
+			if !contains(apiProxy.Resources.Resource, resType+"://"+resName) {
+				apiProxy.AddResource(resName, resType)
+			}
+			return copyFile(filePath, filepath.Join(bundleDir, "resources", resType, resName))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = addTemplateSteps(bundleDir, templateDir); err != nil {
+		return err
+	}
+
+	apiProxyData, err := apiProxy.GetAPIProxy()
+	if err != nil {
+		return err
+	}
+	return writeXMLData(apiProxyFile, apiProxyData)
+}
+
+// addTemplateSteps adds the steps of steps.json to the proxy and target endpoints
+func addTemplateSteps(bundleDir string, templateDir string) (err error) {
+	data, err := os.ReadFile(filepath.Join(templateDir, StepsFileName))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	steps := templateSteps{}
+	if err = json.Unmarshal(data, &steps); err != nil {
+		return fmt.Errorf("unable to parse %s: %v", StepsFileName, err)
+	}
+
+	for endpointDir, endpointSteps := range map[string]map[string][]templateStep{
+		"proxies": steps.Proxy,
+		"targets": steps.Target,
+	} {
+		if len(endpointSteps) == 0 {
+			continue
+		}
+		endpointFiles, err := filepath.Glob(filepath.Join(bundleDir, endpointDir, "*.xml"))
+		if err != nil {
+			return err
+		}
+		for _, endpointFile := range endpointFiles {
+			e, err := readEndpoint(endpointFile)
+			if err != nil {
+				return err
+			}
+			flowSteps := e.flowSteps()
+			for flowKey, stepList := range endpointSteps {
+				if !strings.HasPrefix(flowKey, "PreFlow/") && !strings.HasPrefix(flowKey, "PostFlow/") {
+					return fmt.Errorf("invalid flow %s in %s, must be one of PreFlow/Request, "+
+						"PreFlow/Response, PostFlow/Request or PostFlow/Response", flowKey, StepsFileName)
+				}
+				flow, ok := flowSteps[flowKey]
+				if !ok {
+					return fmt.Errorf("invalid flow %s in %s", flowKey, StepsFileName)
+				}
+				var first []*proxytypes.StepDef
+				for _, step := range stepList {
+					if _, err = os.Stat(filepath.Join(bundleDir, "policies", step.Name+".xml")); err != nil {
+						return fmt.Errorf("policy %s of step in %s not found", step.Name, StepsFileName)
+					}
+					if hasStep(*flow, step.Name) {
+						continue
+					}
+					s := &proxytypes.StepDef{Name: step.Name, Condition: step.Condition}
+					if step.First {
+						first = append(first, s)
+					} else {
+						*flow = append(*flow, s)
+					}
+				}
+				*flow = append(first, *flow...)
+			}
+			if err = e.write(endpointFile); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func renderTemplate(templatePath string, outputPath string, data templateData) error {
+	tmpl, err := template.New(filepath.Base(templatePath)).Option("missingkey=error").ParseFiles(templatePath)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err = tmpl.Execute(&out, data); err != nil {
+		return err
+	}
+	return writeXMLData(outputPath, out.String())
+}
@@ -0,0 +1,289 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxybundle
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// xmlNode is an element or a comment of an endpoint file. Endpoints are merged
+// on this generic tree instead of the bundle types so elements the generator
+// does not know about (fault rules, SSL info, properties) survive a merge
+type xmlNode struct {
+	// Name is the element name, empty for comments
+	Name  string
+	Attrs []xml.Attr
+	// Text is the trimmed character data of the element, or the comment
+	Text     string
+	Children []*xmlNode
+}
+
+func parseXMLNode(data []byte) (root *xmlNode, err error) {
+	var stack []*xmlNode
+
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{Name: qualifiedName(t.Name), Attrs: append([]xml.Attr{}, t.Attr...)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("unexpected end element %s", qualifiedName(t.Name))
+			}
+			node := stack[len(stack)-1]
+			node.Text = strings.TrimSpace(node.Text)
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		case xml.Comment:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, &xmlNode{Text: strings.TrimSpace(string(t))})
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no root element found")
+	}
+	return root, nil
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+// String returns the node indented like the generated endpoints
+func (n *xmlNode) String() string {
+	b := &strings.Builder{}
+	n.write(b, "")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (n *xmlNode) write(b *strings.Builder, indent string) {
+	if n.Name == "" {
+		fmt.Fprintf(b, "%s<!-- %s -->\n", indent, n.Text)
+		return
+	}
+	b.WriteString(indent + "<" + n.Name)
+	for _, attr := range n.Attrs {
+		fmt.Fprintf(b, " %s=\"%s\"", qualifiedName(attr.Name), escapeXML(attr.Value, true))
+	}
+	b.WriteString(">")
+	if len(n.Children) == 0 {
+		b.WriteString(escapeXML(n.Text, false) + "</" + n.Name + ">\n")
+		return
+	}
+	b.WriteString("\n")
+	if n.Text != "" {
+		b.WriteString(indent + " " + escapeXML(n.Text, false) + "\n")
+	}
+	for _, child := range n.Children {
+		child.write(b, indent+" ")
+	}
+	b.WriteString(indent + "</" + n.Name + ">\n")
+}
+
+func escapeXML(s string, attr bool) string {
+	s = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+	if attr {
+		s = strings.ReplaceAll(s, "\"", "&quot;")
+	}
+	return s
+}
+
+// key identifies the node among its siblings: flows, route rules and fault
+// rules by their name attribute, steps by their policy name, comments by their
+// text and other elements by their position
+func (n *xmlNode) key(position int) string {
+	if n.Name == "" {
+		return "#comment(" + n.Text + ")"
+	}
+	for _, attr := range n.Attrs {
+		if attr.Name.Local == "name" {
+			return n.Name + "[" + attr.Value + "]"
+		}
+	}
+	if n.Name == "Step" {
+		for _, child := range n.Children {
+			if child.Name == "Name" {
+				return n.Name + "[" + child.Text + "]"
+			}
+		}
+	}
+	return fmt.Sprintf("%s[%d]", n.Name, position)
+}
+
+// keyedChildren returns the keys of the children in order and the children by key
+func (n *xmlNode) keyedChildren() (keys []string, children map[string]*xmlNode) {
+	children = map[string]*xmlNode{}
+	if n == nil {
+		return nil, children
+	}
+	positions := map[string]int{}
+	for _, child := range n.Children {
+		k := child.key(positions[child.Name])
+		positions[child.Name]++
+		// the same step can appear twice in a flow with different conditions
+		for base, i := k, 2; children[k] != nil; i++ {
+			k = fmt.Sprintf("%s#%d", base, i)
+		}
+		keys = append(keys, k)
+		children[k] = child
+	}
+	return keys, children
+}
+
+func (n *xmlNode) equal(o *xmlNode) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if n.Name != o.Name || !n.sameHeader(o) || len(n.Children) != len(o.Children) {
+		return false
+	}
+	for i := range n.Children {
+		if !n.Children[i].equal(o.Children[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameHeader compares the attributes and text of two nodes
+func (n *xmlNode) sameHeader(o *xmlNode) bool {
+	if n.Text != o.Text || len(n.Attrs) != len(o.Attrs) {
+		return false
+	}
+	attrs := func(node *xmlNode) []string {
+		list := []string{}
+		for _, attr := range node.Attrs {
+			list = append(list, qualifiedName(attr.Name)+"="+attr.Value)
+		}
+		sort.Strings(list)
+		return list
+	}
+	nAttrs, oAttrs := attrs(n), attrs(o)
+	for i := range nAttrs {
+		if nAttrs[i] != oAttrs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeXMLNode merges the local and the newly generated versions of a node
+// against the version generated previously (base, nil when unknown). Changes on
+// one side only are applied; when both sides changed the same element the local
+// version is kept and a conflict is reported
+func mergeXMLNode(path string, base, local, generated *xmlNode, conflicts *[]string) *xmlNode {
+	switch {
+	case local.equal(generated):
+		return local
+	case base != nil && local.equal(base):
+		return generated
+	case base != nil && generated.equal(base):
+		return local
+	}
+
+	merged := &xmlNode{Name: local.Name}
+	switch {
+	case local.sameHeader(generated), base != nil && generated.sameHeader(base):
+		merged.Attrs, merged.Text = local.Attrs, local.Text
+	case base != nil && local.sameHeader(base):
+		merged.Attrs, merged.Text = generated.Attrs, generated.Text
+	default:
+		merged.Attrs, merged.Text = local.Attrs, local.Text
+		*conflicts = append(*conflicts, fmt.Sprintf("%s modified locally and by the spec, kept the local version", path))
+	}
+
+	merged.Children = mergeXMLChildren(path, base, local, generated, conflicts)
+	return merged
+}
+
+// mergeXMLChildren merges the children of a node. The generated order is kept and
+// children added by hand are inserted after their closest local predecessor
+func mergeXMLChildren(path string, base, local, generated *xmlNode, conflicts *[]string) (children []*xmlNode) {
+	_, baseChildren := base.keyedChildren()
+	localKeys, localChildren := local.keyedChildren()
+	generatedKeys, generatedChildren := generated.keyedChildren()
+
+	var keys []string
+	for _, k := range generatedKeys {
+		childPath := path + "/" + k
+		b, l, g := baseChildren[k], localChildren[k], generatedChildren[k]
+		switch {
+		case l != nil:
+			children = append(children, mergeXMLNode(childPath, b, l, g, conflicts))
+		case b == nil:
+			// added by the spec
+			children = append(children, g)
+		case b.equal(g):
+			// removed by hand
+			continue
+		default:
+			*conflicts = append(*conflicts, fmt.Sprintf("%s removed locally but modified by the spec, kept the generated version", childPath))
+			children = append(children, g)
+		}
+		keys = append(keys, k)
+	}
+
+	for index, k := range localKeys {
+		if generatedChildren[k] != nil {
+			continue
+		}
+		b, l := baseChildren[k], localChildren[k]
+		if b != nil {
+			if b.equal(l) {
+				// no longer generated
+				continue
+			}
+			*conflicts = append(*conflicts, fmt.Sprintf("%s/%s modified locally but no longer generated, kept the local version", path, k))
+		}
+		position := 0
+		for p := index - 1; p >= 0 && position == 0; p-- {
+			for i, key := range keys {
+				if key == localKeys[p] {
+					position = i + 1
+					break
+				}
+			}
+		}
+		keys = append(keys[:position], append([]string{k}, keys[position:]...)...)
+		children = append(children[:position], append([]*xmlNode{l}, children[position:]...)...)
+	}
+	return children
+}
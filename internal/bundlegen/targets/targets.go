@@ -22,14 +22,15 @@ import (
 	proxytypes "internal/bundlegen/common"
 )
 
-type targetEndpointDef struct {
-	XMLName              xml.Name                `xml:"TargetEndpoint"`
-	Name                 string                  `xml:"name,attr"`
-	FaultRules           string                  `xml:"FaultRules,omitempty"`
-	PreFlow              proxytypes.PreFlowDef   `xml:"PreFlow,omitempty"`
-	PostFlow             proxytypes.PostFlowDef  `xml:"PostFlow,omitempty"`
-	Flows                proxytypes.FlowsDef     `xml:"Flows,omitempty"`
-	HTTPTargetConnection httpTargetConnectionDef `xml:"HTTPTargetConnection,omitempty"`
+// TargetEndpointDef is a TargetEndpoint of a bundle
+type TargetEndpointDef struct {
+	XMLName              xml.Name                  `xml:"TargetEndpoint"`
+	Name                 string                    `xml:"name,attr"`
+	FaultRules           *proxytypes.FaultRulesDef `xml:"FaultRules,omitempty"`
+	PreFlow              proxytypes.PreFlowDef     `xml:"PreFlow,omitempty"`
+	PostFlow             proxytypes.PostFlowDef    `xml:"PostFlow,omitempty"`
+	Flows                proxytypes.FlowsDef       `xml:"Flows,omitempty"`
+	HTTPTargetConnection httpTargetConnectionDef   `xml:"HTTPTargetConnection,omitempty"`
 }
 
 type property struct {
@@ -45,10 +46,23 @@ type properties struct {
 
 type httpTargetConnectionDef struct {
 	Authentication *authenticationDef `xml:"Authentication"`
-	URL            string             `xml:"URL"`
+	URL            string             `xml:"URL,omitempty"`
+	LoadBalancer   *loadBalancerDef   `xml:"LoadBalancer,omitempty"`
+	Path           string             `xml:"Path,omitempty"`
 	Properties     properties         `xml:"Properties"`
 }
 
+type loadBalancerDef struct {
+	XMLName   xml.Name    `xml:"LoadBalancer"`
+	Algorithm string      `xml:"Algorithm,omitempty"`
+	Server    []serverDef `xml:"Server"`
+}
+
+type serverDef struct {
+	XMLName xml.Name `xml:"Server"`
+	Name    string   `xml:"name,attr"`
+}
+
 type authenticationDef struct {
 	XMLName           xml.Name              `xml:"Authentication"`
 	GoogleAccessToken *googleAccessTokenDef `xml:"GoogleAccessToken,omitempty"`
@@ -82,19 +96,20 @@ var integrationEndpoint = `<?xml version="1.0" encoding="UTF-8" standalone="yes"
 </IntegrationEndpoint>
 `
 
-var TargetEndpoints []targetEndpointDef
+// TargetEndpoints holds the TargetEndpoints of a bundle
+type TargetEndpoints []TargetEndpointDef
 
-func AddStepToPreFlowRequest(name string, targetEndpointName string) {
-	for _, targetEndpoint := range TargetEndpoints {
-		if targetEndpoint.Name == targetEndpointName {
+func (targetEndpoints TargetEndpoints) AddStepToPreFlowRequest(name string, targetEndpointName string) {
+	for index := range targetEndpoints {
+		if targetEndpoints[index].Name == targetEndpointName {
 			step := proxytypes.StepDef{}
 			step.Name = name
-			targetEndpoint.PreFlow.Request.Step = append(targetEndpoint.PreFlow.Request.Step, &step)
+			targetEndpoints[index].PreFlow.Request.Step = append(targetEndpoints[index].PreFlow.Request.Step, &step)
 		}
 	}
 }
 
-func GetTargetEndpoint(targetEndpoint targetEndpointDef) (string, error) {
+func GetTargetEndpoint(targetEndpoint TargetEndpointDef) (string, error) {
 	targetBody, err := xml.MarshalIndent(targetEndpoint, "", " ")
 	if err != nil {
 		return "", nil
@@ -102,8 +117,8 @@ func GetTargetEndpoint(targetEndpoint targetEndpointDef) (string, error) {
 	return string(targetBody), nil
 }
 
-func NewTargetEndpoint(name string, endpoint string, oasGoogleAcessTokenScopeLiteral string, oasGoogleIdTokenAudLiteral string, oasGoogleIdTokenAudRef string) {
-	targetEndpoint := targetEndpointDef{}
+func (targetEndpoints *TargetEndpoints) NewTargetEndpoint(name string, endpoint string, oasGoogleAcessTokenScopeLiteral string, oasGoogleIdTokenAudLiteral string, oasGoogleIdTokenAudRef string) {
+	targetEndpoint := TargetEndpointDef{}
 	targetEndpoint.Name = name
 	targetEndpoint.PreFlow.Name = "PreFlow"
 	targetEndpoint.PostFlow.Name = "PostFlow"
@@ -132,39 +147,76 @@ func NewTargetEndpoint(name string, endpoint string, oasGoogleAcessTokenScopeLit
 	} else {
 		targetEndpoint.HTTPTargetConnection.Authentication = nil
 	}
-	TargetEndpoints = append(TargetEndpoints, targetEndpoint)
+	*targetEndpoints = append(*targetEndpoints, targetEndpoint)
+}
+
+// CopyTargetEndpoint adds a TargetEndpoint with the connection of an existing TargetEndpoint
+func (targetEndpoints *TargetEndpoints) CopyTargetEndpoint(endpointName string, name string) error {
+	for _, targetEndpoint := range *targetEndpoints {
+		if targetEndpoint.Name == endpointName {
+			copyEndpoint := TargetEndpointDef{}
+			copyEndpoint.Name = name
+			copyEndpoint.PreFlow.Name = "PreFlow"
+			copyEndpoint.PostFlow.Name = "PostFlow"
+			copyEndpoint.HTTPTargetConnection = targetEndpoint.HTTPTargetConnection
+			copyEndpoint.HTTPTargetConnection.Properties.Property = append([]property{},
+				targetEndpoint.HTTPTargetConnection.Properties.Property...)
+			*targetEndpoints = append(*targetEndpoints, copyEndpoint)
+			return nil
+		}
+	}
+	return fmt.Errorf("could not copy targetendpoint, targetendpoint %s not found", endpointName)
+}
+
+// SetLoadBalancer replaces the URL of the TargetEndpoint with a LoadBalancer over target servers
+func (targetEndpoints TargetEndpoints) SetLoadBalancer(endpointName string, servers []string, path string) {
+	for index := range targetEndpoints {
+		if targetEndpoints[index].Name == endpointName {
+			loadBalancer := &loadBalancerDef{}
+			if len(servers) > 1 {
+				loadBalancer.Algorithm = "RoundRobin"
+			}
+			for _, server := range servers {
+				loadBalancer.Server = append(loadBalancer.Server, serverDef{Name: server})
+			}
+			targetEndpoints[index].HTTPTargetConnection.URL = ""
+			targetEndpoints[index].HTTPTargetConnection.LoadBalancer = loadBalancer
+			targetEndpoints[index].HTTPTargetConnection.Path = path
+			return
+		}
+	}
 }
 
-func IsExists(endpointName string) bool {
-	for index := range TargetEndpoints {
-		if TargetEndpoints[index].Name == endpointName {
+func (targetEndpoints TargetEndpoints) IsExists(endpointName string) bool {
+	for index := range targetEndpoints {
+		if targetEndpoints[index].Name == endpointName {
 			return true
 		}
 	}
 	return false
 }
 
-func AddTargetEndpointProperty(endpointName string, propertyName string, propertyValue string) {
+func (targetEndpoints TargetEndpoints) AddTargetEndpointProperty(endpointName string, propertyName string, propertyValue string) {
 	property := property{}
 	property.Name = propertyName
 	property.Value = propertyValue
 
-	for index := range TargetEndpoints {
-		if TargetEndpoints[index].Name == endpointName {
-			TargetEndpoints[index].HTTPTargetConnection.Properties.Property = append(TargetEndpoints[index].HTTPTargetConnection.Properties.Property, property)
+	for index := range targetEndpoints {
+		if targetEndpoints[index].Name == endpointName {
+			targetEndpoints[index].HTTPTargetConnection.Properties.Property = append(targetEndpoints[index].HTTPTargetConnection.Properties.Property, property)
 			return
 		}
 	}
 }
 
-func AddStepToFlowRequest(targetEndpointName string, name string, flowName string) error {
-	for index := range TargetEndpoints {
-		if TargetEndpoints[index].Name == targetEndpointName {
-			for flowKey, flow := range TargetEndpoints[index].Flows.Flow {
+func (targetEndpoints TargetEndpoints) AddStepToFlowRequest(targetEndpointName string, name string, flowName string) error {
+	for index := range targetEndpoints {
+		if targetEndpoints[index].Name == targetEndpointName {
+			for flowKey, flow := range targetEndpoints[index].Flows.Flow {
 				if flow.Name == flowName {
 					step := proxytypes.StepDef{}
 					step.Name = name
-					TargetEndpoints[index].Flows.Flow[flowKey].Request.Step = append(TargetEndpoints[index].Flows.Flow[flowKey].Request.Step, &step)
+					targetEndpoints[index].Flows.Flow[flowKey].Request.Step = append(targetEndpoints[index].Flows.Flow[flowKey].Request.Step, &step)
 					return nil
 				}
 			}
@@ -173,7 +225,7 @@ func AddStepToFlowRequest(targetEndpointName string, name string, flowName strin
 	return fmt.Errorf("could not add step, targetendpoint %s not found", targetEndpointName)
 }
 
-func AddFlow(targetEndpointName string, operationId string, keyPath string, method string, description string) error {
+func (targetEndpoints TargetEndpoints) AddFlow(targetEndpointName string, operationId string, keyPath string, method string, description string) error {
 	flow := proxytypes.FlowDef{}
 	flow.Name = operationId
 	if description != "" {
@@ -182,9 +234,9 @@ func AddFlow(targetEndpointName string, operationId string, keyPath string, meth
 	if keyPath != "" && method != "" {
 		flow.Condition.ConditionData = "(proxy.pathsuffix MatchesPath \"" + keyPath + "\") and (request.verb = \"" + strings.ToUpper(method) + "\")"
 	}
-	for index := range TargetEndpoints {
-		if TargetEndpoints[index].Name == targetEndpointName {
-			TargetEndpoints[index].Flows.Flow = append(TargetEndpoints[index].Flows.Flow, flow)
+	for index := range targetEndpoints {
+		if targetEndpoints[index].Name == targetEndpointName {
+			targetEndpoints[index].Flows.Flow = append(targetEndpoints[index].Flows.Flow, flow)
 			return nil
 		}
 	}
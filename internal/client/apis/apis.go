@@ -156,6 +156,11 @@ func FetchProxy(name string, revision int) (err error) {
 	return apiclient.FetchBundle("apis", "", name, strconv.Itoa(revision), true)
 }
 
+// FetchProxyToFile downloads a proxy revision to outputFile
+func FetchProxyToFile(name string, revision int, outputFile string) (err error) {
+	return apiclient.DownloadBundle("apis", name, strconv.Itoa(revision), outputFile)
+}
+
 // GetProxy
 func GetProxy(name string, revision int) (respBody []byte, err error) {
 	u, _ := url.Parse(apiclient.BaseURL)
@@ -0,0 +1,430 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+)
+
+// AppGroups holds a list of app groups
+type AppGroups struct {
+	AppGroups     []AppGroup `json:"appGroups,omitempty"`
+	NextPageToken string     `json:"nextPageToken,omitempty"`
+}
+
+// AppGroup is a group of apps owned by a team instead of a developer
+type AppGroup struct {
+	Name           string      `json:"name,omitempty"`
+	DisplayName    string      `json:"displayName,omitempty"`
+	ChannelURI     string      `json:"channelUri,omitempty"`
+	ChannelID      string      `json:"channelId,omitempty"`
+	Status         string      `json:"status,omitempty"`
+	Attributes     []Attribute `json:"attributes,omitempty"`
+	AppGroupID     string      `json:"appGroupId,omitempty"`
+	Organization   string      `json:"organization,omitempty"`
+	CreatedAt      string      `json:"createdAt,omitempty"`
+	LastModifiedAt string      `json:"lastModifiedAt,omitempty"`
+	// Apps are only set in exported files
+	Apps []App `json:"apps,omitempty"`
+}
+
+// Attribute to used to hold custom attributes for entities
+type Attribute struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// maxPageSize is the largest page the app groups API returns
+const maxPageSize = 1000
+
+// Create
+func Create(name string, displayName string, channelURI string, channelID string, attrs map[string]string) (respBody []byte, err error) {
+	appGroup := AppGroup{
+		Name:        name,
+		DisplayName: displayName,
+		ChannelURI:  channelURI,
+		ChannelID:   channelID,
+		Attributes:  toAttributes(attrs),
+	}
+	return create(appGroup)
+}
+
+// Get
+func Get(name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", name)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// Update changes the display name, channel and attributes of the app group.
+// Empty values are not changed
+func Update(name string, displayName string, channelURI string, channelID string, attrs map[string]string) (respBody []byte, err error) {
+	var appGroup AppGroup
+
+	if appGroup, err = getAppGroup(name); err != nil {
+		return nil, err
+	}
+	if displayName != "" {
+		appGroup.DisplayName = displayName
+	}
+	if channelURI != "" {
+		appGroup.ChannelURI = channelURI
+	}
+	if channelID != "" {
+		appGroup.ChannelID = channelID
+	}
+	for k, v := range attrs {
+		appGroup.Attributes = setAttribute(appGroup.Attributes, k, v)
+	}
+	return update(appGroup)
+}
+
+// Delete
+func Delete(name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", name)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// List
+func List(pageSize int, pageToken string, filter string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups")
+	q := u.Query()
+	if pageSize != -1 {
+		q.Set("pageSize", fmt.Sprint(pageSize))
+	}
+	if pageToken != "" {
+		q.Set("pageToken", pageToken)
+	}
+	if filter != "" {
+		q.Set("filter", filter)
+	}
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// ListAll returns every app group in the org, following the page tokens
+func ListAll(filter string) (respBody []byte, err error) {
+	apiclient.ClientPrintHttpResponse.Set(false)
+	respBody, err = listAll(filter)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return nil, err
+	}
+	return respBody, apiclient.PrettyPrint(respBody)
+}
+
+// Manage sets the status of the app group, action is active or inactive
+func Manage(name string, action string) (respBody []byte, err error) {
+	if action != "active" && action != "inactive" {
+		return nil, fmt.Errorf("invalid action. action must be active or inactive")
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", name)
+	q := u.Query()
+	q.Set("action", action)
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String(), "", "PUT", "application/octet-stream")
+	return respBody, err
+}
+
+// ListAttributes
+func ListAttributes(name string) (respBody []byte, err error) {
+	var appGroup AppGroup
+
+	if appGroup, err = getAppGroup(name); err != nil {
+		return nil, err
+	}
+	if respBody, err = json.Marshal(map[string][]Attribute{"attribute": appGroup.Attributes}); err != nil {
+		return nil, err
+	}
+	return respBody, apiclient.PrettyPrint(respBody)
+}
+
+// SetAttribute creates or updates an attribute of the app group
+func SetAttribute(name string, key string, value string) (respBody []byte, err error) {
+	var appGroup AppGroup
+
+	if appGroup, err = getAppGroup(name); err != nil {
+		return nil, err
+	}
+	appGroup.Attributes = setAttribute(appGroup.Attributes, key, value)
+	return update(appGroup)
+}
+
+// DeleteAttribute removes an attribute of the app group
+func DeleteAttribute(name string, key string) (respBody []byte, err error) {
+	var appGroup AppGroup
+
+	if appGroup, err = getAppGroup(name); err != nil {
+		return nil, err
+	}
+	attributes := []Attribute{}
+	for _, a := range appGroup.Attributes {
+		if a.Name != key {
+			attributes = append(attributes, a)
+		}
+	}
+	if len(attributes) == len(appGroup.Attributes) {
+		return nil, fmt.Errorf("attribute %s not found in app group %s", key, name)
+	}
+	appGroup.Attributes = attributes
+	return update(appGroup)
+}
+
+// Export returns the app groups with their apps and credentials
+func Export(conn int) (respBody []byte, err error) {
+	var appGroupsBody []byte
+	var mu sync.Mutex
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if appGroupsBody, err = listAll(""); err != nil {
+		return nil, err
+	}
+	appGroups := AppGroups{}
+	if err = json.Unmarshal(appGroupsBody, &appGroups); err != nil {
+		return nil, err
+	}
+
+	numEntities := len(appGroups.AppGroups)
+	clilog.Debug.Printf("Exporting apps of %d app groups with %d connections\n", numEntities, conn)
+
+	jobChan := make(chan int)
+	errs := []string{}
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < conn; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobChan {
+				apps, err := listAllApps(appGroups.AppGroups[i].Name)
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, err.Error())
+				} else {
+					appGroups.AppGroups[i].Apps = apps
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range appGroups.AppGroups {
+		jobChan <- i
+	}
+	close(jobChan)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+
+	return json.Marshal(appGroups)
+}
+
+// Import creates the app groups, their apps and credentials. The consumer keys
+// and secrets in the file are preserved
+func Import(conn int, filePath string) error {
+	entities, err := ReadAppGroupsFile(filePath)
+	if err != nil {
+		clilog.Error.Println("Error reading file: ", err)
+		return err
+	}
+
+	numEntities := len(entities.AppGroups)
+	clilog.Debug.Printf("Found %d app groups in the file\n", numEntities)
+	clilog.Debug.Printf("Create app groups with %d connections\n", conn)
+
+	jobChan := make(chan AppGroup)
+	errChan := make(chan error)
+
+	fanOutWg := sync.WaitGroup{}
+	fanInWg := sync.WaitGroup{}
+
+	errs := []string{}
+	fanInWg.Add(1)
+	go func() {
+		defer fanInWg.Done()
+		for {
+			newErr, ok := <-errChan
+			if !ok {
+				return
+			}
+			errs = append(errs, newErr.Error())
+		}
+	}()
+
+	for i := 0; i < conn; i++ {
+		fanOutWg.Add(1)
+		go createAsyncAppGroup(&fanOutWg, jobChan, errChan)
+	}
+
+	for _, entity := range entities.AppGroups {
+		jobChan <- entity
+	}
+	close(jobChan)
+	fanOutWg.Wait()
+	close(errChan)
+	fanInWg.Wait()
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// ReadAppGroupsFile
+func ReadAppGroupsFile(filePath string) (AppGroups, error) {
+	appGroups := AppGroups{}
+
+	jsonFile, err := os.Open(filePath)
+	if err != nil {
+		return appGroups, err
+	}
+
+	defer jsonFile.Close()
+
+	byteValue, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return appGroups, err
+	}
+
+	err = json.Unmarshal(byteValue, &appGroups)
+
+	if err != nil {
+		return appGroups, err
+	}
+
+	return appGroups, nil
+}
+
+func createAsyncAppGroup(wg *sync.WaitGroup, jobs <-chan AppGroup, errs chan<- error) {
+	defer wg.Done()
+
+	for {
+		job, ok := <-jobs
+		if !ok {
+			return
+		}
+		apps := job.Apps
+
+		// remove the fields set by the server
+		job.Apps = nil
+		job.AppGroupID, job.Organization, job.CreatedAt, job.LastModifiedAt = "", "", "", ""
+
+		if _, err := create(job); err != nil {
+			errs <- err
+			continue
+		}
+		for _, app := range apps {
+			if err := importApp(job.Name, app); err != nil {
+				errs <- fmt.Errorf("app %s in app group %s: %v", app.Name, job.Name, err)
+			}
+		}
+		clilog.Debug.Printf("Completed entity: %s", job.Name)
+	}
+}
+
+func create(appGroup AppGroup) (respBody []byte, err error) {
+	var payload []byte
+
+	if payload, err = json.Marshal(appGroup); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups")
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+func update(appGroup AppGroup) (respBody []byte, err error) {
+	var payload []byte
+
+	if payload, err = json.Marshal(appGroup); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup.Name)
+	respBody, err = apiclient.HttpClient(u.String(), string(payload), "PUT")
+	return respBody, err
+}
+
+func getAppGroup(name string) (appGroup AppGroup, err error) {
+	var respBody []byte
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if respBody, err = Get(name); err != nil {
+		return appGroup, err
+	}
+	err = json.Unmarshal(respBody, &appGroup)
+	return appGroup, err
+}
+
+func listAll(filter string) (respBody []byte, err error) {
+	var total int
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups")
+	if filter != "" {
+		q := u.Query()
+		q.Set("filter", filter)
+		u.RawQuery = q.Encode()
+	}
+
+	pager := apiclient.NewPager(u.String(), apiclient.TokenPage, maxPageSize, "appGroups", "")
+	if respBody, total, err = apiclient.ListAll(pager); err != nil {
+		return nil, err
+	}
+	clilog.Info.Printf("Fetched %d app groups in %d pages\n", total, pager.Pages())
+	return respBody, nil
+}
+
+func toAttributes(attrs map[string]string) (attributes []Attribute) {
+	for k, v := range attrs {
+		attributes = append(attributes, Attribute{Name: k, Value: v})
+	}
+	return attributes
+}
+
+func setAttribute(attributes []Attribute, key string, value string) []Attribute {
+	for i, a := range attributes {
+		if a.Name == key {
+			attributes[i].Value = value
+			return attributes
+		}
+	}
+	return append(attributes, Attribute{Name: key, Value: value})
+}
@@ -0,0 +1,275 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+)
+
+// App is an app owned by an app group
+type App struct {
+	Name           string       `json:"name,omitempty"`
+	AppID          string       `json:"appId,omitempty"`
+	AppGroup       string       `json:"appGroup,omitempty"`
+	APIProducts    []string     `json:"apiProducts,omitempty"`
+	Attributes     []Attribute  `json:"attributes,omitempty"`
+	CallbackURL    string       `json:"callbackUrl,omitempty"`
+	KeyExpiresIn   string       `json:"keyExpiresIn,omitempty"`
+	Scopes         []string     `json:"scopes,omitempty"`
+	Status         string       `json:"status,omitempty"`
+	Credentials    []Credential `json:"credentials,omitempty"`
+	CreatedAt      string       `json:"createdAt,omitempty"`
+	LastModifiedAt string       `json:"lastModifiedAt,omitempty"`
+}
+
+// Credential is a key of an app group app
+type Credential struct {
+	ConsumerKey      string       `json:"consumerKey,omitempty"`
+	ConsumerSecret   string       `json:"consumerSecret,omitempty"`
+	APIProducts      []apiProduct `json:"apiProducts,omitempty"`
+	Attributes       []Attribute  `json:"attributes,omitempty"`
+	Scopes           []string     `json:"scopes,omitempty"`
+	Status           string       `json:"status,omitempty"`
+	ExpiresAt        string       `json:"expiresAt,omitempty"`
+	ExpiresInSeconds string       `json:"expiresInSeconds,omitempty"`
+	IssuedAt         string       `json:"issuedAt,omitempty"`
+}
+
+type apiProduct struct {
+	Name   string `json:"apiproduct,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+type apps struct {
+	AppGroupApps  []App  `json:"appGroupApps,omitempty"`
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// CreateApp
+func CreateApp(appGroup string, name string, expires string, callback string, apiProducts []string, scopes []string, attrs map[string]string) (respBody []byte, err error) {
+	app := App{
+		Name:         name,
+		APIProducts:  apiProducts,
+		CallbackURL:  callback,
+		KeyExpiresIn: expires,
+		Scopes:       scopes,
+		Attributes:   toAttributes(attrs),
+	}
+	return createApp(appGroup, app)
+}
+
+// GetApp
+func GetApp(appGroup string, name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", name)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// UpdateApp changes the callback, scopes and attributes of the app. Empty values are not changed
+func UpdateApp(appGroup string, name string, callback string, scopes []string, attrs map[string]string) (respBody []byte, err error) {
+	var respApp []byte
+	var payload []byte
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	respApp, err = GetApp(appGroup, name)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{}
+	if err = json.Unmarshal(respApp, &app); err != nil {
+		return nil, err
+	}
+	// keys are managed with the keys api
+	app.Credentials = nil
+
+	if callback != "" {
+		app.CallbackURL = callback
+	}
+	if len(scopes) > 0 {
+		app.Scopes = scopes
+	}
+	for k, v := range attrs {
+		app.Attributes = setAttribute(app.Attributes, k, v)
+	}
+
+	if payload, err = json.Marshal(app); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", name)
+	respBody, err = apiclient.HttpClient(u.String(), string(payload), "PUT")
+	return respBody, err
+}
+
+// DeleteApp
+func DeleteApp(appGroup string, name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", name)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// ListApps
+func ListApps(appGroup string, pageSize int, pageToken string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps")
+	q := u.Query()
+	if pageSize != -1 {
+		q.Set("pageSize", fmt.Sprint(pageSize))
+	}
+	if pageToken != "" {
+		q.Set("pageToken", pageToken)
+	}
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// ManageApp approves or revokes all the keys of the app
+func ManageApp(appGroup string, name string, action string) (respBody []byte, err error) {
+	if action != "revoke" && action != "approve" {
+		return nil, fmt.Errorf("invalid action. action must be revoke or approve")
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", name)
+	q := u.Query()
+	q.Set("action", action)
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String(), "", "PUT", "application/octet-stream")
+	return respBody, err
+}
+
+func createApp(appGroup string, app App) (respBody []byte, err error) {
+	var payload []byte
+
+	if payload, err = json.Marshal(app); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps")
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+func listAllApps(appGroup string) (appList []App, err error) {
+	var respBody []byte
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps")
+
+	pager := apiclient.NewPager(u.String(), apiclient.TokenPage, maxPageSize, "appGroupApps", "")
+	if respBody, _, err = apiclient.ListAll(pager); err != nil {
+		return nil, err
+	}
+	entities := apps{}
+	if err = json.Unmarshal(respBody, &entities); err != nil {
+		return nil, err
+	}
+	clilog.Debug.Printf("Found %d apps in app group %s\n", len(entities.AppGroupApps), appGroup)
+	return entities.AppGroupApps, nil
+}
+
+// importApp creates the app and its credentials with the same consumer keys and secrets
+func importApp(appGroup string, app App) (err error) {
+	var respBody []byte
+
+	// importing an app will be a two step process.
+	// 1. create the app without the credential
+	// 2. create/import the credential
+	credentials := app.Credentials
+	app.Credentials = nil
+	app.AppID, app.AppGroup, app.CreatedAt, app.LastModifiedAt = "", "", "", ""
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if respBody, err = createApp(appGroup, app); err != nil {
+		return err
+	}
+
+	// delete the auto-generated key
+	newApp := App{}
+	if err = json.Unmarshal(respBody, &newApp); err != nil {
+		return err
+	}
+	for _, c := range newApp.Credentials {
+		if _, err = DeleteKey(appGroup, app.Name, c.ConsumerKey); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for _, credential := range credentials {
+		var products []string
+		for _, p := range credential.APIProducts {
+			products = append(products, p.Name)
+		}
+		expiresInSeconds, expired := keyExpiresInSeconds(credential, now)
+		if expired {
+			clilog.Warning.Printf("Key %s of app %s has expired, skipping\n", credential.ConsumerKey, app.Name)
+			continue
+		}
+		if _, err = CreateKey(appGroup, app.Name, credential.ConsumerKey, credential.ConsumerSecret,
+			products, credential.Scopes, expiresInSeconds); err != nil {
+			return err
+		}
+		if credential.Status == "revoked" {
+			if _, err = ManageKey(appGroup, app.Name, credential.ConsumerKey, "revoke"); err != nil {
+				return err
+			}
+		}
+		for _, p := range credential.APIProducts {
+			if p.Status == "revoked" {
+				if _, err = ManageKeyProduct(appGroup, app.Name, credential.ConsumerKey, p.Name, "revoke"); err != nil {
+					return err
+				}
+			}
+		}
+		if len(products) == 0 {
+			clilog.Warning.Println("NOTE: apiProducts are not associated with the app")
+		}
+	}
+	return nil
+}
+
+// keyExpiresInSeconds returns the remaining lifetime of an exported key. The
+// export has the expiry time in milliseconds, -1 when the key never expires
+func keyExpiresInSeconds(credential Credential, now time.Time) (expiresInSeconds string, expired bool) {
+	if credential.ExpiresInSeconds != "" {
+		return credential.ExpiresInSeconds, false
+	}
+	expiresAt, err := strconv.ParseInt(credential.ExpiresAt, 10, 64)
+	if err != nil || expiresAt <= 0 {
+		return "", false
+	}
+	remaining := time.UnixMilli(expiresAt).Sub(now)
+	if remaining < time.Second {
+		return "", true
+	}
+	return strconv.FormatInt(int64(remaining/time.Second), 10), false
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appgroups
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+
+	"internal/apiclient"
+)
+
+type createKeyRequest struct {
+	ConsumerKey      string   `json:"consumerKey,omitempty"`
+	ConsumerSecret   string   `json:"consumerSecret,omitempty"`
+	Scopes           []string `json:"scopes,omitempty"`
+	ExpiresInSeconds string   `json:"expiresInSeconds,omitempty"`
+}
+
+type updateKeyRequest struct {
+	Action      string   `json:"action,omitempty"`
+	APIProducts []string `json:"apiProducts,omitempty"`
+}
+
+// CreateKey imports a consumer key and secret into the app and adds the api products to it
+func CreateKey(appGroup string, appName string, consumerKey string, consumerSecret string, apiProducts []string, scopes []string, expiresInSeconds string) (respBody []byte, err error) {
+	var payload []byte
+
+	key := createKeyRequest{
+		ConsumerKey:      consumerKey,
+		ConsumerSecret:   consumerSecret,
+		Scopes:           scopes,
+		ExpiresInSeconds: expiresInSeconds,
+	}
+	if payload, err = json.Marshal(key); err != nil {
+		return nil, err
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", appName, "keys")
+
+	if len(apiProducts) > 0 {
+		apiclient.ClientPrintHttpResponse.Set(false)
+	}
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+
+	if err != nil {
+		return respBody, err
+	}
+
+	// since the API does not support adding products when creating a key, use a second API call to add products
+	if len(apiProducts) > 0 {
+		apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+		respBody, err = AddKeyProducts(appGroup, appName, consumerKey, apiProducts)
+	}
+
+	return respBody, err
+}
+
+// GetKey
+func GetKey(appGroup string, appName string, consumerKey string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", appName, "keys", consumerKey)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// DeleteKey
+func DeleteKey(appGroup string, appName string, consumerKey string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", appName, "keys", consumerKey)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// ManageKey approves or revokes a key
+func ManageKey(appGroup string, appName string, consumerKey string, action string) (respBody []byte, err error) {
+	if action != "revoke" && action != "approve" {
+		return nil, fmt.Errorf("invalid action. action must be revoke or approve")
+	}
+	return updateKey(appGroup, appName, consumerKey, updateKeyRequest{Action: action})
+}
+
+// AddKeyProducts adds api products to a key
+func AddKeyProducts(appGroup string, appName string, consumerKey string, apiProducts []string) (respBody []byte, err error) {
+	return updateKey(appGroup, appName, consumerKey, updateKeyRequest{APIProducts: apiProducts})
+}
+
+// RemoveKeyProduct removes an api product from a key
+func RemoveKeyProduct(appGroup string, appName string, consumerKey string, apiProduct string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", appName,
+		"keys", consumerKey, "apiproducts", apiProduct)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// ManageKeyProduct approves or revokes an api product of a key
+func ManageKeyProduct(appGroup string, appName string, consumerKey string, apiProduct string, action string) (respBody []byte, err error) {
+	if action != "revoke" && action != "approve" {
+		return nil, fmt.Errorf("invalid action. action must be revoke or approve")
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", appName,
+		"keys", consumerKey, "apiproducts", apiProduct)
+	q := u.Query()
+	q.Set("action", action)
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String(), "", "POST", "application/octet-stream")
+	return respBody, err
+}
+
+func updateKey(appGroup string, appName string, consumerKey string, request updateKeyRequest) (respBody []byte, err error) {
+	var payload []byte
+
+	if payload, err = json.Marshal(request); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "appgroups", appGroup, "apps", appName, "keys", consumerKey)
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
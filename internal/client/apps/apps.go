@@ -34,6 +34,9 @@ import (
 	"github.com/thedevsaddam/gojsonq"
 )
 
+// maxPageSize is the largest page the apps API returns
+const maxPageSize = 1000
+
 type apps struct {
 	Apps []app `json:"app,omitempty"`
 }
@@ -155,15 +158,12 @@ func SearchApp(name string) (respBody []byte, err error) {
 	apiclient.ClientPrintHttpResponse.Set(false)
 	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
 
-	u, _ := url.Parse(apiclient.BaseURL)
 	// search by name is not implemented; use list and return the appropriate app
-	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "apps")
-	q := u.Query()
+	q := url.Values{}
 	q.Set("expand", "true")
 	q.Set("includeCred", "false")
-	u.RawQuery = q.Encode()
 
-	respBody, err = apiclient.HttpClient(u.String())
+	respBody, err = listAll(q)
 	if err != nil {
 		return respBody, err
 	}
@@ -192,7 +192,7 @@ func List(includeCred bool, expand bool, count int) (respBody []byte, err error)
 		q.Set("includeCred", "false")
 	}
 	if count != -1 {
-		q.Set("row", strconv.Itoa(count))
+		q.Set("rows", strconv.Itoa(count))
 	}
 	u.RawQuery = q.Encode()
 	respBody, err = apiclient.HttpClient(u.String())
@@ -201,13 +201,49 @@ func List(includeCred bool, expand bool, count int) (respBody []byte, err error)
 
 // ListApps
 func ListApps(productName string) (respBody []byte, err error) {
+	q := url.Values{}
+	q.Set("apiProduct", productName)
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	respBody, err = listAll(q)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return nil, err
+	}
+	return respBody, apiclient.PrettyPrint(respBody)
+}
+
+// ListAll returns every app in the org, following the pages
+func ListAll(includeCred bool, expand bool) (respBody []byte, err error) {
+	q := url.Values{}
+	q.Set("expand", strconv.FormatBool(expand))
+	if expand {
+		q.Set("includeCred", strconv.FormatBool(includeCred))
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	respBody, err = listAll(q)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return nil, err
+	}
+	return respBody, apiclient.PrettyPrint(respBody)
+}
+
+// listAll fetches every page of apps matching the query
+func listAll(q url.Values) (respBody []byte, err error) {
+	var total int
+
 	u, _ := url.Parse(apiclient.BaseURL)
 	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "apps")
-	q := u.Query()
-	q.Set("apiProduct", productName)
 	u.RawQuery = q.Encode()
-	respBody, err = apiclient.HttpClient(u.String())
-	return respBody, err
+
+	pager := apiclient.NewPager(u.String(), apiclient.RowPage, maxPageSize, "app", "appId")
+	if respBody, total, err = apiclient.ListAll(pager); err != nil {
+		return nil, err
+	}
+	clilog.Info.Printf("Fetched %d apps in %d pages\n", total, pager.Pages())
+	return respBody, nil
 }
 
 // GenerateKey
@@ -247,10 +283,7 @@ func Export(conn int) (payload [][]byte, err error) {
 	var mu sync.Mutex
 	const entityType = "apps"
 
-	u, _ := url.Parse(apiclient.BaseURL)
-	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), entityType)
-
-	respBody, err := apiclient.HttpClient(u.String())
+	respBody, err := listAll(url.Values{})
 	if err != nil {
 		return apiclient.GetEntityPayloadList(), err
 	}
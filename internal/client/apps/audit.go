@@ -0,0 +1,278 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"internal/client/developers"
+	"internal/client/products"
+)
+
+// Audit checks reported by Audit
+const (
+	AuditOrphanedProduct       = "orphaned-product"
+	AuditExpiredKey            = "expired-key"
+	AuditExpiringKey           = "expiring-key"
+	AuditRevokedKey            = "revoked-key"
+	AuditKeyWithoutExpiry      = "key-without-expiry"
+	AuditInactiveDeveloper     = "inactive-developer"
+	AuditDeveloperWithoutApps  = "developer-without-apps"
+	AuditAppWithoutCredentials = "app-without-credentials"
+	AuditUnbundledProduct      = "unbundled-product"
+)
+
+// AuditFinding is an issue found in the developers, apps or products of the org
+type AuditFinding struct {
+	Check      string `json:"check"`
+	Developer  string `json:"developer,omitempty"`
+	App        string `json:"app,omitempty"`
+	Key        string `json:"key,omitempty"`
+	APIProduct string `json:"apiProduct,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// Cleanable is true when Cleanup can fix the finding. Orphaned products are removed
+// from the key, expired and revoked keys and apps without credentials are deleted
+func (f AuditFinding) Cleanable() bool {
+	switch f.Check {
+	case AuditOrphanedProduct, AuditExpiredKey, AuditRevokedKey, AuditAppWithoutCredentials:
+		return true
+	}
+	return false
+}
+
+type auditDeveloper struct {
+	EMail       string `json:"email,omitempty"`
+	DeveloperID string `json:"developerId,omitempty"`
+	Status      string `json:"status,omitempty"`
+}
+
+// Audit cross references the developers, apps and products of the org. Keys that
+// expire within expiringWithin are reported as expiring
+func Audit(expiringWithin time.Duration) (findings []AuditFinding, err error) {
+	var respBody []byte
+	var appList []application
+	var productNames []string
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if respBody, err = developers.Export(); err != nil {
+		return nil, err
+	}
+	developerList := struct {
+		Developer []auditDeveloper `json:"developer,omitempty"`
+	}{}
+	if err = json.Unmarshal(respBody, &developerList); err != nil {
+		return nil, err
+	}
+
+	if appList, err = listAppsWithCredentials(); err != nil {
+		return nil, err
+	}
+
+	if productNames, err = products.ListNames(); err != nil {
+		return nil, err
+	}
+
+	productExists := make(map[string]bool, len(productNames))
+	for _, p := range productNames {
+		productExists[p] = true
+	}
+	developerEmails := map[string]string{}
+	for _, d := range developerList.Developer {
+		developerEmails[d.DeveloperID] = d.EMail
+	}
+
+	now := time.Now()
+	nowMillis := now.UnixMilli()
+	expiringBefore := now.Add(expiringWithin).UnixMilli()
+
+	appsPerDeveloper := map[string]int{}
+	bundled := map[string]bool{}
+
+	for _, app := range appList {
+		developerEmail := ""
+		if app.DeveloperID != nil {
+			developerEmail = developerEmails[*app.DeveloperID]
+			appsPerDeveloper[*app.DeveloperID]++
+		}
+
+		if app.Credentials == nil || len(*app.Credentials) == 0 {
+			findings = append(findings, AuditFinding{
+				Check:     AuditAppWithoutCredentials,
+				Developer: developerEmail,
+				App:       app.Name,
+			})
+			continue
+		}
+
+		for _, c := range *app.Credentials {
+			finding := AuditFinding{
+				Developer: developerEmail,
+				App:       app.Name,
+				Key:       c.ConsumerKey,
+			}
+
+			for _, p := range c.APIProducts {
+				bundled[p.Name] = true
+				if !productExists[p.Name] {
+					orphan := finding
+					orphan.Check = AuditOrphanedProduct
+					orphan.APIProduct = p.Name
+					orphan.Detail = "api product does not exist"
+					findings = append(findings, orphan)
+				}
+			}
+
+			expiresAt, _ := strconv.ParseInt(c.ExpiresAt, 10, 64)
+			switch {
+			case c.Status == "revoked":
+				finding.Check = AuditRevokedKey
+			case expiresAt > 0 && expiresAt < nowMillis:
+				finding.Check = AuditExpiredKey
+				finding.Detail = "expired " + formatMillis(expiresAt)
+			case expiresAt > 0 && expiresAt < expiringBefore:
+				finding.Check = AuditExpiringKey
+				finding.Detail = "expires " + formatMillis(expiresAt)
+			case expiresAt <= 0:
+				finding.Check = AuditKeyWithoutExpiry
+			default:
+				continue
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	for _, d := range developerList.Developer {
+		if d.Status != "" && d.Status != "active" {
+			findings = append(findings, AuditFinding{
+				Check:     AuditInactiveDeveloper,
+				Developer: d.EMail,
+				Detail:    "status " + d.Status,
+			})
+		}
+		if appsPerDeveloper[d.DeveloperID] == 0 {
+			findings = append(findings, AuditFinding{
+				Check:     AuditDeveloperWithoutApps,
+				Developer: d.EMail,
+			})
+		}
+	}
+
+	for _, p := range productNames {
+		if !bundled[p] {
+			findings = append(findings, AuditFinding{
+				Check:      AuditUnbundledProduct,
+				APIProduct: p,
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Check < findings[j].Check
+	})
+
+	clilog.Info.Printf("Audited %d developers, %d apps and %d products\n",
+		len(developerList.Developer), len(appList), len(productNames))
+	return findings, nil
+}
+
+// Cleanup fixes the cleanable findings. Findings of keys or apps that were already
+// deleted by an earlier finding are skipped
+func Cleanup(findings []AuditFinding) (err error) {
+	var errs []string
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	deleted := map[string]bool{}
+
+	// delete apps and keys before removing products from the keys
+	sorted := make([]AuditFinding, 0, len(findings))
+	for _, f := range findings {
+		if f.Check != AuditOrphanedProduct {
+			sorted = append(sorted, f)
+		}
+	}
+	for _, f := range findings {
+		if f.Check == AuditOrphanedProduct {
+			sorted = append(sorted, f)
+		}
+	}
+
+	for _, f := range sorted {
+		if !f.Cleanable() {
+			continue
+		}
+		if f.Developer == "" {
+			errs = append(errs, fmt.Sprintf("%s %s: the developer of the app was not found", f.Check, f.App))
+			continue
+		}
+		if deleted[f.Developer+"/"+f.App] || deleted[f.Key] {
+			continue
+		}
+
+		switch f.Check {
+		case AuditAppWithoutCredentials:
+			if _, err = Delete(f.App, f.Developer); err == nil {
+				deleted[f.Developer+"/"+f.App] = true
+				clilog.Info.Printf("Deleted app %s\n", f.App)
+			}
+		case AuditExpiredKey, AuditRevokedKey:
+			if _, err = DeleteKey(f.Developer, f.App, f.Key); err == nil {
+				deleted[f.Key] = true
+				clilog.Info.Printf("Deleted key %s of app %s\n", f.Key, f.App)
+			}
+		case AuditOrphanedProduct:
+			if _, err = removeKeyProduct(f.Developer, f.App, f.Key, f.APIProduct); err == nil {
+				clilog.Info.Printf("Removed %s from key %s of app %s\n", f.APIProduct, f.Key, f.App)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s %s: %v", f.Check, f.App, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func removeKeyProduct(developerEmail string, appName string, consumerKey string, apiProduct string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "developers", developerEmail, "apps", appName,
+		"keys", consumerKey, "apiproducts", apiProduct)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+func formatMillis(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
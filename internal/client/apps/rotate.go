@@ -0,0 +1,325 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"internal/client/developers"
+)
+
+// RotateFilter selects the apps whose keys are rotated. Empty fields match every app
+type RotateFilter struct {
+	// DeveloperEmail of the app owner
+	DeveloperEmail string
+	// APIProduct that must be associated with the key
+	APIProduct string
+	// Attributes the app must have with the same values
+	Attributes map[string]string
+	// NamePattern is matched against the app name, ex: mobile-*
+	NamePattern string
+}
+
+// KeyRotation maps an old consumer key to the key that replaces it
+type KeyRotation struct {
+	App            string   `json:"app"`
+	DeveloperEmail string   `json:"developerEmail"`
+	OldKey         string   `json:"oldKey"`
+	NewKey         string   `json:"newKey,omitempty"`
+	APIProducts    []string `json:"apiProducts,omitempty"`
+	Scopes         []string `json:"scopes,omitempty"`
+	OldKeyStatus   string   `json:"oldKeyStatus,omitempty"`
+	RotatedAt      string   `json:"rotatedAt,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// RotateKeys creates a new key, with the same api products and scopes, for every
+// approved key of the apps matching the filter. The keys are generated by Apigee.
+// When dryRun is set the keys that would be rotated are returned without making
+// changes. The old keys are not changed, use RevokeKeys once the new keys are distributed
+// and the grace period has elapsed
+func RotateKeys(filter RotateFilter, dryRun bool) (rotations []KeyRotation, err error) {
+	var appList []application
+	var developerEmails map[string]string
+	var errs []string
+
+	if filter.NamePattern != "" {
+		if _, err = path.Match(filter.NamePattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern %s: %v", filter.NamePattern, err)
+		}
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if developerEmails, err = listDeveloperEmails(); err != nil {
+		return nil, err
+	}
+	if appList, err = listAppsWithCredentials(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+
+	for _, app := range appList {
+		developerEmail := ""
+		if app.DeveloperID != nil {
+			developerEmail = developerEmails[*app.DeveloperID]
+		}
+		if developerEmail == "" {
+			// apps owned by app groups or deleted developers are not rotated
+			continue
+		}
+		if !filter.matchApp(app, developerEmail) {
+			continue
+		}
+		if app.Credentials == nil {
+			continue
+		}
+		// keys of the app, used to find the key generated by each rotation
+		appKeys := map[string]bool{}
+		for _, c := range *app.Credentials {
+			appKeys[c.ConsumerKey] = true
+		}
+		for _, c := range *app.Credentials {
+			if c.Status != "approved" || isExpired(c.ExpiresAt, now) {
+				continue
+			}
+			products := make([]string, 0, len(c.APIProducts))
+			for _, p := range c.APIProducts {
+				products = append(products, p.Name)
+			}
+			if filter.APIProduct != "" && !contains(products, filter.APIProduct) {
+				continue
+			}
+
+			rotation := KeyRotation{
+				App:            app.Name,
+				DeveloperEmail: developerEmail,
+				OldKey:         c.ConsumerKey,
+				APIProducts:    products,
+				Scopes:         c.Scopes,
+				OldKeyStatus:   c.Status,
+			}
+
+			if !dryRun {
+				var newKey string
+				if len(products) == 0 {
+					err = fmt.Errorf("the key has no api products")
+				} else {
+					newKey, err = generateKey(developerEmail, app, products, c.Scopes, appKeys)
+				}
+				if err != nil {
+					rotation.Error = err.Error()
+					errs = append(errs, fmt.Sprintf("app %s key %s: %v", app.Name, c.ConsumerKey, err))
+				} else {
+					rotation.NewKey = newKey
+					rotation.RotatedAt = time.Now().UTC().Format(time.RFC3339)
+					clilog.Info.Printf("Created key for app %s to replace %s\n", app.Name, c.ConsumerKey)
+				}
+			}
+			rotations = append(rotations, rotation)
+		}
+	}
+
+	if len(errs) > 0 {
+		return rotations, errors.New(strings.Join(errs, "\n"))
+	}
+	return rotations, nil
+}
+
+// RevokeKeys revokes the old keys of the rotations that created a new key. Nothing
+// is revoked until the grace period has elapsed since every rotation. Old keys
+// already revoked are skipped, so the rotations of a partial run can be passed again
+func RevokeKeys(rotations []KeyRotation, gracePeriod time.Duration) (err error) {
+	var errs []string
+
+	now := time.Now()
+	for _, r := range rotations {
+		if r.NewKey == "" || r.OldKeyStatus == "revoked" {
+			continue
+		}
+		rotatedAt, err := time.Parse(time.RFC3339, r.RotatedAt)
+		if err != nil {
+			return fmt.Errorf("app %s key %s: invalid rotation time %q", r.App, r.OldKey, r.RotatedAt)
+		}
+		if end := rotatedAt.Add(gracePeriod); now.Before(end) {
+			return fmt.Errorf("the grace period of app %s key %s ends at %s", r.App, r.OldKey, end.Format(time.RFC3339))
+		}
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	for i, r := range rotations {
+		if r.NewKey == "" || r.OldKeyStatus == "revoked" {
+			continue
+		}
+		rotations[i].Error = ""
+		if _, err = ManageKey(r.DeveloperEmail, r.App, r.OldKey, "revoke"); err != nil {
+			rotations[i].Error = err.Error()
+			errs = append(errs, fmt.Sprintf("app %s key %s: %v", r.App, r.OldKey, err))
+			continue
+		}
+		rotations[i].OldKeyStatus = "revoked"
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func (f RotateFilter) matchApp(app application, developerEmail string) bool {
+	if f.DeveloperEmail != "" && !strings.EqualFold(f.DeveloperEmail, developerEmail) {
+		return false
+	}
+	if f.NamePattern != "" {
+		if ok, _ := path.Match(f.NamePattern, app.Name); !ok {
+			return false
+		}
+	}
+	for k, v := range f.Attributes {
+		found := false
+		for _, a := range app.Attributes {
+			if a.Name == k && a.Value == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// generateKeyPayload updates an app with a new key. The update replaces the app,
+// its attributes and callback url are sent so that they are kept
+type generateKeyPayload struct {
+	Name        string      `json:"name"`
+	APIProducts []string    `json:"apiProducts"`
+	Scopes      []string    `json:"scopes,omitempty"`
+	CallbackURL string      `json:"callbackUrl,omitempty"`
+	Attributes  []attribute `json:"attributes,omitempty"`
+}
+
+// generateKey has Apigee generate a key for the app with the api products and
+// scopes. The new key is the one not found in appKeys, it is added to appKeys
+func generateKey(developerEmail string, app application, products []string, scopes []string,
+	appKeys map[string]bool,
+) (newKey string, err error) {
+	var payload, respBody []byte
+
+	if payload, err = json.Marshal(generateKeyPayload{
+		Name:        app.Name,
+		APIProducts: products,
+		Scopes:      scopes,
+		CallbackURL: app.CallbackURL,
+		Attributes:  app.Attributes,
+	}); err != nil {
+		return "", err
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "developers", developerEmail, "apps", app.Name)
+	if respBody, err = apiclient.HttpClient(u.String(), string(payload)); err != nil {
+		return "", err
+	}
+
+	updated := application{}
+	if err = json.Unmarshal(respBody, &updated); err != nil {
+		return "", err
+	}
+	if updated.Credentials != nil {
+		for _, c := range *updated.Credentials {
+			if !appKeys[c.ConsumerKey] {
+				appKeys[c.ConsumerKey] = true
+				return c.ConsumerKey, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("the generated key was not found in the response")
+}
+
+// listAppsWithCredentials returns every app in the org with the credentials
+func listAppsWithCredentials() (appList []application, err error) {
+	var respBody []byte
+
+	q := url.Values{}
+	q.Set("expand", "true")
+	q.Set("includeCred", "true")
+	if respBody, err = listAll(q); err != nil {
+		return nil, err
+	}
+
+	entities := struct {
+		Apps []application `json:"app,omitempty"`
+	}{}
+	if err = json.Unmarshal(respBody, &entities); err != nil {
+		return nil, err
+	}
+	return entities.Apps, nil
+}
+
+// listDeveloperEmails returns the email of every developer by developer id
+func listDeveloperEmails() (emails map[string]string, err error) {
+	var respBody []byte
+
+	if respBody, err = developers.Export(); err != nil {
+		return nil, err
+	}
+	entities := developers.Appdevelopers{}
+	if err = json.Unmarshal(respBody, &entities); err != nil {
+		return nil, err
+	}
+	emails = make(map[string]string, len(entities.Developer))
+	for _, d := range entities.Developer {
+		emails[d.DeveloperId] = d.EMail
+	}
+	return emails, nil
+}
+
+// isExpired checks the expiresAt of a credential, -1 means the key never expires
+func isExpired(expiresAt string, now int64) bool {
+	if expiresAt == "" || expiresAt == "-1" {
+		return false
+	}
+	ms, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil || ms <= 0 {
+		return false
+	}
+	return ms < now
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
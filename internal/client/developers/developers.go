@@ -46,6 +46,9 @@ type Appdevelopers struct {
 	Developer []Appdeveloper `json:"developer,omitempty"`
 }
 
+// maxPageSize is the largest page the developers API returns
+const maxPageSize = 1000
+
 // Attribute to used to hold custom attributes for entities
 type Attribute struct {
 	Name  string `json:"name,omitempty"`
@@ -152,6 +155,8 @@ func List(count int, expand bool, ids string) (respBody []byte, err error) {
 
 // Export
 func Export() (respBody []byte, err error) {
+	var total int
+
 	u, _ := url.Parse(apiclient.BaseURL)
 	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "developers")
 
@@ -162,8 +167,34 @@ func Export() (respBody []byte, err error) {
 	// don't print to sysout
 	apiclient.ClientPrintHttpResponse.Set(false)
 	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
-	respBody, err = apiclient.HttpClient(u.String())
-	return respBody, err
+
+	pager := apiclient.NewPager(u.String(), apiclient.StartKeyPage, maxPageSize, "developer", "email")
+	if respBody, total, err = apiclient.ListAll(pager); err != nil {
+		return nil, err
+	}
+	clilog.Info.Printf("Fetched %d developers in %d pages\n", total, pager.Pages())
+	return respBody, nil
+}
+
+// ListAll returns every developer in the org, following the pages
+func ListAll(expand bool) (respBody []byte, err error) {
+	var total int
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "developers")
+	q := u.Query()
+	q.Set("expand", strconv.FormatBool(expand))
+	u.RawQuery = q.Encode()
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	pager := apiclient.NewPager(u.String(), apiclient.StartKeyPage, maxPageSize, "developer", "email")
+	respBody, total, err = apiclient.ListAll(pager)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return nil, err
+	}
+	clilog.Info.Printf("Fetched %d developers in %d pages\n", total, pager.Pages())
+	return respBody, apiclient.PrettyPrint(respBody)
 }
 
 // Import
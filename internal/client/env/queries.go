@@ -0,0 +1,329 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"internal/apiclient"
+
+	"github.com/ghodss/yaml"
+)
+
+// Query is the status of an asynchronous analytics query
+type Query struct {
+	Self           string       `json:"self,omitempty"`
+	Name           string       `json:"name,omitempty"`
+	State          string       `json:"state,omitempty"`
+	Created        string       `json:"created,omitempty"`
+	Updated        string       `json:"updated,omitempty"`
+	Error          string       `json:"error,omitempty"`
+	ResultRows     string       `json:"resultRows,omitempty"`
+	ResultFileSize string       `json:"resultFileSize,omitempty"`
+	Result         *queryResult `json:"result,omitempty"`
+}
+
+type queryResult struct {
+	Self    string `json:"self,omitempty"`
+	Expires string `json:"expires,omitempty"`
+}
+
+// QueryID returns the id of the query from the self link
+func (q Query) QueryID() string {
+	return path.Base(q.Self)
+}
+
+// CreateQuery submits an asynchronous analytics query. The query definition
+// is read from a json or yaml file
+func CreateQuery(queryFile string) (respBody []byte, err error) {
+	var payload []byte
+
+	if payload, err = ReadQueryFile(queryFile); err != nil {
+		return nil, err
+	}
+
+	// throttle API Calls
+	getDefaultRate := apiclient.GetRate()
+	apiclient.SetRate(apiclient.ApigeeAnalyticsAPI)
+	defer apiclient.SetRate(getDefaultRate)
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "queries")
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+// GetQuery returns the status of an asynchronous analytics query
+func GetQuery(queryID string) (respBody []byte, err error) {
+	getDefaultRate := apiclient.GetRate()
+	apiclient.SetRate(apiclient.ApigeeAnalyticsAPI)
+	defer apiclient.SetRate(getDefaultRate)
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "queries", queryID)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// ListQueries returns the asynchronous analytics queries in the environment
+func ListQueries(submittedBy string, status string, from string, to string) (respBody []byte, err error) {
+	getDefaultRate := apiclient.GetRate()
+	apiclient.SetRate(apiclient.ApigeeAnalyticsAPI)
+	defer apiclient.SetRate(getDefaultRate)
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "queries")
+	q := u.Query()
+	if submittedBy != "" {
+		q.Set("submittedBy", submittedBy)
+	}
+	if status != "" {
+		q.Set("status", status)
+	}
+	if from != "" {
+		q.Set("from", from)
+	}
+	if to != "" {
+		q.Set("to", to)
+	}
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// GetQueryResult downloads the result of a completed query, decompresses it and
+// converts the rows to csv or ndjson
+func GetQueryResult(queryID string, format string) (result []byte, err error) {
+	var data []byte
+
+	if format != "csv" && format != "ndjson" {
+		return nil, fmt.Errorf("format must be csv or ndjson")
+	}
+
+	getDefaultRate := apiclient.GetRate()
+	apiclient.SetRate(apiclient.ApigeeAnalyticsAPI)
+	defer apiclient.SetRate(getDefaultRate)
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(),
+		"queries", queryID, "result")
+
+	resp, err := apiclient.DownloadFile(u.String(), true)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+	defer resp.Body.Close()
+
+	if data, err = io.ReadAll(resp.Body); err != nil {
+		return nil, err
+	}
+
+	if data, err = decompress(data); err != nil {
+		return nil, err
+	}
+
+	return convertQueryResult(data, format)
+}
+
+// ReadQueryFile reads a query definition in json or yaml and returns it as json
+func ReadQueryFile(queryFile string) (payload []byte, err error) {
+	if payload, err = os.ReadFile(queryFile); err != nil {
+		return nil, err
+	}
+	ext := filepath.Ext(queryFile)
+	if ext == ".yaml" || ext == ".yml" {
+		if payload, err = yaml.YAMLToJSON(payload); err != nil {
+			return nil, err
+		}
+	}
+	if !json.Valid(payload) {
+		return nil, fmt.Errorf("%s is not a valid json or yaml query definition", queryFile)
+	}
+	return payload, nil
+}
+
+// decompress extracts zip or gzip content, other content is returned as is
+func decompress(data []byte) (out []byte, err error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		var zr *zip.Reader
+		if zr, err = zip.NewReader(bytes.NewReader(data), int64(len(data))); err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			var rc io.ReadCloser
+			var content []byte
+			if rc, err = f.Open(); err != nil {
+				return nil, err
+			}
+			content, err = io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			if content, err = decompress(content); err != nil {
+				return nil, err
+			}
+			out = append(out, content...)
+			if len(out) > 0 && out[len(out)-1] != '\n' {
+				out = append(out, '\n')
+			}
+		}
+		return out, nil
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		var gr *gzip.Reader
+		if gr, err = gzip.NewReader(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		return io.ReadAll(gr)
+	default:
+		return data, nil
+	}
+}
+
+// convertQueryResult converts a result file in json, ndjson or csv to the format
+func convertQueryResult(data []byte, format string) (out []byte, err error) {
+	var rows []map[string]interface{}
+	var header []string
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	switch trimmed[0] {
+	case '[':
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.UseNumber()
+		if err = dec.Decode(&rows); err != nil {
+			return nil, err
+		}
+	case '{':
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.UseNumber()
+		for dec.More() {
+			row := map[string]interface{}{}
+			if err = dec.Decode(&row); err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		}
+	default:
+		if format == "csv" {
+			return data, nil
+		}
+		var records [][]string
+		if records, err = csv.NewReader(bytes.NewReader(trimmed)).ReadAll(); err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+		header = records[0]
+		for _, record := range records[1:] {
+			row := map[string]interface{}{}
+			for i, h := range header {
+				if i < len(record) {
+					row[h] = record[i]
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	var buf bytes.Buffer
+	if format == "ndjson" {
+		w := bufio.NewWriter(&buf)
+		for _, row := range rows {
+			var line []byte
+			if line, err = json.Marshal(row); err != nil {
+				return nil, err
+			}
+			w.Write(line)
+			w.WriteByte('\n')
+		}
+		if err = w.Flush(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	// csv columns are the union of the keys in every row
+	if header == nil {
+		keys := map[string]bool{}
+		for _, row := range rows {
+			for k := range row {
+				if !keys[k] {
+					keys[k] = true
+					header = append(header, k)
+				}
+			}
+		}
+		sort.Strings(header)
+	}
+
+	w := csv.NewWriter(&buf)
+	if err = w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, h := range header {
+			record[i] = cellValue(row[h])
+		}
+		if err = w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// cellValue formats a json value as a csv cell, objects and arrays are kept as json
+func cellValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		b, _ := json.Marshal(value)
+		return string(b)
+	}
+}
@@ -61,6 +61,31 @@ type apiCalls struct {
 
 var ApiCalls = &apiCalls{count: 0}
 
+// UsageRow is the number of API calls for a combination of proxy, developer app and product
+type UsageRow struct {
+	Environment   string `json:"environment"`
+	Proxy         string `json:"proxy"`
+	DeveloperApp  string `json:"developerApp"`
+	APIProduct    string `json:"apiProduct"`
+	Calls         int64  `json:"calls"`
+	PreviousCalls *int64 `json:"previousCalls,omitempty"`
+	Delta         *int64 `json:"delta,omitempty"`
+}
+
+type usageRows struct {
+	rows []UsageRow
+	errs []string
+	sync.Mutex
+}
+
+var Usage = &usageRows{}
+
+// usageDimensions are the dimensions used to break down usage
+var usageDimensions = []string{proxy_dimension, "developer_app", "api_product"}
+
+// statsLimit is the largest number of rows returned by the stats api
+const statsLimit = 14400
+
 func TotalAPICallsInMonthAsync(environment string, month int, year int, envDetails bool, wg *sync.WaitGroup) {
 	defer wg.Done()
 	var total int
@@ -121,6 +146,103 @@ func TotalAPICallsInMonth(environment string, month int, year int) (total int, e
 	return apiCalls, nil
 }
 
+// UsageInRangeAsync collects the usage of an environment into Usage. Errors
+// are collected with the environment name
+func UsageInRangeAsync(environment string, start time.Time, end time.Time, wg *sync.WaitGroup) {
+	defer wg.Done()
+	var rows []UsageRow
+	var err error
+
+	if rows, err = UsageInRange(environment, start, end); err != nil {
+		clilog.Error.Println(err)
+		Usage.addError(environment, err)
+		return
+	}
+	Usage.add(rows)
+}
+
+// UsageInRange returns the API calls in the environment by proxy, developer app
+// and product between start and end
+func UsageInRange(environment string, start time.Time, end time.Time) (rows []UsageRow, err error) {
+	var respBody []byte
+	var series []StatsSeries
+
+	query := StatsQuery{
+		Dimensions: usageDimensions,
+		Metrics:    []string{selection},
+		TimeRange:  start.Format("01/02/2006 15:04") + "~" + end.Format("01/02/2006 15:04"),
+		Limit:      statsLimit,
+	}
+
+	if respBody, err = queryStats(environment, query); err != nil {
+		return nil, err
+	}
+	if series, err = ParseStats(respBody, usageDimensions); err != nil {
+		return nil, err
+	}
+
+	for _, s := range series {
+		var calls float64
+		for _, p := range s.Points {
+			v, _ := strconv.ParseFloat(p.Value, 64)
+			calls = calls + v
+		}
+		rows = append(rows, UsageRow{
+			Environment:  environment,
+			Proxy:        s.Dimensions[proxy_dimension],
+			DeveloperApp: s.Dimensions["developer_app"],
+			APIProduct:   s.Dimensions["api_product"],
+			Calls:        int64(calls),
+		})
+	}
+
+	if len(rows) == statsLimit {
+		clilog.Warning.Printf("usage for environment %s has %d rows and may be truncated, use a shorter range\n",
+			environment, statsLimit)
+	}
+	return rows, nil
+}
+
+// GetRows returns the collected usage
+func (u *usageRows) GetRows() []UsageRow {
+	u.Lock()
+	defer u.Unlock()
+	rows := make([]UsageRow, len(u.rows))
+	copy(rows, u.rows)
+	return rows
+}
+
+// GetErrors returns the environments whose usage could not be collected with their error
+func (u *usageRows) GetErrors() []string {
+	u.Lock()
+	defer u.Unlock()
+	errs := make([]string, len(u.errs))
+	copy(errs, u.errs)
+	return errs
+}
+
+// ResetRows
+func (u *usageRows) ResetRows() {
+	u.Lock()
+	defer u.Unlock()
+	u.rows = nil
+	u.errs = nil
+}
+
+// add synchronizes collecting usage
+func (u *usageRows) add(rows []UsageRow) {
+	u.Lock()
+	defer u.Unlock()
+	u.rows = append(u.rows, rows...)
+}
+
+// addError synchronizes collecting errors
+func (u *usageRows) addError(environment string, err error) {
+	u.Lock()
+	defer u.Unlock()
+	u.errs = append(u.errs, environment+": "+err.Error())
+}
+
 // GetCount
 func (c *apiCalls) GetCount() int {
 	return c.count
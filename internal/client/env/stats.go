@@ -0,0 +1,265 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal/apiclient"
+)
+
+// StatsQuery holds the parameters of an analytics stats query
+type StatsQuery struct {
+	Dimensions  []string
+	Metrics     []string
+	Filter      string
+	TimeRange   string
+	TimeUnit    string
+	Sort        string
+	SortBy      string
+	Limit       int
+	TsAscending bool
+}
+
+// StatsPoint is a single value of a metric, Timestamp is zero when the
+// query is not grouped by a time unit
+type StatsPoint struct {
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Value     string `json:"value"`
+}
+
+// StatsSeries holds the values of a metric for a combination of dimensions
+type StatsSeries struct {
+	Environment string            `json:"environment"`
+	Dimensions  map[string]string `json:"dimensions,omitempty"`
+	Metric      string            `json:"metric"`
+	Points      []StatsPoint      `json:"points"`
+}
+
+type statsReport struct {
+	Environments []statsEnvironment `json:"environments,omitempty"`
+}
+
+type statsEnvironment struct {
+	Name       string           `json:"name,omitempty"`
+	Dimensions []statsDimension `json:"dimensions,omitempty"`
+	Metrics    []statsMetric    `json:"metrics,omitempty"`
+}
+
+type statsDimension struct {
+	Name    string        `json:"name,omitempty"`
+	Metrics []statsMetric `json:"metrics,omitempty"`
+}
+
+type statsMetric struct {
+	Name   string            `json:"name,omitempty"`
+	Values []json.RawMessage `json:"values,omitempty"`
+}
+
+// QueryStats runs an analytics stats query against the environment
+func QueryStats(query StatsQuery) (respBody []byte, err error) {
+	return queryStats(apiclient.GetApigeeEnv(), query)
+}
+
+func queryStats(environment string, query StatsQuery) (respBody []byte, err error) {
+	if len(query.Metrics) == 0 {
+		return nil, fmt.Errorf("at least one metric must be set")
+	}
+	if query.TimeRange == "" {
+		return nil, fmt.Errorf("timeRange must be set")
+	}
+
+	// throttle API Calls
+	getDefaultRate := apiclient.GetRate()
+	apiclient.SetRate(apiclient.ApigeeAnalyticsAPI)
+	defer apiclient.SetRate(getDefaultRate)
+
+	u, _ := url.Parse(apiclient.BaseURL)
+
+	q := u.Query()
+	q.Set("select", strings.Join(query.Metrics, ","))
+	q.Set("timeRange", query.TimeRange)
+	if query.TimeUnit != "" {
+		q.Set("timeUnit", query.TimeUnit)
+	}
+	if query.Filter != "" {
+		q.Set("filter", query.Filter)
+	}
+	if query.Sort != "" {
+		q.Set("sort", query.Sort)
+	}
+	if query.SortBy != "" {
+		q.Set("sortby", query.SortBy)
+	}
+	if query.Limit > 0 {
+		q.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.TsAscending {
+		q.Set("tsAscending", "true")
+	}
+
+	u.RawQuery = q.Encode()
+	// stats with no dimension are queried at the environment level
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", environment,
+		"stats", strings.Join(query.Dimensions, ","))
+
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// ParseStats flattens a stats response into a series per environment, dimension
+// values and metric
+func ParseStats(respBody []byte, dimensions []string) (series []StatsSeries, err error) {
+	report := statsReport{}
+	if err = json.Unmarshal(respBody, &report); err != nil {
+		return nil, err
+	}
+
+	for _, e := range report.Environments {
+		for _, m := range e.Metrics {
+			s, err := newSeries(e.Name, nil, m)
+			if err != nil {
+				return nil, err
+			}
+			series = append(series, s)
+		}
+		for _, d := range e.Dimensions {
+			dimensionValues := splitDimension(d.Name, dimensions)
+			for _, m := range d.Metrics {
+				s, err := newSeries(e.Name, dimensionValues, m)
+				if err != nil {
+					return nil, err
+				}
+				series = append(series, s)
+			}
+		}
+	}
+	return series, nil
+}
+
+// StatsTable returns the series as rows with a column per dimension and metric.
+// The timestamp column is included when the series have timestamps
+func StatsTable(series []StatsSeries, dimensions []string, metrics []string) (header []string, rows [][]string) {
+	type rowKey struct {
+		env, dims string
+		ts        int64
+	}
+
+	timeSeries := false
+	for _, s := range series {
+		for _, p := range s.Points {
+			if p.Timestamp != 0 {
+				timeSeries = true
+			}
+		}
+	}
+
+	header = append(header, "environment")
+	header = append(header, dimensions...)
+	if timeSeries {
+		header = append(header, "timestamp")
+	}
+	metricCol := map[string]int{}
+	for _, m := range metrics {
+		metricCol[m] = len(header)
+		header = append(header, m)
+	}
+
+	index := map[rowKey]int{}
+	for _, s := range series {
+		dimensionValues := make([]string, len(dimensions))
+		for i, d := range dimensions {
+			dimensionValues[i] = s.Dimensions[d]
+		}
+		col, ok := metricCol[s.Metric]
+		if !ok {
+			// the api may normalize the metric name, add it as a new column
+			col = len(header)
+			metricCol[s.Metric] = col
+			header = append(header, s.Metric)
+			for i := range rows {
+				rows[i] = append(rows[i], "")
+			}
+		}
+		for _, p := range s.Points {
+			key := rowKey{s.Environment, strings.Join(dimensionValues, ","), p.Timestamp}
+			i, ok := index[key]
+			if !ok {
+				row := []string{s.Environment}
+				row = append(row, dimensionValues...)
+				if timeSeries {
+					row = append(row, time.UnixMilli(p.Timestamp).UTC().Format(time.RFC3339))
+				}
+				row = append(row, make([]string, len(header)-len(row))...)
+				rows = append(rows, row)
+				i = len(rows) - 1
+				index[key] = i
+			}
+			rows[i][col] = p.Value
+		}
+	}
+	return header, rows
+}
+
+func newSeries(environment string, dimensionValues map[string]string, m statsMetric) (s StatsSeries, err error) {
+	s = StatsSeries{
+		Environment: environment,
+		Dimensions:  dimensionValues,
+		Metric:      m.Name,
+	}
+	for _, v := range m.Values {
+		p := StatsPoint{}
+		// values are plain strings unless the query has a time unit
+		if err = json.Unmarshal(v, &p.Value); err != nil {
+			tsValue := struct {
+				Timestamp int64           `json:"timestamp,omitempty"`
+				Value     json.RawMessage `json:"value,omitempty"`
+			}{}
+			if err = json.Unmarshal(v, &tsValue); err != nil {
+				return s, fmt.Errorf("unable to parse value of metric %s: %v", m.Name, err)
+			}
+			p.Timestamp = tsValue.Timestamp
+			if err = json.Unmarshal(tsValue.Value, &p.Value); err != nil {
+				p.Value = string(tsValue.Value)
+			}
+		}
+		s.Points = append(s.Points, p)
+	}
+	return s, nil
+}
+
+// splitDimension maps the dimension name returned by the api to each requested
+// dimension, the values of multiple dimensions are separated by commas
+func splitDimension(name string, dimensions []string) map[string]string {
+	values := map[string]string{}
+	if len(dimensions) == 0 {
+		return values
+	}
+	parts := strings.Split(name, ",")
+	if len(parts) != len(dimensions) {
+		values[dimensions[0]] = name
+		return values
+	}
+	for i, d := range dimensions {
+		values[d] = parts[i]
+	}
+	return values
+}
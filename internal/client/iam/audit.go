@@ -0,0 +1,174 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iam
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"internal/client/env"
+)
+
+type roleBinding struct {
+	Role    string   `json:"role,omitempty"`
+	Members []string `json:"members,omitempty"`
+}
+
+type iamPolicy struct {
+	Bindings []roleBinding `json:"bindings,omitempty"`
+}
+
+// AuditEntry is a member holding a role at a scope (project or environment)
+type AuditEntry struct {
+	Member  string `json:"member,omitempty"`
+	Role    string `json:"role,omitempty"`
+	Scope   string `json:"scope,omitempty"`
+	Finding string `json:"finding,omitempty"`
+}
+
+// privilegedRoles should not be held by principals used by the runtime
+var privilegedRoles = []string{
+	"roles/owner", "roles/editor", "roles/apigee.admin", "roles/apigee.apiAdminV2",
+	"roles/apigee.environmentAdmin", "roles/apigee.developerAdmin",
+}
+
+// publicMembers grant access to any user
+var publicMembers = []string{"allUsers", "allAuthenticatedUsers"}
+
+// Audit builds a matrix of members, roles and scopes from the project IAM
+// policy and the IAM policy of every environment in the org. Project roles
+// not related to Apigee are skipped unless allRoles is set.
+func Audit(allRoles bool) (entries []AuditEntry, err error) {
+	var respBody []byte
+	var environments []string
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	clilog.Info.Printf("Fetching IAM policy for project %s\n", apiclient.GetProjectID())
+	if respBody, err = apiclient.GetProjectIAMPolicy(); err != nil {
+		return nil, err
+	}
+
+	projectPolicy := iamPolicy{}
+	if err = json.Unmarshal(respBody, &projectPolicy); err != nil {
+		return nil, err
+	}
+
+	for _, binding := range projectPolicy.Bindings {
+		if !allRoles && !isApigeeRole(binding.Role) {
+			continue
+		}
+		entries = append(entries, newEntries(binding, "project/"+apiclient.GetProjectID())...)
+	}
+
+	if respBody, err = env.List(); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(respBody, &environments); err != nil {
+		return nil, err
+	}
+
+	currentEnv := apiclient.GetApigeeEnv()
+	defer apiclient.SetApigeeEnv(currentEnv)
+
+	for _, environment := range environments {
+		clilog.Info.Printf("Fetching IAM policy for environment %s\n", environment)
+		apiclient.SetApigeeEnv(environment)
+		if respBody, err = env.GetIAM(); err != nil {
+			return nil, err
+		}
+
+		envPolicy := iamPolicy{}
+		if err = json.Unmarshal(respBody, &envPolicy); err != nil {
+			return nil, err
+		}
+
+		for _, binding := range envPolicy.Bindings {
+			entries = append(entries, newEntries(binding, "environment/"+environment)...)
+		}
+	}
+
+	flagEntries(entries)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Member != entries[j].Member {
+			return entries[i].Member < entries[j].Member
+		}
+		if entries[i].Scope != entries[j].Scope {
+			return entries[i].Scope < entries[j].Scope
+		}
+		return entries[i].Role < entries[j].Role
+	})
+
+	return entries, nil
+}
+
+func newEntries(binding roleBinding, scope string) (entries []AuditEntry) {
+	for _, member := range binding.Members {
+		entries = append(entries, AuditEntry{
+			Member: member,
+			Role:   binding.Role,
+			Scope:  scope,
+		})
+	}
+	return entries
+}
+
+// flagEntries sets a finding on entries that grant more access than needed
+func flagEntries(entries []AuditEntry) {
+	runtimeMembers := map[string]bool{}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Member, "serviceAccount:") && isRuntimeRole(entry.Role) {
+			runtimeMembers[entry.Member] = true
+		}
+	}
+
+	for i, entry := range entries {
+		switch {
+		case contains(publicMembers, entry.Member):
+			entries[i].Finding = "role granted to a public principal"
+		case runtimeMembers[entry.Member] && contains(privilegedRoles, entry.Role):
+			entries[i].Finding = "runtime service account holds a privileged role"
+		}
+	}
+}
+
+// isApigeeRole returns true for roles that grant access to Apigee resources
+func isApigeeRole(role string) bool {
+	return strings.Contains(role, "apigee") || contains(privilegedRoles, role) ||
+		contains(apiclient.GetRuntimeRoles(), role)
+}
+
+// isRuntimeRole returns true for the Apigee roles used by hybrid runtime components
+func isRuntimeRole(role string) bool {
+	return strings.HasPrefix(role, "roles/apigee") && contains(apiclient.GetRuntimeRoles(), role)
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
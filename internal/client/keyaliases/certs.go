@@ -0,0 +1,220 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyaliases
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// GetCertificates returns the parsed certificate chain of a key alias. The alias
+// only describes its certificates, the chain is downloaded from the certificate endpoint
+func GetCertificates(keystoreName string, name string) (certs []*x509.Certificate, err error) {
+	var respBody []byte
+
+	if respBody, err = getCertificate(keystoreName, name); err != nil {
+		return nil, err
+	}
+	return ParseCertificates(respBody)
+}
+
+// ParseCertificates parses all the PEM encoded certificates in data
+func ParseCertificates(data []byte) (certs []*x509.Certificate, err error) {
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found")
+	}
+	return certs, nil
+}
+
+func getCertificate(keystoreName string, name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(),
+		"keystores", keystoreName, "aliases", name, "certificate")
+
+	resp, err := apiclient.DownloadFile(u.String(), true)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+// ValidateKeyCert checks the PEM certificate chain and private key before they are
+// uploaded. The chain must be ordered from the leaf to the issuers, the key must match
+// the leaf certificate and the leaf must be valid for the hostname when one is set
+func ValidateKeyCert(certFile string, keyFile string, hostname string) (err error) {
+	var certPEM, keyPEM []byte
+	var certs []*x509.Certificate
+	var key crypto.PrivateKey
+
+	if certPEM, err = os.ReadFile(certFile); err != nil {
+		return err
+	}
+	if certs, err = ParseCertificates(certPEM); err != nil {
+		return fmt.Errorf("invalid certificate file %s: %v", certFile, err)
+	}
+
+	if keyFile != "" {
+		if keyPEM, err = os.ReadFile(keyFile); err != nil {
+			return err
+		}
+		block, _ := pem.Decode(keyPEM)
+		if block == nil {
+			return fmt.Errorf("no PEM data found in key file %s", keyFile)
+		}
+		if strings.Contains(block.Type, "ENCRYPTED") {
+			clilog.Warning.Println("private key is encrypted, skipping key and certificate match")
+		} else {
+			if key, err = parsePrivateKey(block.Bytes); err != nil {
+				return fmt.Errorf("invalid key file %s: %v", keyFile, err)
+			}
+		}
+	}
+
+	return validateChain(certs, key, hostname)
+}
+
+// ValidatePfx checks the certificate chain and private key in a PKCS12 file before it is uploaded
+func ValidatePfx(pfxFile string, password string, hostname string) (err error) {
+	var certs []*x509.Certificate
+	var key crypto.PrivateKey
+
+	if certs, key, err = ReadPfx(pfxFile, password); err != nil {
+		return err
+	}
+	return validateChain(certs, key, hostname)
+}
+
+// ReadPfx returns the certificates and private key of a PKCS12 file. The certificate
+// for the private key is returned first
+func ReadPfx(pfxFile string, password string) (certs []*x509.Certificate, key crypto.PrivateKey, err error) {
+	var pfxData []byte
+	var blocks []*pem.Block
+
+	if pfxData, err = os.ReadFile(pfxFile); err != nil {
+		return nil, nil, err
+	}
+	if blocks, err = pkcs12.ToPEM(pfxData, password); err != nil {
+		return nil, nil, fmt.Errorf("invalid pfx file %s: %v", pfxFile, err)
+	}
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			if cert, err = x509.ParseCertificate(block.Bytes); err != nil {
+				return nil, nil, err
+			}
+			certs = append(certs, cert)
+		case "PRIVATE KEY":
+			if key, err = parsePrivateKey(block.Bytes); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("no certificates found in pfx file %s", pfxFile)
+	}
+
+	// pfx files don't guarantee an order, move the certificate for the key first
+	if key != nil {
+		for i, cert := range certs {
+			if publicKeyMatches(cert, key) {
+				certs[0], certs[i] = certs[i], certs[0]
+				break
+			}
+		}
+	}
+
+	return certs, key, nil
+}
+
+func validateChain(certs []*x509.Certificate, key crypto.PrivateKey, hostname string) (err error) {
+	leaf := certs[0]
+
+	if key != nil && !publicKeyMatches(leaf, key) {
+		return fmt.Errorf("private key does not match the certificate %s", leaf.Subject)
+	}
+
+	for i := 0; i < len(certs)-1; i++ {
+		if err = certs[i].CheckSignatureFrom(certs[i+1]); err != nil {
+			return fmt.Errorf("certificate %s is not signed by the next certificate in the chain %s: %v",
+				certs[i].Subject, certs[i+1].Subject, err)
+		}
+	}
+
+	if hostname != "" {
+		if err = leaf.VerifyHostname(hostname); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parsePrivateKey(der []byte) (key crypto.PrivateKey, err error) {
+	if key, err = x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err = x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err = x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type")
+}
+
+func publicKeyMatches(cert *x509.Certificate, key crypto.PrivateKey) bool {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return false
+	}
+	pub, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && pub.Equal(cert.PublicKey)
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyaliases
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+
+	"internal/clilog"
+)
+
+// GenerateKeyAndCSR creates a private key and a PKCS #10 certificate signing request
+// locally, without sending the key material to Apigee. keyType is rsa (keySize 2048,
+// 3072 or 4096) or ecdsa (keySize 256, 384 or 521). The key is written to keyFile
+// readable only by the owner. Existing files are only overwritten when force is set
+func GenerateKeyAndCSR(keyType string, keySize int, subject pkix.Name, dnsNames []string,
+	ipAddresses []string, emailAddresses []string, keyFile string, csrFile string, force bool,
+) (err error) {
+	var key crypto.Signer
+	var keyDER, csrDER []byte
+	var sigAlg x509.SignatureAlgorithm
+
+	switch keyType {
+	case "rsa":
+		if keySize != 2048 && keySize != 3072 && keySize != 4096 {
+			return fmt.Errorf("rsa key size must be 2048, 3072 or 4096")
+		}
+		if key, err = rsa.GenerateKey(rand.Reader, keySize); err != nil {
+			return err
+		}
+		sigAlg = x509.SHA256WithRSA
+	case "ecdsa":
+		var curve elliptic.Curve
+		switch keySize {
+		case 256:
+			curve, sigAlg = elliptic.P256(), x509.ECDSAWithSHA256
+		case 384:
+			curve, sigAlg = elliptic.P384(), x509.ECDSAWithSHA384
+		case 521:
+			curve, sigAlg = elliptic.P521(), x509.ECDSAWithSHA512
+		default:
+			return fmt.Errorf("ecdsa key size must be 256, 384 or 521")
+		}
+		if key, err = ecdsa.GenerateKey(curve, rand.Reader); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("key type must be rsa or ecdsa")
+	}
+
+	if subject.CommonName == "" {
+		return fmt.Errorf("commonName is a mandatory parameter")
+	}
+
+	template := &x509.CertificateRequest{
+		Subject:            subject,
+		DNSNames:           dnsNames,
+		EmailAddresses:     emailAddresses,
+		SignatureAlgorithm: sigAlg,
+	}
+
+	for _, ipAddress := range ipAddresses {
+		ip := net.ParseIP(ipAddress)
+		if ip == nil {
+			return fmt.Errorf("invalid ip address %s", ipAddress)
+		}
+		template.IPAddresses = append(template.IPAddresses, ip)
+	}
+
+	if csrDER, err = x509.CreateCertificateRequest(rand.Reader, template, key); err != nil {
+		return err
+	}
+
+	if keyDER, err = x509.MarshalPKCS8PrivateKey(key); err != nil {
+		return err
+	}
+
+	if !force {
+		for _, fileName := range []string{keyFile, csrFile} {
+			if _, err = os.Stat(fileName); err == nil {
+				return fmt.Errorf("%s already exists, set force to overwrite it", fileName)
+			}
+		}
+	}
+
+	if err = writePEM(keyFile, "PRIVATE KEY", keyDER, 0o600, force); err != nil {
+		return err
+	}
+	clilog.Info.Printf("Private key written to %s\n", keyFile)
+
+	if err = writePEM(csrFile, "CERTIFICATE REQUEST", csrDER, 0o644, force); err != nil {
+		return err
+	}
+	clilog.Info.Printf("Certificate signing request written to %s\n", csrFile)
+
+	return nil
+}
+
+// writePEM creates the file, it fails when the file exists unless force is set
+func writePEM(fileName string, blockType string, der []byte, perm os.FileMode, force bool) (err error) {
+	flag := os.O_CREATE | os.O_EXCL | os.O_WRONLY
+	if force {
+		flag = os.O_CREATE | os.O_TRUNC | os.O_WRONLY
+	}
+	file, err := os.OpenFile(fileName, flag, perm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = file.Chmod(perm); err != nil {
+		return err
+	}
+
+	return pem.Encode(file, &pem.Block{Type: blockType, Bytes: der})
+}
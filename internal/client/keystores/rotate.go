@@ -0,0 +1,353 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keystores
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"internal/client/keyaliases"
+	"internal/client/references"
+	"internal/client/targetservers"
+)
+
+// rotation tracks the changes made during a rotation so they can be rolled back
+type rotation struct {
+	newKeystoreName, newAliasName string
+	keystoreCreated               bool
+	aliasCreated                  bool
+	updatedRefs                   []keystoreRef
+	updatedTargetServers          []targetServerSSL
+}
+
+// RotationRecord describes a completed rotation. It is kept by the caller and
+// passed to CleanupRotation once the grace period has elapsed
+type RotationRecord struct {
+	Keystore    string   `json:"keystore"`
+	Alias       string   `json:"alias"`
+	NewKeystore string   `json:"newKeystore"`
+	NewAlias    string   `json:"newAlias"`
+	RotatedAt   string   `json:"rotatedAt"`
+	Switched    []string `json:"switched,omitempty"`
+}
+
+// Rotate uploads new key material as newAliasName in a new keystore, verifies it and
+// switches every reference and target server using the old keystore and alias to it.
+// The new keystore only has the new alias, so the rotation is refused when a reference
+// to the old keystore is also used by target servers with another alias.
+// All the changes are rolled back if a step fails. The record of the rotation,
+// with the resources that were switched, is returned
+func Rotate(keystoreName string, aliasName string, newKeystoreName string, newAliasName string,
+	format string, certFile string, keyFile string, pfxFile string, password string,
+	ignoreExpiry bool, ignoreNewLine bool,
+) (record RotationRecord, err error) {
+	var switched []string
+
+	r := &rotation{
+		newKeystoreName: newKeystoreName,
+		newAliasName:    newAliasName,
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	defer func() {
+		if err != nil {
+			clilog.Warning.Printf("rotation failed, rolling back: %v\n", err)
+			if rollbackErr := r.rollback(); rollbackErr != nil {
+				err = fmt.Errorf("%v; rollback failed: %v", err, rollbackErr)
+			}
+		}
+	}()
+
+	// Step 1: find the references and target servers to switch
+	refs, targets, err := getDependents(keystoreName)
+	if err != nil {
+		return record, err
+	}
+	if err = checkSharedRefs(targets, aliasName); err != nil {
+		return record, err
+	}
+
+	// Step 2: upload the new material under a new keystore
+	clilog.Info.Printf("Creating keystore %s\n", newKeystoreName)
+	if _, err = Create(newKeystoreName); err != nil {
+		return record, err
+	}
+	r.keystoreCreated = true
+
+	clilog.Info.Printf("Creating key alias %s\n", newAliasName)
+	switch format {
+	case "pem":
+		_, err = keyaliases.CreateOrUpdateKeyCert(newKeystoreName, newAliasName, false,
+			ignoreExpiry, ignoreNewLine, certFile, keyFile, password)
+	case "pkcs12":
+		_, err = keyaliases.CreateOrUpdatePfx(newKeystoreName, newAliasName, false,
+			ignoreExpiry, ignoreNewLine, pfxFile, password)
+	default:
+		err = fmt.Errorf("invalid format key alias for %s", format)
+	}
+	if err != nil {
+		return record, err
+	}
+	r.aliasCreated = true
+
+	// Step 3: verify the uploaded certificate matches the local material
+	if err = verifyUpload(newKeystoreName, newAliasName, format, certFile, pfxFile, password); err != nil {
+		return record, err
+	}
+
+	// Step 4: switch references and target servers to the new keystore
+	for _, ref := range refs {
+		clilog.Info.Printf("Switching reference %s to keystore %s\n", ref.Name, newKeystoreName)
+		if _, err = references.Update(ref.Name, "", ref.ResourceType, newKeystoreName); err != nil {
+			return record, err
+		}
+		r.updatedRefs = append(r.updatedRefs, ref)
+		switched = append(switched, "reference/"+ref.Name)
+	}
+
+	for _, target := range targets {
+		if target.SslInfo.Keyalias != aliasName {
+			continue
+		}
+		keyStore := target.SslInfo.Keystore
+		if !strings.HasPrefix(keyStore, refPrefix) {
+			keyStore = newKeystoreName
+		} else if newAliasName == aliasName {
+			// the reference was switched and the alias didn't change
+			continue
+		}
+		clilog.Info.Printf("Switching target server %s to keystore %s and alias %s\n",
+			target.Name, keyStore, newAliasName)
+		if _, err = targetservers.UpdateKeystore(target.Name, keyStore, newAliasName); err != nil {
+			return record, err
+		}
+		r.updatedTargetServers = append(r.updatedTargetServers, target)
+		switched = append(switched, "targetserver/"+target.Name)
+	}
+
+	return RotationRecord{
+		Keystore:    keystoreName,
+		Alias:       aliasName,
+		NewKeystore: newKeystoreName,
+		NewAlias:    newAliasName,
+		RotatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Switched:    switched,
+	}, nil
+}
+
+// CleanupRotation deletes the old key alias of a rotation, and the keystore when it has
+// no aliases left. It is refused before the grace period has elapsed, and while a
+// reference still points at the old keystore or a target server uses the old alias
+func CleanupRotation(record RotationRecord, gracePeriod time.Duration) (err error) {
+	var respBody []byte
+	var aliasList []string
+	var refs []keystoreRef
+	var targets []targetServerSSL
+
+	keystoreName, aliasName := record.Keystore, record.Alias
+
+	rotatedAt, err := time.Parse(time.RFC3339, record.RotatedAt)
+	if err != nil {
+		return fmt.Errorf("invalid rotation time %q", record.RotatedAt)
+	}
+	if end := rotatedAt.Add(gracePeriod); time.Now().Before(end) {
+		return fmt.Errorf("the grace period of the rotation ends at %s", end.Format(time.RFC3339))
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if refs, targets, err = getDependents(keystoreName); err != nil {
+		return err
+	}
+	if len(refs) > 0 {
+		return fmt.Errorf("keystore %s is still used by reference %s", keystoreName, refs[0].Name)
+	}
+	for _, target := range targets {
+		if target.SslInfo.Keyalias == aliasName {
+			return fmt.Errorf("key alias %s is still used by target server %s", aliasName, target.Name)
+		}
+	}
+
+	clilog.Info.Printf("Deleting key alias %s from keystore %s\n", aliasName, keystoreName)
+	if _, err = keyaliases.Delete(keystoreName, aliasName); err != nil {
+		return err
+	}
+
+	if respBody, err = keyaliases.List(keystoreName); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(respBody, &aliasList); err != nil {
+		return err
+	}
+
+	if len(aliasList) == 0 {
+		clilog.Info.Printf("Deleting empty keystore %s\n", keystoreName)
+		_, err = Delete(keystoreName)
+	}
+	return err
+}
+
+func (r *rotation) rollback() (err error) {
+	errs := []string{}
+
+	for i := len(r.updatedTargetServers) - 1; i >= 0; i-- {
+		target := r.updatedTargetServers[i]
+		if _, err = targetservers.UpdateKeystore(target.Name,
+			target.SslInfo.Keystore, target.SslInfo.Keyalias); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	for i := len(r.updatedRefs) - 1; i >= 0; i-- {
+		ref := r.updatedRefs[i]
+		if _, err = references.Update(ref.Name, "", ref.ResourceType, ref.Refers); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if r.aliasCreated {
+		if _, err = keyaliases.Delete(r.newKeystoreName, r.newAliasName); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if r.keystoreCreated {
+		if _, err = Delete(r.newKeystoreName); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// checkSharedRefs returns an error when a target server uses a reference to the
+// keystore with another alias than the rotated one. Switching the reference would
+// point it to a keystore without that alias
+func checkSharedRefs(targets []targetServerSSL, aliasName string) error {
+	var shared []string
+	for _, target := range targets {
+		if strings.HasPrefix(target.SslInfo.Keystore, refPrefix) && target.SslInfo.Keyalias != aliasName {
+			shared = append(shared, fmt.Sprintf("target server %s uses reference %s with alias %s",
+				target.Name, strings.TrimPrefix(target.SslInfo.Keystore, refPrefix), target.SslInfo.Keyalias))
+		}
+	}
+	if len(shared) > 0 {
+		return fmt.Errorf("the references cannot be switched to a keystore with only the alias %s:\n%s",
+			aliasName, strings.Join(shared, "\n"))
+	}
+	return nil
+}
+
+// verifyUpload checks the leaf certificate in Apigee matches the local certificate
+func verifyUpload(keystoreName string, aliasName string, format string,
+	certFile string, pfxFile string, password string,
+) (err error) {
+	var local, remote []*x509.Certificate
+	var certPEM []byte
+
+	localFile := certFile
+	if format == "pkcs12" {
+		localFile = pfxFile
+		if local, _, err = keyaliases.ReadPfx(pfxFile, password); err != nil {
+			return err
+		}
+	} else {
+		if certPEM, err = os.ReadFile(certFile); err != nil {
+			return err
+		}
+		if local, err = keyaliases.ParseCertificates(certPEM); err != nil {
+			return err
+		}
+	}
+	if remote, err = keyaliases.GetCertificates(keystoreName, aliasName); err != nil {
+		return err
+	}
+	if !bytes.Equal(local[0].Raw, remote[0].Raw) {
+		return fmt.Errorf("certificate in alias %s does not match %s", aliasName, localFile)
+	}
+	return nil
+}
+
+// getDependents returns the references and TLS target servers that use the keystore,
+// directly or through a reference
+func getDependents(keystoreName string) (refs []keystoreRef, targets []targetServerSSL, err error) {
+	var respBody []byte
+	var refList, targetServerList []string
+
+	refNames := map[string]bool{}
+
+	if respBody, err = references.List(); err != nil {
+		return nil, nil, err
+	}
+	if err = json.Unmarshal(respBody, &refList); err != nil {
+		return nil, nil, err
+	}
+
+	for _, refName := range refList {
+		if respBody, err = references.Get(refName); err != nil {
+			return nil, nil, err
+		}
+		ref := keystoreRef{}
+		if err = json.Unmarshal(respBody, &ref); err != nil {
+			return nil, nil, err
+		}
+		if ref.Refers == keystoreName {
+			refs = append(refs, ref)
+			refNames[ref.Name] = true
+		}
+	}
+
+	if respBody, err = targetservers.List(); err != nil {
+		return nil, nil, err
+	}
+	if err = json.Unmarshal(respBody, &targetServerList); err != nil {
+		return nil, nil, err
+	}
+
+	for _, targetServerName := range targetServerList {
+		if respBody, err = targetservers.Get(targetServerName); err != nil {
+			return nil, nil, err
+		}
+		target := targetServerSSL{}
+		if err = json.Unmarshal(respBody, &target); err != nil {
+			return nil, nil, err
+		}
+		if target.SslInfo == nil {
+			continue
+		}
+		if target.SslInfo.Keystore == keystoreName || (strings.HasPrefix(target.SslInfo.Keystore, refPrefix) &&
+			refNames[strings.TrimPrefix(target.SslInfo.Keystore, refPrefix)]) {
+			targets = append(targets, target)
+		}
+	}
+
+	return refs, targets, nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keystores
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"internal/client/env"
+	"internal/client/keyaliases"
+	"internal/client/references"
+	"internal/client/targetservers"
+)
+
+// CertificateReport holds the details of a certificate in a key alias
+type CertificateReport struct {
+	Environment   string    `json:"environment,omitempty"`
+	Keystore      string    `json:"keystore,omitempty"`
+	Alias         string    `json:"alias,omitempty"`
+	Subject       string    `json:"subject,omitempty"`
+	SANs          []string  `json:"subjectAlternativeNames,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	NotAfter      time.Time `json:"notAfter,omitempty"`
+	DaysRemaining int       `json:"daysRemaining"`
+	UsedBy        []string  `json:"usedBy,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+type targetServerSSL struct {
+	Name    string `json:"name,omitempty"`
+	SslInfo *struct {
+		Keystore   string `json:"keyStore,omitempty"`
+		Keyalias   string `json:"keyAlias,omitempty"`
+		Truststore string `json:"trustStore,omitempty"`
+	} `json:"sSLInfo,omitempty"`
+}
+
+type keystoreRef struct {
+	Name         string `json:"name,omitempty"`
+	ResourceType string `json:"resourceType,omitempty"`
+	Refers       string `json:"refers,omitempty"`
+}
+
+const refPrefix = "ref://"
+
+// Scan reports every certificate in every keystore and alias of the environments.
+// All the environments in the org are scanned when environments is empty. An alias
+// whose certificates can't be read is reported with the error, and an error naming
+// those aliases is returned along with the reports
+func Scan(environments []string) (reports []CertificateReport, err error) {
+	var respBody []byte
+	var errs []string
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if len(environments) == 0 {
+		if respBody, err = env.List(); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(respBody, &environments); err != nil {
+			return nil, err
+		}
+	}
+
+	currentEnv := apiclient.GetApigeeEnv()
+	defer apiclient.SetApigeeEnv(currentEnv)
+
+	now := time.Now()
+
+	for _, environment := range environments {
+		var keystoreList []string
+		var usage map[string][]string
+
+		clilog.Info.Printf("Scanning keystores in environment %s\n", environment)
+		apiclient.SetApigeeEnv(environment)
+
+		if usage, err = getKeystoreUsage(); err != nil {
+			return nil, err
+		}
+
+		if respBody, err = List(); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(respBody, &keystoreList); err != nil {
+			return nil, err
+		}
+
+		for _, keystore := range keystoreList {
+			var aliasList []string
+
+			if respBody, err = keyaliases.List(keystore); err != nil {
+				return nil, err
+			}
+			if err = json.Unmarshal(respBody, &aliasList); err != nil {
+				return nil, err
+			}
+
+			for _, alias := range aliasList {
+				var certs []*x509.Certificate
+				if certs, err = keyaliases.GetCertificates(keystore, alias); err != nil {
+					reports = append(reports, CertificateReport{
+						Environment: environment,
+						Keystore:    keystore,
+						Alias:       alias,
+						UsedBy:      usage[keystore],
+						Error:       err.Error(),
+					})
+					errs = append(errs, environment+"/"+keystore+"/"+alias)
+					continue
+				}
+				for _, cert := range certs {
+					reports = append(reports, CertificateReport{
+						Environment:   environment,
+						Keystore:      keystore,
+						Alias:         alias,
+						Subject:       cert.Subject.String(),
+						SANs:          getSANs(cert),
+						Issuer:        cert.Issuer.String(),
+						NotAfter:      cert.NotAfter.UTC(),
+						DaysRemaining: int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24)),
+						UsedBy:        usage[keystore],
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].NotAfter.Before(reports[j].NotAfter)
+	})
+
+	if len(errs) > 0 {
+		return reports, fmt.Errorf("unable to read the certificates of %d alias(es): %s",
+			len(errs), strings.Join(errs, ", "))
+	}
+	return reports, nil
+}
+
+// getKeystoreUsage maps keystore names to the target servers and references
+// in the current environment that use them
+func getKeystoreUsage() (usage map[string][]string, err error) {
+	var respBody []byte
+	var refList, targetServerList []string
+
+	usage = map[string][]string{}
+	refersTo := map[string]string{}
+
+	if respBody, err = references.List(); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(respBody, &refList); err != nil {
+		return nil, err
+	}
+
+	for _, refName := range refList {
+		if respBody, err = references.Get(refName); err != nil {
+			return nil, err
+		}
+		r := keystoreRef{}
+		if err = json.Unmarshal(respBody, &r); err != nil {
+			return nil, err
+		}
+		if r.ResourceType != "KeyStore" {
+			continue
+		}
+		refersTo[r.Name] = r.Refers
+		usage[r.Refers] = append(usage[r.Refers], "reference/"+r.Name)
+	}
+
+	if respBody, err = targetservers.List(); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(respBody, &targetServerList); err != nil {
+		return nil, err
+	}
+
+	for _, targetServerName := range targetServerList {
+		if respBody, err = targetservers.Get(targetServerName); err != nil {
+			return nil, err
+		}
+		t := targetServerSSL{}
+		if err = json.Unmarshal(respBody, &t); err != nil {
+			return nil, err
+		}
+		if t.SslInfo == nil {
+			continue
+		}
+		for _, store := range []string{t.SslInfo.Keystore, t.SslInfo.Truststore} {
+			if store == "" {
+				continue
+			}
+			if strings.HasPrefix(store, refPrefix) {
+				if store = refersTo[strings.TrimPrefix(store, refPrefix)]; store == "" {
+					continue
+				}
+			}
+			usage[store] = append(usage[store], "targetserver/"+t.Name)
+		}
+	}
+
+	return usage, nil
+}
+
+func getSANs(cert *x509.Certificate) (sans []string) {
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
@@ -107,39 +107,61 @@ func ListEntries(proxyName string, mapName string, pageSize int, pageToken strin
 	return respBody, err
 }
 
+// ListAllEntries returns every entry in the map, following the page tokens
+func ListAllEntries(proxyName string, mapName string) (respBody []byte, err error) {
+	var total int
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	pager := apiclient.NewPager(entriesURL(proxyName, mapName), apiclient.TokenPage, -1, "keyValueEntries", "")
+	pager.SizeParam, pager.TokenParam = "page_size", "page_token"
+	respBody, total, err = apiclient.ListAll(pager)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return nil, err
+	}
+	clilog.Info.Printf("Fetched %d entries in %d pages for map %s\n", total, pager.Pages(), mapName)
+	return respBody, apiclient.PrettyPrint(respBody)
+}
+
+func entriesURL(proxyName string, mapName string) string {
+	u, _ := url.Parse(apiclient.BaseURL)
+	if apiclient.GetApigeeEnv() != "" {
+		u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "keyvaluemaps", mapName, "entries")
+	} else if proxyName != "" {
+		u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "apis", proxyName, "keyvaluemaps", mapName, "entries")
+	} else {
+		u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "keyvaluemaps", mapName, "entries")
+	}
+	return u.String()
+}
+
 // ExportEntries
 func ExportEntries(proxyName string, mapName string) (payload [][]byte, err error) {
+	var items []json.RawMessage
 	var respBody []byte
-	count := 1
+	var ok bool
 
 	apiclient.ClientPrintHttpResponse.Set(false)
 	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
 
-	if respBody, err = ListEntries(proxyName, mapName, -1, ""); err != nil {
-		return nil, err
-	}
-
-	clilog.Debug.Printf("Exporting batch 1 of KVM entries for map %s\n", mapName)
-	payload = append(payload, respBody)
-
-	keyValueEntries := keyvalueentries{}
-	if err = json.Unmarshal(respBody, &keyValueEntries); err != nil {
-		return nil, err
-	}
+	pager := apiclient.NewPager(entriesURL(proxyName, mapName), apiclient.TokenPage, -1, "keyValueEntries", "")
+	pager.SizeParam, pager.TokenParam = "page_size", "page_token"
 
-	for keyValueEntries.NextPageToken != "" {
-		if respBody, err = ListEntries(proxyName, mapName, -1, keyValueEntries.NextPageToken); err != nil {
+	for {
+		if items, ok, err = pager.Next(); err != nil {
 			return nil, err
 		}
-
-		if err = json.Unmarshal(respBody, &keyValueEntries); err != nil {
+		if !ok {
+			break
+		}
+		clilog.Debug.Printf("Exporting batch %d of KVM entries for map %s\n", pager.Pages(), mapName)
+		if respBody, err = json.Marshal(map[string][]json.RawMessage{"keyValueEntries": items}); err != nil {
 			return nil, err
 		}
-		count++
-		clilog.Debug.Printf("Exporting batch %d of KVM entries for map %s\n", count, mapName)
 		payload = append(payload, respBody)
 	}
 
+	clilog.Info.Printf("Fetched %d entries in %d pages for map %s\n", pager.Total(), pager.Pages(), mapName)
 	return payload, nil
 }
 
@@ -17,6 +17,8 @@ package orgs
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,8 +30,6 @@ import (
 )
 
 func TotalAPICallsInMonth(month int, year int, envDetails bool, conn int) (total int, err error) {
-	var pwg sync.WaitGroup
-	var envListBytes []byte
 	var envList []string
 
 	// ensure the count is reset to zero before calculating the next set
@@ -38,13 +38,136 @@ func TotalAPICallsInMonth(month int, year int, envDetails bool, conn int) (total
 	apiclient.ClientPrintHttpResponse.Set(false)
 	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
 
-	if envListBytes, err = env.List(); err != nil {
+	if envList, err = listEnvironments(); err != nil {
 		return -1, err
 	}
 
+	forEachEnvironment(envList, conn, func(environment string, wg *sync.WaitGroup) {
+		env.TotalAPICallsInMonthAsync(environment, month, year, envDetails, wg)
+	})
+
+	return env.ApiCalls.GetCount(), nil
+}
+
+// UsageReport returns the API calls by environment, proxy, developer app and product
+// between start and end. When compare is set, the calls in the previous period of the
+// same length are included with the delta. When the usage of some environments
+// cannot be read, the rows of the other environments are returned with an error
+// naming the failed environments
+func UsageReport(start time.Time, end time.Time, envList []string, compare bool, conn int) (rows []env.UsageRow, err error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("end must be after start")
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if len(envList) == 0 {
+		if envList, err = listEnvironments(); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, errs := usageInRange(envList, start, end, conn)
+	if compare {
+		prevStart, prevEnd := PreviousPeriod(start, end)
+		clilog.Debug.Printf("Comparing with %s~%s\n", prevStart, prevEnd)
+		previous, prevErrs := usageInRange(envList, prevStart, prevEnd, conn)
+		for _, prevErr := range prevErrs {
+			errs = append(errs, prevErr+" (previous period)")
+		}
+		rows = compareUsage(rows, previous)
+	}
+
+	if len(errs) > 0 {
+		return rows, fmt.Errorf("usage could not be read for %d environment(s): %s", len(errs), strings.Join(errs, "; "))
+	}
+	return rows, nil
+}
+
+// PreviousPeriod returns the period before start and end. Calendar months are
+// compared with the previous calendar month, other ranges with the range of the
+// same length that ends before start
+func PreviousPeriod(start time.Time, end time.Time) (time.Time, time.Time) {
+	if start.Day() == 1 && start.Hour() == 0 && start.Minute() == 0 &&
+		end.Equal(start.AddDate(0, 1, 0).Add(-time.Minute)) {
+		prevStart := start.AddDate(0, -1, 0)
+		return prevStart, start.Add(-time.Minute)
+	}
+	prevEnd := start.Add(-time.Minute)
+	return prevEnd.Add(-end.Sub(start)), prevEnd
+}
+
+func usageInRange(envList []string, start time.Time, end time.Time, conn int) ([]env.UsageRow, []string) {
+	// ensure the rows are reset before collecting the next set
+	defer env.Usage.ResetRows()
+
+	forEachEnvironment(envList, conn, func(environment string, wg *sync.WaitGroup) {
+		env.UsageInRangeAsync(environment, start, end, wg)
+	})
+
+	rows := env.Usage.GetRows()
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Calls != rows[j].Calls {
+			return rows[i].Calls > rows[j].Calls
+		}
+		return usageKey(rows[i]) < usageKey(rows[j])
+	})
+	return rows, env.Usage.GetErrors()
+}
+
+// compareUsage adds the calls in the previous period and the delta to each row.
+// Rows only found in the previous period are included with zero calls
+func compareUsage(current []env.UsageRow, previous []env.UsageRow) (rows []env.UsageRow) {
+	prevCalls := map[string]int64{}
+	for _, p := range previous {
+		prevCalls[usageKey(p)] = prevCalls[usageKey(p)] + p.Calls
+	}
+
+	seen := map[string]bool{}
+	for _, r := range current {
+		key := usageKey(r)
+		seen[key] = true
+		prev := prevCalls[key]
+		delta := r.Calls - prev
+		r.PreviousCalls, r.Delta = &prev, &delta
+		rows = append(rows, r)
+	}
+
+	for _, p := range previous {
+		key := usageKey(p)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		prev := prevCalls[key]
+		delta := -prev
+		p.Calls, p.PreviousCalls, p.Delta = 0, &prev, &delta
+		rows = append(rows, p)
+	}
+	return rows
+}
+
+func usageKey(r env.UsageRow) string {
+	return strings.Join([]string{r.Environment, r.Proxy, r.DeveloperApp, r.APIProduct}, "\x00")
+}
+
+func listEnvironments() (envList []string, err error) {
+	var envListBytes []byte
+
+	if envListBytes, err = env.List(); err != nil {
+		return nil, err
+	}
+
 	if err = json.Unmarshal(envListBytes, &envList); err != nil {
-		return -1, err
+		return nil, err
 	}
+	return envList, nil
+}
+
+// forEachEnvironment runs report for the environments in batches of conn
+func forEachEnvironment(envList []string, conn int, report func(environment string, wg *sync.WaitGroup)) {
+	var pwg sync.WaitGroup
 
 	numEntities := len(envList)
 	clilog.Debug.Printf("Found %d environments\n", numEntities)
@@ -63,7 +186,7 @@ func TotalAPICallsInMonth(month int, year int, envDetails bool, conn int) (total
 		pwg.Add(1)
 		end = (i * conn) + conn
 		clilog.Debug.Printf("Creating reports for a batch %d of environments\n", (i + 1))
-		go batchReport(envList[start:end], month, year, envDetails, &pwg)
+		go batchReport(envList[start:end], report, &pwg)
 		start = end
 		pwg.Wait()
 	}
@@ -71,14 +194,12 @@ func TotalAPICallsInMonth(month int, year int, envDetails bool, conn int) (total
 	if remaining > 0 {
 		pwg.Add(1)
 		clilog.Debug.Printf("Creating reports for remaining %d environments\n", remaining)
-		go batchReport(envList[start:numEntities], month, year, envDetails, &pwg)
+		go batchReport(envList[start:numEntities], report, &pwg)
 		pwg.Wait()
 	}
-
-	return env.ApiCalls.GetCount(), nil
 }
 
-func batchReport(envList []string, month int, year int, envDetails bool, pwg *sync.WaitGroup) {
+func batchReport(envList []string, report func(environment string, wg *sync.WaitGroup), pwg *sync.WaitGroup) {
 	defer pwg.Done()
 	// batch workgroup
 	var bwg sync.WaitGroup
@@ -86,7 +207,7 @@ func batchReport(envList []string, month int, year int, envDetails bool, pwg *sy
 	bwg.Add(len(envList))
 
 	for _, environment := range envList {
-		go env.TotalAPICallsInMonthAsync(environment, month, year, envDetails, &bwg)
+		go report(environment, &bwg)
 	}
 
 	bwg.Wait()
@@ -0,0 +1,198 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+
+	"internal/apiclient"
+)
+
+// APIDoc is a catalog item of an integrated developer portal
+type APIDoc struct {
+	ID                 string   `json:"id,omitempty"`
+	SiteID             string   `json:"siteId,omitempty"`
+	Title              string   `json:"title,omitempty"`
+	Description        string   `json:"description,omitempty"`
+	APIProductName     string   `json:"apiProductName,omitempty"`
+	Published          bool     `json:"published"`
+	AnonAllowed        bool     `json:"anonAllowed"`
+	RequireCallbackURL bool     `json:"requireCallbackUrl"`
+	ImageURL           string   `json:"imageUrl,omitempty"`
+	CategoryIDs        []string `json:"categoryIds,omitempty"`
+	Visibility         bool     `json:"visibility,omitempty"`
+	ModifiedTime       string   `json:"modified,omitempty"`
+}
+
+type apiDocResponse struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    APIDoc `json:"data,omitempty"`
+}
+
+type apiDocsResponse struct {
+	Status        string   `json:"status,omitempty"`
+	Data          []APIDoc `json:"data,omitempty"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+}
+
+type documentation struct {
+	OASDocumentation *oasDocumentation `json:"oasDocumentation,omitempty"`
+}
+
+type oasDocumentation struct {
+	Spec   documentationFile `json:"spec,omitempty"`
+	Format string            `json:"format,omitempty"`
+}
+
+type documentationFile struct {
+	DisplayName string `json:"displayName,omitempty"`
+	Contents    []byte `json:"contents,omitempty"`
+}
+
+// maxPageSize is the largest page the apidocs API returns
+const maxPageSize = 100
+
+// CreateAPIDoc adds a catalog item to the portal
+func CreateAPIDoc(siteID string, apiDoc APIDoc) (respBody []byte, err error) {
+	var payload []byte
+
+	if payload, err = json.Marshal(apiDoc); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apidocs")
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+// GetAPIDoc
+func GetAPIDoc(siteID string, id string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apidocs", id)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// UpdateAPIDoc replaces the catalog item
+func UpdateAPIDoc(siteID string, id string, apiDoc APIDoc) (respBody []byte, err error) {
+	var payload []byte
+
+	apiDoc.ID, apiDoc.SiteID, apiDoc.ModifiedTime = "", "", ""
+	if payload, err = json.Marshal(apiDoc); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apidocs", id)
+	respBody, err = apiclient.HttpClient(u.String(), string(payload), "PUT")
+	return respBody, err
+}
+
+// DeleteAPIDoc
+func DeleteAPIDoc(siteID string, id string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apidocs", id)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// ListAPIDocs
+func ListAPIDocs(siteID string, pageSize int, pageToken string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apidocs")
+	q := u.Query()
+	if pageSize != -1 {
+		q.Set("pageSize", strconv.Itoa(pageSize))
+	}
+	if pageToken != "" {
+		q.Set("pageToken", pageToken)
+	}
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// GetDocumentation returns the spec of the catalog item
+func GetDocumentation(siteID string, id string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apidocs", id, "documentation")
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// UpdateDocumentation uploads an OpenAPI spec to the catalog item, format is JSON or YAML
+func UpdateDocumentation(siteID string, id string, displayName string, contents []byte, format string) (respBody []byte, err error) {
+	var payload []byte
+
+	if format != "JSON" && format != "YAML" {
+		return nil, fmt.Errorf("format must be JSON or YAML")
+	}
+
+	doc := documentation{
+		OASDocumentation: &oasDocumentation{
+			Spec: documentationFile{
+				DisplayName: displayName,
+				Contents:    contents,
+			},
+			Format: format,
+		},
+	}
+	if payload, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apidocs", id, "documentation")
+	respBody, err = apiclient.HttpClient(u.String(), string(payload), "PATCH")
+	return respBody, err
+}
+
+// GetAPIDocData returns the catalog item without printing it
+func GetAPIDocData(siteID string, id string) (apiDoc APIDoc, err error) {
+	var respBody []byte
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	respBody, err = GetAPIDoc(siteID, id)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return apiDoc, err
+	}
+	resp := apiDocResponse{}
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return apiDoc, err
+	}
+	return resp.Data, nil
+}
+
+// listAllAPIDocs returns every catalog item of the portal
+func listAllAPIDocs(siteID string) (apiDocs []APIDoc, err error) {
+	var respBody []byte
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apidocs")
+
+	pager := apiclient.NewPager(u.String(), apiclient.TokenPage, maxPageSize, "data", "")
+	if respBody, _, err = apiclient.ListAll(pager); err != nil {
+		return nil, err
+	}
+	resp := apiDocsResponse{}
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+)
+
+// Category is a catalog category of an integrated developer portal
+type Category struct {
+	ID         string `json:"id,omitempty"`
+	SiteID     string `json:"siteId,omitempty"`
+	Name       string `json:"name,omitempty"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+type categoryResponse struct {
+	Status string   `json:"status,omitempty"`
+	Data   Category `json:"data,omitempty"`
+}
+
+type categoriesResponse struct {
+	Status string     `json:"status,omitempty"`
+	Data   []Category `json:"data,omitempty"`
+}
+
+// CreateCategory
+func CreateCategory(siteID string, name string) (respBody []byte, err error) {
+	var payload []byte
+
+	if payload, err = json.Marshal(Category{Name: name}); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apicategories")
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+// GetCategory
+func GetCategory(siteID string, id string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apicategories", id)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// UpdateCategory renames the category
+func UpdateCategory(siteID string, id string, name string) (respBody []byte, err error) {
+	var payload []byte
+
+	if payload, err = json.Marshal(Category{Name: name}); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apicategories", id)
+	respBody, err = apiclient.HttpClient(u.String(), string(payload), "PATCH")
+	return respBody, err
+}
+
+// DeleteCategory
+func DeleteCategory(siteID string, id string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apicategories", id)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// ListCategories
+func ListCategories(siteID string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "sites", siteID, "apicategories")
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// ResolveCategories returns the ids of the categories, which are passed by name
+// or id. Categories that are not found are created when create is set
+func ResolveCategories(siteID string, categories []string, create bool) (ids []string, err error) {
+	var respBody []byte
+
+	if len(categories) == 0 {
+		return nil, nil
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if respBody, err = ListCategories(siteID); err != nil {
+		return nil, err
+	}
+	existing := categoriesResponse{}
+	if err = json.Unmarshal(respBody, &existing); err != nil {
+		return nil, err
+	}
+
+	for _, c := range categories {
+		id := ""
+		for _, e := range existing.Data {
+			if e.ID == c || e.Name == c {
+				id = e.ID
+				break
+			}
+		}
+		if id == "" {
+			if !create {
+				return nil, fmt.Errorf("category %s was not found", c)
+			}
+			if respBody, err = CreateCategory(siteID, c); err != nil {
+				return nil, err
+			}
+			created := categoryResponse{}
+			if err = json.Unmarshal(respBody, &created); err != nil {
+				return nil, err
+			}
+			id = created.Data.ID
+			clilog.Info.Printf("Created category %s\n", c)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portals
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+
+	"github.com/ghodss/yaml"
+)
+
+// specInfo is the part of the OpenAPI info object used for the catalog item.
+// The image is read from the x-logo extension
+type specInfo struct {
+	Info struct {
+		Title       string `json:"title,omitempty"`
+		Description string `json:"description,omitempty"`
+		Logo        struct {
+			URL string `json:"url,omitempty"`
+		} `json:"x-logo,omitempty"`
+	} `json:"info,omitempty"`
+}
+
+// Publish creates or updates the catalog item of the api product from an OpenAPI spec.
+// The title, description and image are read from the spec info, the categories are
+// passed by name or id and created when missing. The spec is uploaded as the
+// documentation of the catalog item
+func Publish(siteID string, apiProduct string, specName string, contents []byte,
+	categories []string, published bool, anonAllowed bool,
+) (respBody []byte, err error) {
+	var jsonContents []byte
+	var apiDocs []APIDoc
+	var categoryIDs []string
+
+	if jsonContents, err = yaml.YAMLToJSON(contents); err != nil {
+		return nil, err
+	}
+	spec := specInfo{}
+	if err = json.Unmarshal(jsonContents, &spec); err != nil {
+		return nil, err
+	}
+	if spec.Info.Title == "" {
+		return nil, fmt.Errorf("the spec %s does not have an info title", specName)
+	}
+
+	if categoryIDs, err = ResolveCategories(siteID, categories, true); err != nil {
+		return nil, err
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if apiDocs, err = listAllAPIDocs(siteID); err != nil {
+		return nil, err
+	}
+
+	apiDoc := APIDoc{}
+	for _, d := range apiDocs {
+		if d.APIProductName == apiProduct {
+			apiDoc = d
+			break
+		}
+	}
+
+	apiDoc.Title = spec.Info.Title
+	apiDoc.Description = spec.Info.Description
+	apiDoc.APIProductName = apiProduct
+	apiDoc.Published = published
+	apiDoc.AnonAllowed = anonAllowed
+	if spec.Info.Logo.URL != "" {
+		apiDoc.ImageURL = spec.Info.Logo.URL
+	}
+	if len(categoryIDs) > 0 {
+		apiDoc.CategoryIDs = categoryIDs
+	}
+
+	if apiDoc.ID == "" {
+		if respBody, err = CreateAPIDoc(siteID, apiDoc); err != nil {
+			return nil, err
+		}
+		created := apiDocResponse{}
+		if err = json.Unmarshal(respBody, &created); err != nil {
+			return nil, err
+		}
+		apiDoc.ID = created.Data.ID
+		clilog.Info.Printf("Created catalog item %s for %s\n", apiDoc.ID, apiProduct)
+	} else {
+		if _, err = UpdateAPIDoc(siteID, apiDoc.ID, apiDoc); err != nil {
+			return nil, err
+		}
+		clilog.Info.Printf("Updated catalog item %s for %s\n", apiDoc.ID, apiProduct)
+	}
+
+	format := "JSON"
+	if ext := strings.ToLower(filepath.Ext(specName)); ext == ".yaml" || ext == ".yml" {
+		format = "YAML"
+	}
+	if _, err = UpdateDocumentation(siteID, apiDoc.ID, specName, contents, format); err != nil {
+		return nil, err
+	}
+	clilog.Info.Printf("Uploaded %s to catalog item %s\n", specName, apiDoc.ID)
+
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	return GetAPIDoc(siteID, apiDoc.ID)
+}
@@ -30,6 +30,9 @@ import (
 	"internal/clilog"
 )
 
+// maxPageSize is the largest page the products API returns
+const maxPageSize = 1000
+
 type apiProducts struct {
 	APIProduct []APIProduct `json:"apiProduct,omitempty"`
 }
@@ -119,6 +122,63 @@ type Attribute struct {
 	Value string `json:"value,omitempty"`
 }
 
+// AddOperation adds an operation on an API proxy to the operation group.
+// Operations on the same resource with the same quota share an operation config
+func (o *OperationGroup) AddOperation(apiSource string, resource string, method string, limit string, interval string, timeUnit string) {
+	var q *quota
+	if limit != "" {
+		q = &quota{Limit: limit, Interval: interval, TimeUnit: timeUnit}
+	}
+
+	o.OperationConfigType = "proxy"
+	for index, config := range o.OperationConfigs {
+		if config.APISource != apiSource || len(config.Operations) != 1 ||
+			config.Operations[0].Resource != resource {
+			continue
+		}
+		if (config.Quota == nil && q == nil) || (config.Quota != nil && q != nil && *config.Quota == *q) {
+			o.OperationConfigs[index].Operations[0].Methods = append(config.Operations[0].Methods, method)
+			return
+		}
+	}
+	o.OperationConfigs = append(o.OperationConfigs, operationConfig{
+		APISource:  apiSource,
+		Operations: []operation{{Resource: resource, Methods: []string{method}}},
+		Quota:      q,
+	})
+}
+
+// AddOperations adds operations of a type, QUERY or MUTATION, on an API proxy
+// to the operation group
+func (o *GraphqlOperationGroup) AddOperations(apiSource string, operationType string, operations []string, limit string, interval string, timeUnit string) {
+	config := graphQLOperationConfig{APISource: apiSource}
+	for _, operation := range operations {
+		config.Operations = append(config.Operations, graphQLoperation{
+			OperationTypes: []string{operationType},
+			Operation:      operation,
+		})
+	}
+	if limit != "" {
+		config.Quota = &quota{Limit: limit, Interval: interval, TimeUnit: timeUnit}
+	}
+	o.OperationConfigType = "proxy"
+	o.OperationConfigs = append(o.OperationConfigs, config)
+}
+
+// AddService adds the methods of a gRPC service on an API proxy to the
+// operation group
+func (o *GrpcOperationGroup) AddService(apiSource string, service string, methods []string, limit string, interval string, timeUnit string) {
+	config := grpcOperationConfig{
+		APISource: apiSource,
+		Service:   service,
+		Methods:   methods,
+	}
+	if limit != "" {
+		config.Quota = &quota{Limit: limit, Interval: interval, TimeUnit: timeUnit}
+	}
+	o.OperationConfigs = append(o.OperationConfigs, config)
+}
+
 func Create(p APIProduct) (respBody []byte, err error) {
 	return upsert(p, CREATE)
 }
@@ -239,33 +299,17 @@ func List(count int, startKey string, expand bool) (respBody []byte, err error)
 
 // ListFilter
 func ListFilter(filter map[string]string) (respBody []byte, err error) {
-	maxProducts := 1000
-	nextPage := true
-	startKey := ""
 	allprds := apiProducts{}
 	outprds := apiProducts{}
 
 	apiclient.ClientPrintHttpResponse.Set(false)
 
-	for nextPage {
-		pageResp, err := List(maxProducts, startKey, true)
-		if err != nil {
-			return nil, err
-		}
-		prds := apiProducts{}
-		err = json.Unmarshal(pageResp, &prds)
-		if err != nil {
-			return nil, err
-		}
-
-		startKey = prds.APIProduct[len(prds.APIProduct)-1].Name
-
-		allprds.APIProduct = append(allprds.APIProduct, prds.APIProduct...)
-
-		// if there is only one item in the list, the there are no more products to fetch
-		if len(prds.APIProduct) == 1 {
-			nextPage = false
-		}
+	respBody, err = listAll(true)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(respBody, &allprds); err != nil {
+		return nil, err
 	}
 
 	if filter["proxy"] != "" {
@@ -305,6 +349,56 @@ func ListFilter(filter map[string]string) (respBody []byte, err error) {
 	return respBody, err
 }
 
+// ListAll returns every product in the org, following the pages
+func ListAll(expand bool) (respBody []byte, err error) {
+	apiclient.ClientPrintHttpResponse.Set(false)
+	respBody, err = listAll(expand)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return nil, err
+	}
+	return respBody, apiclient.PrettyPrint(respBody)
+}
+
+// ListNames returns the name of every product in the org
+func ListNames() (names []string, err error) {
+	var respBody []byte
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	respBody, err = listAll(false)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return nil, err
+	}
+
+	entities := apiProducts{}
+	if err = json.Unmarshal(respBody, &entities); err != nil {
+		return nil, err
+	}
+	for _, p := range entities.APIProduct {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+// listAll fetches every page of products
+func listAll(expand bool) (respBody []byte, err error) {
+	var total int
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "apiproducts")
+	q := u.Query()
+	q.Set("expand", strconv.FormatBool(expand))
+	u.RawQuery = q.Encode()
+
+	pager := apiclient.NewPager(u.String(), apiclient.StartKeyPage, maxPageSize, "apiProduct", "name")
+	if respBody, total, err = apiclient.ListAll(pager); err != nil {
+		return nil, err
+	}
+	clilog.Info.Printf("Fetched %d products in %d pages\n", total, pager.Pages())
+	return respBody, nil
+}
+
 // Export
 func Export(conn int) (payload [][]byte, err error) {
 	// parent workgroup
@@ -312,11 +406,9 @@ func Export(conn int) (payload [][]byte, err error) {
 	var mu sync.Mutex
 	const entityType = "apiproducts"
 
-	u, _ := url.Parse(apiclient.BaseURL)
-	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), entityType)
 	// don't print to sysout
 	apiclient.ClientPrintHttpResponse.Set(false)
-	respBody, err := apiclient.HttpClient(u.String())
+	respBody, err := listAll(true)
 	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
 	if err != nil {
 		return apiclient.GetEntityPayloadList(), err
@@ -0,0 +1,330 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+)
+
+// ChartType is the chart used to render a custom report
+type ChartType string
+
+const (
+	ColumnChart ChartType = "column"
+	BarChart    ChartType = "bar"
+	LineChart   ChartType = "line"
+	PieChart    ChartType = "pie"
+	AreaChart   ChartType = "area"
+)
+
+// ChartTypes are the supported chart types
+var ChartTypes = []ChartType{ColumnChart, BarChart, LineChart, PieChart, AreaChart}
+
+// MetricFunctions are the aggregate functions supported for metrics
+var MetricFunctions = []string{"sum", "avg", "min", "max", "count"}
+
+// CustomReports holds a list of custom reports
+type CustomReports struct {
+	Qualifier []CustomReport `json:"qualifier,omitempty"`
+}
+
+// CustomReport is an analytics custom report
+type CustomReport struct {
+	Name           string           `json:"name,omitempty"`
+	DisplayName    string           `json:"displayName,omitempty"`
+	ChartType      ChartType        `json:"chartType,omitempty"`
+	Comments       []string         `json:"comments,omitempty"`
+	Dimensions     []string         `json:"dimensions,omitempty"`
+	Metrics        []Metric         `json:"metrics,omitempty"`
+	Filter         string           `json:"filter,omitempty"`
+	FromTime       string           `json:"fromTime,omitempty"`
+	ToTime         string           `json:"toTime,omitempty"`
+	Limit          string           `json:"limit,omitempty"`
+	Offset         string           `json:"offset,omitempty"`
+	SortByCols     []string         `json:"sortByCols,omitempty"`
+	SortOrder      string           `json:"sortOrder,omitempty"`
+	Tags           []string         `json:"tags,omitempty"`
+	TimeUnit       string           `json:"timeUnit,omitempty"`
+	Topk           string           `json:"topk,omitempty"`
+	Properties     []ReportProperty `json:"properties,omitempty"`
+	Environment    string           `json:"environment,omitempty"`
+	Organization   string           `json:"organization,omitempty"`
+	CreatedAt      string           `json:"createdAt,omitempty"`
+	LastModifiedAt string           `json:"lastModifiedAt,omitempty"`
+	LastViewedAt   string           `json:"lastViewedAt,omitempty"`
+}
+
+// Metric is a metric and the function used to aggregate it
+type Metric struct {
+	Name     string `json:"name,omitempty"`
+	Function string `json:"function,omitempty"`
+}
+
+// ReportProperty holds custom properties of a report
+type ReportProperty struct {
+	Property string      `json:"property,omitempty"`
+	Value    []Attribute `json:"value,omitempty"`
+}
+
+// Attribute is a name value pair
+type Attribute struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+var metricExpr = regexp.MustCompile(`^(\w+)\((\w+)\)$`)
+
+// ParseMetric parses a metric in the form function(name), ex: sum(message_count)
+func ParseMetric(m string) (metric Metric, err error) {
+	match := metricExpr.FindStringSubmatch(strings.TrimSpace(m))
+	if match == nil {
+		return metric, fmt.Errorf("invalid metric %s, must be in the form function(name)", m)
+	}
+	metric = Metric{Function: match[1], Name: match[2]}
+	return metric, metric.Validate()
+}
+
+// Validate checks the metric function is supported
+func (m Metric) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("metric name must be set")
+	}
+	for _, f := range MetricFunctions {
+		if m.Function == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid function %s for metric %s, must be one of %s",
+		m.Function, m.Name, strings.Join(MetricFunctions, ", "))
+}
+
+// Validate checks the chart type is supported
+func (c ChartType) Validate() error {
+	if c == "" {
+		return nil
+	}
+	for _, t := range ChartTypes {
+		if c == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid chart type %s", c)
+}
+
+// Validate checks the mandatory fields of the report
+func (r CustomReport) Validate() (err error) {
+	if r.DisplayName == "" {
+		return fmt.Errorf("displayName must be set")
+	}
+	if len(r.Metrics) == 0 {
+		return fmt.Errorf("at least one metric must be set")
+	}
+	for _, m := range r.Metrics {
+		if err = m.Validate(); err != nil {
+			return err
+		}
+	}
+	return r.ChartType.Validate()
+}
+
+// Create
+func Create(report CustomReport) (respBody []byte, err error) {
+	var payload []byte
+
+	if err = report.Validate(); err != nil {
+		return nil, err
+	}
+	if payload, err = json.Marshal(report); err != nil {
+		return nil, err
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "reports")
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+// Get
+func Get(name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "reports", name)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// GetReport returns the custom report as a typed model
+func GetReport(name string) (report CustomReport, err error) {
+	var respBody []byte
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if respBody, err = Get(name); err != nil {
+		return report, err
+	}
+	err = json.Unmarshal(respBody, &report)
+	return report, err
+}
+
+// Update
+func Update(name string, report CustomReport) (respBody []byte, err error) {
+	var payload []byte
+
+	if err = report.Validate(); err != nil {
+		return nil, err
+	}
+	if payload, err = json.Marshal(report); err != nil {
+		return nil, err
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "reports", name)
+	respBody, err = apiclient.HttpClient(u.String(), string(payload), "PUT")
+	return respBody, err
+}
+
+// Delete
+func Delete(name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "reports", name)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// List
+func List(expand bool) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "reports")
+	if expand {
+		q := u.Query()
+		q.Set("expand", "true")
+		u.RawQuery = q.Encode()
+	}
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// Export returns all the custom reports with their details
+func Export() (respBody []byte, err error) {
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	return List(true)
+}
+
+// Import creates the custom reports in the file. Reports with the same display
+// name as an existing report are updated
+func Import(filePath string) (err error) {
+	var respBody []byte
+	var customReports CustomReports
+
+	if customReports, err = ReadReportsFile(filePath); err != nil {
+		return err
+	}
+
+	if len(customReports.Qualifier) < 1 {
+		return nil
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	if respBody, err = List(true); err != nil {
+		return err
+	}
+	existing := CustomReports{}
+	if err = json.Unmarshal(respBody, &existing); err != nil {
+		return err
+	}
+	names := map[string]string{}
+	for _, r := range existing.Qualifier {
+		names[r.DisplayName] = r.Name
+	}
+
+	for _, report := range customReports.Qualifier {
+		report = clearOutputFields(report)
+		if name, ok := names[report.DisplayName]; ok {
+			clilog.Info.Printf("Updating custom report %s\n", report.DisplayName)
+			_, err = Update(name, report)
+		} else {
+			clilog.Info.Printf("Creating custom report %s\n", report.DisplayName)
+			_, err = Create(report)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadReportFile reads a single custom report from a json file
+func ReadReportFile(filePath string) (report CustomReport, err error) {
+	var byteValue []byte
+
+	if byteValue, err = os.ReadFile(filePath); err != nil {
+		return report, err
+	}
+	if err = json.Unmarshal(byteValue, &report); err != nil {
+		return report, err
+	}
+	return clearOutputFields(report), nil
+}
+
+// ReadReportsFile reads an exported list of custom reports
+func ReadReportsFile(filePath string) (CustomReports, error) {
+	customReports := CustomReports{}
+
+	jsonFile, err := os.Open(filePath)
+	if err != nil {
+		return customReports, err
+	}
+
+	defer jsonFile.Close()
+
+	byteValue, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return customReports, err
+	}
+
+	err = json.Unmarshal(byteValue, &customReports)
+
+	if err != nil {
+		return customReports, err
+	}
+
+	return customReports, nil
+}
+
+// clearOutputFields removes the fields set by the server so a report can be
+// created in another org
+func clearOutputFields(report CustomReport) CustomReport {
+	report.Name = ""
+	report.Organization = ""
+	report.CreatedAt = ""
+	report.LastModifiedAt = ""
+	report.LastViewedAt = ""
+	return report
+}
@@ -0,0 +1,246 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+)
+
+// SecurityActions holds a page of security actions
+type SecurityActions struct {
+	SecurityActions []SecurityAction `json:"securityActions,omitempty"`
+	NextPageToken   string           `json:"nextPageToken,omitempty"`
+}
+
+// SecurityAction allows, denies or flags the traffic matching the conditions
+type SecurityAction struct {
+	Name            string           `json:"name,omitempty"`
+	Description     string           `json:"description,omitempty"`
+	State           string           `json:"state,omitempty"`
+	ConditionConfig *ConditionConfig `json:"conditionConfig,omitempty"`
+	Allow           *struct{}        `json:"allow,omitempty"`
+	Deny            *DenyAction      `json:"deny,omitempty"`
+	Flag            *FlagAction      `json:"flag,omitempty"`
+	APIProxies      []string         `json:"apiProxies,omitempty"`
+	ExpireTime      string           `json:"expireTime,omitempty"`
+	TTL             string           `json:"ttl,omitempty"`
+	CreateTime      string           `json:"createTime,omitempty"`
+	UpdateTime      string           `json:"updateTime,omitempty"`
+}
+
+// ConditionConfig selects the traffic, a request matches when it matches any condition
+type ConditionConfig struct {
+	IPAddressRanges []string `json:"ipAddressRanges,omitempty"`
+	BotReasons      []string `json:"botReasons,omitempty"`
+	APIKeys         []string `json:"apiKeys,omitempty"`
+	DeveloperApps   []string `json:"developerApps,omitempty"`
+	Developers      []string `json:"developers,omitempty"`
+	AccessTokens    []string `json:"accessTokens,omitempty"`
+	UserAgents      []string `json:"userAgents,omitempty"`
+	RegionCodes     []string `json:"regionCodes,omitempty"`
+	ASNs            []string `json:"asns,omitempty"`
+	HTTPMethods     []string `json:"httpMethods,omitempty"`
+	APIProducts     []string `json:"apiProducts,omitempty"`
+}
+
+// DenyAction rejects the request with the response code
+type DenyAction struct {
+	ResponseCode int `json:"responseCode,omitempty"`
+}
+
+// FlagAction adds headers to the request
+type FlagAction struct {
+	Headers []FlagHeader `json:"headers,omitempty"`
+}
+
+// FlagHeader is a header added to flagged requests
+type FlagHeader struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// NewSecurityAction returns an action of type allow, deny or flag. The response
+// code is used by deny actions and the headers by flag actions
+func NewSecurityAction(actionType string, description string, conditions ConditionConfig,
+	responseCode int, headers map[string]string,
+) (action SecurityAction, err error) {
+	action = SecurityAction{
+		Description:     description,
+		State:           "ENABLED",
+		ConditionConfig: &conditions,
+	}
+	switch actionType {
+	case "allow":
+		action.Allow = &struct{}{}
+	case "deny":
+		action.Deny = &DenyAction{ResponseCode: responseCode}
+	case "flag":
+		action.Flag = &FlagAction{}
+		for k, v := range headers {
+			action.Flag.Headers = append(action.Flag.Headers, FlagHeader{Name: k, Value: v})
+		}
+	default:
+		return action, fmt.Errorf("action must be allow, deny or flag")
+	}
+	return action, nil
+}
+
+// CreateAction creates a security action in the environment
+func CreateAction(id string, action SecurityAction) (respBody []byte, err error) {
+	var payload []byte
+
+	// remove the output only fields
+	action.Name, action.CreateTime, action.UpdateTime = "", "", ""
+	if action.State == "" {
+		action.State = "ENABLED"
+	}
+
+	if payload, err = json.Marshal(action); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "securityActions")
+	q := u.Query()
+	q.Set("securityActionId", id)
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+// CreateActionFromFile creates a security action from a json definition
+func CreateActionFromFile(id string, filePath string) (respBody []byte, err error) {
+	action := SecurityAction{}
+	if err = readFile(filePath, &action); err != nil {
+		return nil, err
+	}
+	return CreateAction(id, action)
+}
+
+// GetAction
+func GetAction(id string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "securityActions", id)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// UpdateAction replaces the conditions and the action from a json definition
+func UpdateAction(id string, filePath string) (respBody []byte, err error) {
+	var payload []byte
+
+	action := SecurityAction{}
+	if err = readFile(filePath, &action); err != nil {
+		return nil, err
+	}
+	action.Name, action.CreateTime, action.UpdateTime = "", "", ""
+	if payload, err = json.Marshal(action); err != nil {
+		return nil, err
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "securityActions", id)
+	respBody, err = apiclient.HttpClient(u.String(), string(payload), "PATCH")
+	return respBody, err
+}
+
+// DeleteAction
+func DeleteAction(id string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "securityActions", id)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// ListActions returns the security actions in the environment, filter is ex: state=ENABLED
+func ListActions(pageSize int, pageToken string, filter string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "securityActions")
+	setPage(u, pageSize, pageToken)
+	if filter != "" {
+		q := u.Query()
+		q.Set("filter", filter)
+		u.RawQuery = q.Encode()
+	}
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// EnableAction
+func EnableAction(id string) (respBody []byte, err error) {
+	return setActionState(id, "enable")
+}
+
+// DisableAction
+func DisableAction(id string) (respBody []byte, err error) {
+	return setActionState(id, "disable")
+}
+
+// ExportActions returns every security action in the environment
+func ExportActions() (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "securityActions")
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	pager := apiclient.NewPager(u.String(), apiclient.TokenPage, maxPageSize, "securityActions", "")
+	respBody, _, err = apiclient.ListAll(pager)
+	return respBody, err
+}
+
+// ImportActions creates the security actions in the file in the environment.
+// The id of each action is the last part of its name
+func ImportActions(filePath string) (err error) {
+	var errs []string
+
+	actions := SecurityActions{}
+	if err = readFile(filePath, &actions); err != nil {
+		return err
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	for _, action := range actions.SecurityActions {
+		id := path.Base(action.Name)
+		if _, err = CreateAction(id, action); err != nil {
+			errs = append(errs, fmt.Sprintf("security action %s: %v", id, err))
+			continue
+		}
+		clilog.Info.Printf("Imported security action %s\n", id)
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func setActionState(id string, state string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(),
+		"securityActions", id+":"+state)
+	respBody, err = apiclient.HttpClient(u.String(), "{}")
+	return respBody, err
+}
@@ -0,0 +1,278 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"internal/apiclient"
+
+	"internal/clilog"
+)
+
+// ProfileCategories are the assessment categories of a security profile
+var ProfileCategories = []string{"abuse", "mediation", "authorization", "threat", "mtls", "cors"}
+
+// SecurityProfiles holds a page of security profiles
+type SecurityProfiles struct {
+	SecurityProfiles []SecurityProfile `json:"securityProfiles,omitempty"`
+	NextPageToken    string            `json:"nextPageToken,omitempty"`
+}
+
+// SecurityProfile is an Advanced API Security profile
+type SecurityProfile struct {
+	Name                string               `json:"name,omitempty"`
+	DisplayName         string               `json:"displayName,omitempty"`
+	Description         string               `json:"description,omitempty"`
+	ProfileConfig       *ProfileConfig       `json:"profileConfig,omitempty"`
+	Environments        []profileEnvironment `json:"environments,omitempty"`
+	RevisionID          string               `json:"revisionId,omitempty"`
+	RevisionCreateTime  string               `json:"revisionCreateTime,omitempty"`
+	RevisionUpdateTime  string               `json:"revisionUpdateTime,omitempty"`
+	RevisionPublishTime string               `json:"revisionPublishTime,omitempty"`
+	ScoringConfigs      json.RawMessage      `json:"scoringConfigs,omitempty"`
+	MinScore            int                  `json:"minScore,omitempty"`
+	MaxScore            int                  `json:"maxScore,omitempty"`
+}
+
+// ProfileConfig lists the categories assessed by the profile, ex: {"abuse":{}}
+type ProfileConfig struct {
+	Categories []map[string]json.RawMessage `json:"categories,omitempty"`
+}
+
+type profileEnvironment struct {
+	Environment string `json:"environment,omitempty"`
+	AttachTime  string `json:"attachTime,omitempty"`
+}
+
+// maxPageSize is the largest page the security APIs return
+const maxPageSize = 1000
+
+// CreateProfile creates a security profile that assesses the categories
+func CreateProfile(id string, displayName string, description string, categories []string) (respBody []byte, err error) {
+	profile := SecurityProfile{
+		DisplayName:   displayName,
+		Description:   description,
+		ProfileConfig: &ProfileConfig{},
+	}
+	for _, c := range categories {
+		if !isProfileCategory(c) {
+			return nil, fmt.Errorf("invalid category %s, must be one of %s", c, strings.Join(ProfileCategories, ", "))
+		}
+		profile.ProfileConfig.Categories = append(profile.ProfileConfig.Categories,
+			map[string]json.RawMessage{c: json.RawMessage("{}")})
+	}
+	return createProfile(id, profile)
+}
+
+// CreateProfileFromFile creates a security profile from a json definition
+func CreateProfileFromFile(id string, filePath string) (respBody []byte, err error) {
+	var profile SecurityProfile
+
+	if profile, err = readProfileFile(filePath); err != nil {
+		return nil, err
+	}
+	return createProfile(id, profile)
+}
+
+// GetProfile
+func GetProfile(name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles", name)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// UpdateProfile changes the display name, description and categories of the
+// profile from a json definition
+func UpdateProfile(name string, filePath string) (respBody []byte, err error) {
+	var profile SecurityProfile
+	var payload []byte
+
+	if profile, err = readProfileFile(filePath); err != nil {
+		return nil, err
+	}
+	if payload, err = json.Marshal(profile); err != nil {
+		return nil, err
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles", name)
+	q := u.Query()
+	q.Set("updateMask", "displayName,description,profileConfig")
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String(), string(payload), "PATCH")
+	return respBody, err
+}
+
+// DeleteProfile
+func DeleteProfile(name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles", name)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// ListProfiles
+func ListProfiles(pageSize int, pageToken string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles")
+	setPage(u, pageSize, pageToken)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// ListProfileRevisions
+func ListProfileRevisions(name string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles", name+":listRevisions")
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// AttachProfile attaches the security profile to an environment
+func AttachProfile(name string, environment string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles", name, "environments")
+	respBody, err = apiclient.HttpClient(u.String(), "{\"name\":\""+environment+"\"}")
+	return respBody, err
+}
+
+// DetachProfile detaches the security profile from an environment
+func DetachProfile(name string, environment string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles", name, "environments", environment)
+	respBody, err = apiclient.HttpClient(u.String(), "", "DELETE")
+	return respBody, err
+}
+
+// ExportProfiles returns every security profile in the org
+func ExportProfiles() (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles")
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	pager := apiclient.NewPager(u.String(), apiclient.TokenPage, maxPageSize, "securityProfiles", "")
+	respBody, _, err = apiclient.ListAll(pager)
+	return respBody, err
+}
+
+// ImportProfiles creates the security profiles in the file and attaches them to
+// the same environments. Profiles provided by Google are skipped
+func ImportProfiles(filePath string) (err error) {
+	var errs []string
+
+	profiles := SecurityProfiles{}
+	if err = readFile(filePath, &profiles); err != nil {
+		return err
+	}
+
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	for _, profile := range profiles.SecurityProfiles {
+		id := path.Base(profile.Name)
+		if strings.HasPrefix(id, "google-") {
+			clilog.Debug.Printf("Skipping security profile %s\n", id)
+			continue
+		}
+		environments := profile.Environments
+		if _, err = createProfile(id, profile); err != nil {
+			errs = append(errs, fmt.Sprintf("security profile %s: %v", id, err))
+			continue
+		}
+		for _, e := range environments {
+			if _, err = AttachProfile(id, e.Environment); err != nil {
+				errs = append(errs, fmt.Sprintf("security profile %s environment %s: %v", id, e.Environment, err))
+			}
+		}
+		clilog.Info.Printf("Imported security profile %s\n", id)
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func createProfile(id string, profile SecurityProfile) (respBody []byte, err error) {
+	var payload []byte
+
+	// remove the output only fields
+	profile.Name, profile.Environments, profile.RevisionID = "", nil, ""
+	profile.RevisionCreateTime, profile.RevisionUpdateTime, profile.RevisionPublishTime = "", "", ""
+	profile.MinScore, profile.MaxScore = 0, 0
+
+	if payload, err = json.Marshal(profile); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles")
+	q := u.Query()
+	q.Set("securityProfileId", id)
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+func readProfileFile(filePath string) (profile SecurityProfile, err error) {
+	err = readFile(filePath, &profile)
+	return profile, err
+}
+
+func isProfileCategory(category string) bool {
+	for _, c := range ProfileCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func setPage(u *url.URL, pageSize int, pageToken string) {
+	q := u.Query()
+	if pageSize != -1 {
+		q.Set("pageSize", strconv.Itoa(pageSize))
+	}
+	if pageToken != "" {
+		q.Set("pageToken", pageToken)
+	}
+	u.RawQuery = q.Encode()
+}
+
+func readFile(filePath string, v interface{}) (err error) {
+	var byteValue []byte
+
+	jsonFile, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer jsonFile.Close()
+
+	if byteValue, err = io.ReadAll(jsonFile); err != nil {
+		return err
+	}
+	return json.Unmarshal(byteValue, v)
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"io"
+	"net/url"
+	"path"
+
+	"internal/apiclient"
+
+	"internal/client/env"
+)
+
+// CreateReport submits an asynchronous security report. The query definition
+// is read from a json or yaml file
+func CreateReport(queryFile string) (respBody []byte, err error) {
+	var payload []byte
+
+	if payload, err = env.ReadQueryFile(queryFile); err != nil {
+		return nil, err
+	}
+
+	// throttle API Calls
+	getDefaultRate := apiclient.GetRate()
+	apiclient.SetRate(apiclient.ApigeeAnalyticsAPI)
+	defer apiclient.SetRate(getDefaultRate)
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "securityReports")
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+// GetReport returns the status of a security report
+func GetReport(id string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "securityReports", id)
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// ListReports returns the security reports in the environment
+func ListReports(submittedBy string, status string, from string, to string, pageSize int, pageToken string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "securityReports")
+	setPage(u, pageSize, pageToken)
+	q := u.Query()
+	if submittedBy != "" {
+		q.Set("submittedBy", submittedBy)
+	}
+	if status != "" {
+		q.Set("status", status)
+	}
+	if from != "" {
+		q.Set("from", from)
+	}
+	if to != "" {
+		q.Set("to", to)
+	}
+	u.RawQuery = q.Encode()
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// GetReportResultView returns the rows of a completed security report
+func GetReportResultView(id string) (respBody []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(),
+		"securityReports", id, "resultView")
+	respBody, err = apiclient.HttpClient(u.String())
+	return respBody, err
+}
+
+// GetReportResult downloads the zip file of a completed security report
+func GetReportResult(id string) (data []byte, err error) {
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(),
+		"securityReports", id, "result")
+
+	resp, err := apiclient.DownloadFile(u.String(), true)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
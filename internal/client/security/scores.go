@@ -0,0 +1,151 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal/apiclient"
+)
+
+// EnvironmentScore is the security score of an environment in a time range
+type EnvironmentScore struct {
+	TimeRange timeRange      `json:"timeRange,omitempty"`
+	Component ScoreComponent `json:"component,omitempty"`
+}
+
+// ScoreComponent is the score of a category, ex: /org@myorg/envs@test/source@abuse.
+// The score of the environment is the sum of its subcomponents
+type ScoreComponent struct {
+	ScorePath       string                `json:"scorePath,omitempty"`
+	Score           int                   `json:"score"`
+	CalculateTime   string                `json:"calculateTime,omitempty"`
+	DataCaptureTime string                `json:"dataCaptureTime,omitempty"`
+	Subcomponents   []ScoreComponent      `json:"subcomponents,omitempty"`
+	Recommendations []ScoreRecommendation `json:"recommendations,omitempty"`
+	DrilldownPaths  []string              `json:"drilldownPaths,omitempty"`
+}
+
+// ScoreRecommendation is a change that improves the score
+type ScoreRecommendation struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Impact      int    `json:"impact,omitempty"`
+}
+
+type timeRange struct {
+	StartTime string `json:"startTime,omitempty"`
+	EndTime   string `json:"endTime,omitempty"`
+}
+
+type computeScoresRequest struct {
+	TimeRange timeRange     `json:"timeRange"`
+	Filters   []scoreFilter `json:"filters,omitempty"`
+	PageSize  int           `json:"pageSize,omitempty"`
+}
+
+type scoreFilter struct {
+	ScorePath string `json:"scorePath,omitempty"`
+}
+
+type scoresResponse struct {
+	Scores        []EnvironmentScore `json:"scores,omitempty"`
+	NextPageToken string             `json:"nextPageToken,omitempty"`
+}
+
+// ComputeScores returns the scores of the environment assessed with the security
+// profile. scorePaths limits the scores to the components, ex: /org@myorg/envs@test/source@abuse
+func ComputeScores(profile string, environment string, start time.Time, end time.Time, scorePaths []string) (respBody []byte, err error) {
+	var payload []byte
+
+	if !end.After(start) {
+		return nil, fmt.Errorf("the end time must be after the start time")
+	}
+
+	request := computeScoresRequest{
+		TimeRange: timeRange{
+			StartTime: start.UTC().Format(time.RFC3339),
+			EndTime:   end.UTC().Format(time.RFC3339),
+		},
+	}
+	for _, p := range scorePaths {
+		request.Filters = append(request.Filters, scoreFilter{ScorePath: p})
+	}
+	if payload, err = json.Marshal(request); err != nil {
+		return nil, err
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "securityProfiles", profile,
+		"environments", environment+":computeEnvironmentScores")
+	respBody, err = apiclient.HttpClient(u.String(), string(payload))
+	return respBody, err
+}
+
+// ParseScores returns the scores in a compute scores response
+func ParseScores(respBody []byte) (scores []EnvironmentScore, err error) {
+	resp := scoresResponse{}
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Scores, nil
+}
+
+// ScoreBreakdown returns a row per component, subcomponents are indented under
+// their parent. The top recommendations of each component are listed by impact
+func ScoreBreakdown(scores []EnvironmentScore) (header []string, rows [][]string) {
+	header = []string{"time range", "component", "score", "recommendations"}
+	for _, s := range scores {
+		period := s.TimeRange.StartTime + " - " + s.TimeRange.EndTime
+		rows = appendComponent(rows, period, s.Component, 0)
+	}
+	return header, rows
+}
+
+func appendComponent(rows [][]string, period string, c ScoreComponent, depth int) [][]string {
+	var recommendations []string
+	for _, r := range c.Recommendations {
+		title := r.Title
+		if r.Impact > 0 {
+			title += " (+" + strconv.Itoa(r.Impact) + ")"
+		}
+		recommendations = append(recommendations, title)
+	}
+
+	rows = append(rows, []string{
+		period,
+		strings.Repeat("  ", depth) + componentName(c.ScorePath),
+		strconv.Itoa(c.Score),
+		strings.Join(recommendations, "; "),
+	})
+	for _, sub := range c.Subcomponents {
+		rows = appendComponent(rows, period, sub, depth+1)
+	}
+	return rows
+}
+
+// componentName returns the last element of the score path, ex: source@abuse
+func componentName(scorePath string) string {
+	if i := strings.LastIndex(scorePath, "/"); i >= 0 && i < len(scorePath)-1 {
+		return scorePath[i+1:]
+	}
+	return scorePath
+}
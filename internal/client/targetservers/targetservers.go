@@ -123,6 +123,71 @@ func createOrUpdate(action string, targetsvr targetserver, name string, descript
 	return respBody, err
 }
 
+// UpdateKeystore changes the keystore and key alias used for TLS by a target server
+func UpdateKeystore(name string, keyStore string, keyAlias string) (respBody []byte, err error) {
+	apiclient.ClientPrintHttpResponse.Set(false)
+	defer apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+
+	targetRespBody, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	targetsvr := targetserver{}
+	if err = json.Unmarshal(targetRespBody, &targetsvr); err != nil {
+		return nil, err
+	}
+
+	if targetsvr.SslInfo == nil {
+		return nil, fmt.Errorf("target server %s does not have TLS configured", name)
+	}
+
+	targetsvr.SslInfo.Keystore = keyStore
+	targetsvr.SslInfo.Keyalias = keyAlias
+
+	reqBody, err := json.Marshal(targetsvr)
+	if err != nil {
+		return nil, err
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "targetservers", name)
+	return apiclient.HttpClient(u.String(), string(reqBody), "PUT")
+}
+
+// UpdateHost changes the host, port and TLS enabled flag of a target server,
+// keeping the rest of its configuration
+func UpdateHost(name string, host string, port int, tlsenabled bool) (respBody []byte, err error) {
+	apiclient.ClientPrintHttpResponse.Set(false)
+	targetRespBody, err := Get(name)
+	apiclient.ClientPrintHttpResponse.Set(apiclient.GetCmdPrintHttpResponseSetting())
+	if err != nil {
+		return nil, err
+	}
+
+	targetsvr := targetserver{}
+	if err = json.Unmarshal(targetRespBody, &targetsvr); err != nil {
+		return nil, err
+	}
+
+	targetsvr.Host = host
+	targetsvr.Port = port
+	if targetsvr.SslInfo != nil {
+		targetsvr.SslInfo.Enabled = tlsenabled
+	} else if tlsenabled {
+		targetsvr.SslInfo = &sslInfo{Enabled: true}
+	}
+
+	reqBody, err := json.Marshal(targetsvr)
+	if err != nil {
+		return nil, err
+	}
+
+	u, _ := url.Parse(apiclient.BaseURL)
+	u.Path = path.Join(u.Path, apiclient.GetApigeeOrg(), "environments", apiclient.GetApigeeEnv(), "targetservers", name)
+	return apiclient.HttpClient(u.String(), string(reqBody), "PUT")
+}
+
 // Get
 func Get(name string) (respBody []byte, err error) {
 	u, _ := url.Parse(apiclient.BaseURL)